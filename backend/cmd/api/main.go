@@ -8,7 +8,9 @@ import (
 	"github.com/ChukwukaRosemary23/flowboard-backend/config"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/handlers"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/jobs"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/routes"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
 	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -25,14 +27,48 @@ func main() {
 
 	database.SeedRolesAndPermissions()
 
+	// Convert legacy integer positions to rank keys (no-op once done)
+	ordering := &services.OrderingService{}
+	if err := ordering.ConvertLegacyPositions(database.DB); err != nil {
+		log.Fatal("Failed to convert positions to ranks:", err)
+	}
+
 	// Create WebSocket hub
 	hub := ws.NewHub()
+	eventLog := &services.EventLogService{DB: database.DB}
+	hub.Events = eventLog
+	hub.Commands = handlers.WSCommands{}
+	if cfg.PubSub == "postgres" {
+		hub.PubSub = &services.PubSubService{DB: database.DB, Events: eventLog}
+		log.Println("🔌 WebSocket broadcasts shared through PostgreSQL")
+	}
 	go hub.Run()
 	log.Println("🔌 WebSocket hub started")
 
 	// Set global hub for handlers
 	handlers.WSHub = hub
 
+	// Start trash purge job
+	handlers.TrashRetention = time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+	jobs.StartTrashPurger(handlers.TrashRetention, time.Hour)
+	log.Printf("🗑️ Trash purger started (retention: %d days)", cfg.TrashRetentionDays)
+
+	// Public URLs for feed links
+	handlers.APIBaseURL = cfg.APIBaseURL
+	handlers.FrontendURL = cfg.FrontendURL
+
+	// Start rank rebalancer
+	jobs.StartRankRebalancer(10 * time.Minute)
+
+	// Start recurring card scheduler
+	jobs.StartRecurrenceScheduler(hub, time.Minute)
+	log.Println("🔁 Recurring card scheduler started")
+
+	// Start automation rules
+	jobs.StartAutomationRunner(hub)
+	jobs.StartAutomationScheduler(hub, time.Minute)
+	log.Println("🤖 Automation runner started")
+
 	// Set Gin mode based on environment
 	if cfg.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -99,6 +135,11 @@ func main() {
 	log.Println("   Auth:")
 	log.Println("     POST   /api/v1/auth/register         - Register new user")
 	log.Println("     POST   /api/v1/auth/login            - Login user")
+	log.Println("   Trash:")
+	log.Println("     GET    /api/v1/boards/:id/trash      - List deleted items")
+	log.Println("   Saved filters:")
+	log.Println("     GET    /api/v1/dashboard             - Saved filter panels")
+	log.Println("     GET    /api/v1/feeds/filters/:token  - Saved filter RSS feed")
 	log.Println("   Boards, Lists, Cards, Comments, Labels, etc...")
 
 	if err := router.Run(serverAddr); err != nil {
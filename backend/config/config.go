@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +18,17 @@ type Config struct {
 	Port       string
 	JWTSecret  string
 	Env        string
+
+	// Days a deleted card, list, comment or attachment stays restorable
+	TrashRetentionDays int
+
+	// Public URLs used to build links in feeds
+	APIBaseURL  string
+	FrontendURL string
+
+	// How board broadcasts reach WebSocket clients: "memory" for one
+	// instance, "postgres" to share them between instances with LISTEN/NOTIFY
+	PubSub string
 }
 
 // LoadConfig loads configuration from environment variables
@@ -36,6 +48,13 @@ func LoadConfig() *Config {
 		Port:       getEnv("PORT", "8080"),
 		JWTSecret:  getEnv("JWT_SECRET", "change-me-in-production"),
 		Env:        getEnv("ENV", "development"),
+
+		TrashRetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+
+		APIBaseURL:  getEnv("API_BASE_URL", "http://localhost:"+getEnv("PORT", "8080")),
+		FrontendURL: getEnv("FRONTEND_URL", ""),
+
+		PubSub: getEnv("PUBSUB", "memory"),
 	}
 }
 
@@ -46,3 +65,14 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt gets an integer environment variable with fallback default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %d", key, defaultValue)
+	}
+	return defaultValue
+}
@@ -0,0 +1,27 @@
+package database
+
+import "log"
+
+// convertActivityMetadata turns the activities.metadata column from a JSON
+// string into jsonb, moving each old flat metadata object under "details"
+// so it reads as models.ActivityMetadata. It runs before AutoMigrate, which
+// cannot cast the empty strings old rows hold, and does nothing once the
+// column is converted.
+func convertActivityMetadata() error {
+	var dataType string
+	if err := DB.Raw(`SELECT data_type FROM information_schema.columns
+		WHERE table_schema = CURRENT_SCHEMA() AND table_name = 'activities' AND column_name = 'metadata'`).Scan(&dataType).Error; err != nil {
+		return err
+	}
+	if dataType != "text" {
+		return nil
+	}
+
+	if err := DB.Exec(`ALTER TABLE activities ALTER COLUMN metadata TYPE jsonb USING
+		CASE WHEN metadata IS NULL OR metadata = '' THEN NULL ELSE jsonb_build_object('details', metadata::jsonb) END`).Error; err != nil {
+		return err
+	}
+
+	log.Println("✅ Activity metadata converted to jsonb")
+	return nil
+}
@@ -65,6 +65,10 @@ func ConnectDatabase(cfg *config.Config) error {
 func runMigrations() error {
 	log.Println("🔄 Running database migrations...")
 
+	if err := convertActivityMetadata(); err != nil {
+		return err
+	}
+
 	err := DB.AutoMigrate(
 		&models.User{},
 		&models.Board{},
@@ -75,13 +79,42 @@ func runMigrations() error {
 		&models.Attachment{},
 		&models.CardMember{},
 		&models.CardLabel{},
+		&models.CardLabelSpan{},
 		&models.Activity{},
+		&models.SavedFilter{},
+		&models.CustomField{},
+		&models.CardFieldValue{},
+		&models.CardRelation{},
+		&models.CardRecurrence{},
+		&models.AutomationRule{},
+		&models.AutomationRun{},
+		&models.Lane{},
+		&models.TimeEntry{},
+		&models.CardTransition{},
+		&models.Sprint{},
+		&models.SprintCard{},
+		&models.SprintCardSpan{},
+		&models.CalendarFeed{},
+		&models.CardRevision{},
+		&models.BoardEvent{},
 	)
 
 	if err != nil {
 		return err
 	}
 
+	if err := ensureSearchIndexes(); err != nil {
+		return err
+	}
+
+	if err := backfillCardTransitions(); err != nil {
+		return err
+	}
+
+	if err := ensureScopeHistory(); err != nil {
+		return err
+	}
+
 	log.Println("✅ Database migrations completed successfully!")
 	return nil
 }
@@ -0,0 +1,78 @@
+package database
+
+import "log"
+
+// scopeHistory lists the membership tables whose rows are kept as spans:
+// each insert opens a span and each delete closes it
+var scopeHistory = []struct {
+	table   string // Membership table
+	spans   string // Span table
+	owner   string // Column naming what the card is a member of
+	trigger string
+}{
+	{table: "card_labels", spans: "card_label_spans", owner: "label_id", trigger: "record_card_label_span"},
+	{table: "sprint_cards", spans: "sprint_card_spans", owner: "sprint_id", trigger: "record_sprint_card_span"},
+}
+
+// ensureScopeHistory installs the triggers that record sprint membership
+// spans, after opening a span for every membership that has none. Labels
+// added before spans were recorded start at their latest added_label
+// activity, or when the card was created if there is none. Explicit sprint
+// cards have always had their join time.
+func ensureScopeHistory() error {
+	labels := DB.Exec(`INSERT INTO card_label_spans (card_id, label_id, added_at)
+		SELECT card_labels.card_id, card_labels.label_id, COALESCE(
+			(SELECT MAX(activities.created_at) FROM activities
+			WHERE activities.action = 'added_label' AND activities.entity_type = 'card'
+				AND activities.entity_id = card_labels.card_id
+				AND activities.metadata->'details'->>'label_id' = card_labels.label_id::text),
+			cards.created_at)
+		FROM card_labels JOIN cards ON cards.id = card_labels.card_id
+		WHERE NOT EXISTS (SELECT 1 FROM card_label_spans
+			WHERE card_label_spans.card_id = card_labels.card_id
+				AND card_label_spans.label_id = card_labels.label_id
+				AND card_label_spans.removed_at IS NULL)`)
+	if labels.Error != nil {
+		return labels.Error
+	}
+
+	sprints := DB.Exec(`INSERT INTO sprint_card_spans (sprint_id, card_id, added_at)
+		SELECT sprint_id, card_id, created_at FROM sprint_cards
+		WHERE NOT EXISTS (SELECT 1 FROM sprint_card_spans
+			WHERE sprint_card_spans.sprint_id = sprint_cards.sprint_id
+				AND sprint_card_spans.card_id = sprint_cards.card_id
+				AND sprint_card_spans.removed_at IS NULL)`)
+	if sprints.Error != nil {
+		return sprints.Error
+	}
+
+	for _, history := range scopeHistory {
+		if err := DB.Exec(`CREATE OR REPLACE FUNCTION ` + history.trigger + `() RETURNS trigger AS $$
+			BEGIN
+				IF TG_OP = 'INSERT' THEN
+					INSERT INTO ` + history.spans + ` (card_id, ` + history.owner + `, added_at)
+					VALUES (NEW.card_id, NEW.` + history.owner + `, NOW());
+					RETURN NEW;
+				END IF;
+				UPDATE ` + history.spans + ` SET removed_at = NOW()
+				WHERE card_id = OLD.card_id AND ` + history.owner + ` = OLD.` + history.owner + ` AND removed_at IS NULL;
+				RETURN OLD;
+			END
+			$$ LANGUAGE plpgsql`).Error; err != nil {
+			return err
+		}
+
+		if err := DB.Exec(`DROP TRIGGER IF EXISTS ` + history.trigger + ` ON ` + history.table).Error; err != nil {
+			return err
+		}
+		if err := DB.Exec(`CREATE TRIGGER ` + history.trigger + ` AFTER INSERT OR DELETE ON ` + history.table +
+			` FOR EACH ROW EXECUTE FUNCTION ` + history.trigger + `()`).Error; err != nil {
+			return err
+		}
+	}
+
+	if total := labels.RowsAffected + sprints.RowsAffected; total > 0 {
+		log.Printf("✅ Sprint membership spans backfilled for %d memberships", total)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package database
+
+import "log"
+
+// searchColumns are generated tsvector columns kept up to date by Postgres.
+// Titles weigh more than descriptions, which weigh more than comments and
+// file names. Filenames are split on punctuation so "q3-report.pdf" matches "report".
+var searchColumns = []struct {
+	table      string
+	expression string
+}{
+	{"cards", "setweight(to_tsvector('english', coalesce(title, '')), 'A') || " +
+		"setweight(to_tsvector('english', coalesce(description, '')), 'B')"},
+	{"comments", "setweight(to_tsvector('english', coalesce(content, '')), 'C')"},
+	{"attachments", "setweight(to_tsvector('english', regexp_replace(coalesce(filename, ''), '[[:punct:]]', ' ', 'g')), 'C')"},
+}
+
+// ensureSearchIndexes adds the full-text search columns and their GIN indexes.
+// AutoMigrate cannot express generated columns, so this runs as raw DDL.
+func ensureSearchIndexes() error {
+	for _, column := range searchColumns {
+		if err := DB.Exec("ALTER TABLE " + column.table +
+			" ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (" +
+			column.expression + ") STORED").Error; err != nil {
+			return err
+		}
+
+		if err := DB.Exec("CREATE INDEX IF NOT EXISTS idx_" + column.table + "_search_vector ON " +
+			column.table + " USING GIN (search_vector)").Error; err != nil {
+			return err
+		}
+	}
+
+	log.Println("✅ Search indexes ready")
+	return nil
+}
@@ -0,0 +1,21 @@
+package database
+
+import "log"
+
+// backfillCardTransitions gives cards created before transitions were
+// recorded a single transition into their current list at their creation
+// time, so flow analytics include them. It only touches cards with none.
+func backfillCardTransitions() error {
+	result := DB.Exec(`INSERT INTO card_transitions (card_id, board_id, to_list_id, moved_at)
+		SELECT cards.id, lists.board_id, cards.list_id, cards.created_at
+		FROM cards JOIN lists ON lists.id = cards.list_id
+		WHERE NOT EXISTS (SELECT 1 FROM card_transitions WHERE card_transitions.card_id = cards.id)`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Printf("✅ Card transitions backfilled for %d cards", result.RowsAffected)
+	}
+	return nil
+}
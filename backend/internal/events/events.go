@@ -0,0 +1,42 @@
+// Package events passes board events to in-process subscribers such as the
+// automation runner
+package events
+
+import "sync"
+
+// Sources of events
+const (
+	SourceBroadcast = "broadcast" // Sent to WebSocket clients with Hub.BroadcastToBoard
+	SourceActivity  = "activity"  // Written to the activity log with utils.LogActivity
+)
+
+// Event is something that happened on a board
+type Event struct {
+	BoardID uint
+	Source  string
+	Type    string      // WebSocket message type or activity action
+	Data    interface{} // Payload as sent to clients or the activity's details
+	Chain   []uint      // Automation rules whose actions led to this event, first rule first
+}
+
+var (
+	mu          sync.RWMutex
+	subscribers []func(Event)
+)
+
+// Subscribe registers fn to be called with every published event. fn runs
+// on the publisher's goroutine and must not block for long.
+func Subscribe(fn func(Event)) {
+	mu.Lock()
+	defer mu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// Publish hands event to every subscriber
+func Publish(event Event) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
@@ -2,45 +2,72 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // ActivityResponse represents activity data
 type ActivityResponse struct {
-	ID          uint         `json:"id"`
-	Action      string       `json:"action"`
-	EntityType  string       `json:"entity_type"`
-	EntityID    uint         `json:"entity_id"`
-	EntityTitle string       `json:"entity_title"`
-	BoardID     uint         `json:"board_id"`
-	User        UserResponse `json:"user"`
-	Metadata    string       `json:"metadata"`
-	CreatedAt   time.Time    `json:"created_at"`
+	ID          uint                    `json:"id"`
+	Action      string                  `json:"action"`
+	EntityType  string                  `json:"entity_type"`
+	EntityID    uint                    `json:"entity_id"`
+	EntityTitle string                  `json:"entity_title"`
+	BoardID     uint                    `json:"board_id"`
+	User        UserResponse            `json:"user"`
+	Metadata    models.ActivityMetadata `json:"metadata"`
+	CreatedAt   time.Time               `json:"created_at"`
 }
 
-// GetBoardActivities returns recent activities for a board
+// activityPaginator pages the activity feeds, newest first by default
+var activityPaginator = &Paginator[models.Activity]{
+	Sorts: map[string]SortKey[models.Activity]{
+		"created_at": {Column: "activities.created_at", Value: func(a models.Activity) interface{} { return a.CreatedAt }},
+	},
+	DefaultSort:  "-created_at",
+	IDColumn:     "activities.id",
+	ID:           func(a models.Activity) uint { return a.ID },
+	DefaultLimit: 50,
+	MaxLimit:     200,
+}
+
+// GetBoardActivities returns a board's activities, one page at a time. They
+// can be filtered as described at filterActivities.
 func GetBoardActivities(c *gin.Context) {
-	boardID := c.Param("board_id")
+	boardID, err := strconv.ParseUint(c.Param("board_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
 	userID := c.GetUint("user_id")
 
-	// Verify board exists and user owns it
-	var board models.Board
-	if err := database.DB.Where("id = ? AND owner_id = ?", boardID, userID).First(&board).Error; err != nil {
+	// Verify board exists and user can view it
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, uint(boardID), "view_board") {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Board not found or access denied"})
 		return
 	}
 
+	page, ok := activityPaginator.Parse(c)
+	if !ok {
+		return
+	}
+	query, ok := filterActivities(c, database.DB.Where("activities.board_id = ?", boardID))
+	if !ok {
+		return
+	}
+
 	// Get activities 
 	var activities []models.Activity
-	if err := database.DB.Where("board_id = ?", boardID).
-		Preload("User").
-		Order("created_at DESC").
-		Limit(50).
-		Find(&activities).Error; err != nil {
+	hasMore, err := page.Find(query.Preload("User"), &activities)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activities"})
 		return
 	}
@@ -66,25 +93,28 @@ func GetBoardActivities(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"activities": response,
-		"count":      len(response),
-	})
+	page.Respond(c, "activities", activities, response, hasMore)
 }
 
-// GetUserActivities returns recent activities by a user
+// GetUserActivities returns activities on the user's boards, one page at a
+// time. They can be filtered as described at filterActivities.
 func GetUserActivities(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
+	page, ok := activityPaginator.Parse(c)
+	if !ok {
+		return
+	}
+	query, ok := filterActivities(c, services.MemberBoards(database.DB.
+		Joins("JOIN boards ON boards.id = activities.board_id"), userID))
+	if !ok {
+		return
+	}
+
 	// Get user's activities across all their boards
 	var activities []models.Activity
-	if err := database.DB.
-		Joins("JOIN boards ON boards.id = activities.board_id").
-		Where("boards.owner_id = ?", userID).
-		Preload("User").
-		Order("activities.created_at DESC").
-		Limit(50).
-		Find(&activities).Error; err != nil {
+	hasMore, err := page.Find(query.Preload("User"), &activities)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activities"})
 		return
 	}
@@ -110,8 +140,58 @@ func GetUserActivities(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"activities": response,
-		"count":      len(response),
-	})
+	page.Respond(c, "activities", activities, response, hasMore)
+}
+
+// filterActivities narrows an activity query by the optional query
+// parameters entity_type, entity_id, user_id, action (comma separated) and
+// from and to (YYYY-MM-DD, inclusive, UTC). On failure it responds and
+// returns false.
+func filterActivities(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if entityType := c.Query("entity_type"); entityType != "" {
+		query = query.Where("activities.entity_type = ?", entityType)
+	}
+	if value := c.Query("entity_id"); value != "" {
+		entityID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity_id"})
+			return nil, false
+		}
+		query = query.Where("activities.entity_id = ?", entityID)
+	}
+	if value := c.Query("user_id"); value != "" {
+		actorID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return nil, false
+		}
+		query = query.Where("activities.user_id = ?", actorID)
+	}
+	if value := c.Query("action"); value != "" {
+		var actions []string
+		for _, action := range strings.Split(value, ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				actions = append(actions, action)
+			}
+		}
+		if len(actions) > 0 {
+			query = query.Where("activities.action IN ?", actions)
+		}
+	}
+
+	if c.Query("from") != "" {
+		from, ok := queryDate(c, "from", time.Time{})
+		if !ok {
+			return nil, false
+		}
+		query = query.Where("activities.created_at >= ?", from)
+	}
+	if c.Query("to") != "" {
+		to, ok := queryDate(c, "to", time.Time{})
+		if !ok {
+			return nil, false
+		}
+		query = query.Where("activities.created_at < ?", to.AddDate(0, 0, 1))
+	}
+	return query, true
 }
@@ -0,0 +1,28 @@
+package handlers
+
+import "time"
+
+// ThroughputWeekResponse is how many cards were finished in a week
+type ThroughputWeekResponse struct {
+	WeekStart string `json:"week_start"` // Monday, YYYY-MM-DD
+	Cards     int    `json:"cards"`
+}
+
+// CumulativeFlowSeries is one list's card count on each day of a cumulative
+// flow diagram
+type CumulativeFlowSeries struct {
+	ListID uint   `json:"list_id"`
+	Title  string `json:"title"`
+	IsDone bool   `json:"is_done"`
+	Counts []int  `json:"counts"` // One per date, in the order of dates
+}
+
+// CardTransitionResponse is one list a card entered
+type CardTransitionResponse struct {
+	ID         uint      `json:"id"`
+	FromListID *uint     `json:"from_list_id"` // Nil when the card was created
+	ToListID   uint      `json:"to_list_id"`
+	BoardID    uint      `json:"board_id"`
+	UserID     *uint     `json:"user_id"`
+	MovedAt    time.Time `json:"moved_at"`
+}
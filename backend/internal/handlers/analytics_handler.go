@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// Analytics date range limits
+const (
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 366
+)
+
+// GetCycleTimes reports lead and cycle time for the cards a board finished
+// between from and to, per card, per label and overall
+func GetCycleTimes(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	dates, ok := parseDateRange(c, analyticsDefaultDays, analyticsMaxDays)
+	if !ok {
+		return
+	}
+
+	analyticsService := &services.AnalyticsService{}
+	summary, err := analyticsService.FlowSummary(database.DB, uint(boardID), dates.From, dates.End())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute cycle times"})
+		return
+	}
+	cards, err := analyticsService.CardFlowTimes(database.DB, uint(boardID), dates.From, dates.End())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute cycle times"})
+		return
+	}
+	labels, err := analyticsService.LabelFlowStats(database.DB, uint(boardID), dates.From, dates.End())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute cycle times"})
+		return
+	}
+	if cards == nil {
+		cards = []services.CardFlowTime{}
+	}
+	if labels == nil {
+		labels = []services.LabelFlowStats{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board_id": boardID,
+		"from":     dates.From.Format("2006-01-02"),
+		"to":       dates.To.Format("2006-01-02"),
+		"summary":  summary,
+		"cards":    cards,
+		"labels":   labels,
+	})
+}
+
+// GetThroughput reports how many cards a board finished in each week (Monday
+// to Sunday) overlapping from and to
+func GetThroughput(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	dates, ok := parseDateRange(c, analyticsDefaultDays, analyticsMaxDays)
+	if !ok {
+		return
+	}
+
+	analyticsService := &services.AnalyticsService{}
+	weeks, err := analyticsService.Throughput(database.DB, uint(boardID), dates.From, dates.End())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute throughput"})
+		return
+	}
+
+	response := make([]ThroughputWeekResponse, len(weeks))
+	for i, week := range weeks {
+		response[i] = ThroughputWeekResponse{
+			WeekStart: week.WeekStart.Format("2006-01-02"),
+			Cards:     week.Cards,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board_id": boardID,
+		"from":     dates.From.Format("2006-01-02"),
+		"to":       dates.To.Format("2006-01-02"),
+		"weeks":    response,
+	})
+}
+
+// GetCumulativeFlow returns cumulative flow diagram data: for each of the
+// board's lists, in board order, its card count at the end of every day
+// between from and to
+func GetCumulativeFlow(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	dates, ok := parseDateRange(c, analyticsDefaultDays, analyticsMaxDays)
+	if !ok {
+		return
+	}
+
+	var lists []models.List
+	if err := database.DB.Where("board_id = ?", boardID).Order("rank ASC, id ASC").Find(&lists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lists"})
+		return
+	}
+
+	analyticsService := &services.AnalyticsService{}
+	counts, err := analyticsService.CumulativeFlow(database.DB, uint(boardID), dates.From, dates.End())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute cumulative flow"})
+		return
+	}
+
+	var days []string
+	dayIndex := make(map[string]int)
+	for day := dates.From; day.Before(dates.End()); day = day.AddDate(0, 0, 1) {
+		dayIndex[day.Format("2006-01-02")] = len(days)
+		days = append(days, day.Format("2006-01-02"))
+	}
+
+	series := make([]CumulativeFlowSeries, len(lists))
+	listIndex := make(map[uint]int, len(lists))
+	for i, list := range lists {
+		listIndex[list.ID] = i
+		series[i] = CumulativeFlowSeries{
+			ListID: list.ID,
+			Title:  list.Title,
+			IsDone: list.IsDone,
+			Counts: make([]int, len(days)),
+		}
+	}
+	// Lists in the trash are left out
+	for _, count := range counts {
+		i, ok := listIndex[count.ListID]
+		if !ok {
+			continue
+		}
+		series[i].Counts[dayIndex[count.Day.Format("2006-01-02")]] = count.Cards
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board_id": boardID,
+		"from":     dates.From.Format("2006-01-02"),
+		"to":       dates.To.Format("2006-01-02"),
+		"dates":    days,
+		"lists":    series,
+	})
+}
+
+// GetCardTransitions returns the lists a card has entered, oldest first
+func GetCardTransitions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "view_board")
+	if !ok {
+		return
+	}
+
+	var transitions []models.CardTransition
+	if err := database.DB.Where("card_id = ?", card.ID).Order("moved_at ASC, id ASC").Find(&transitions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch card history"})
+		return
+	}
+
+	response := make([]CardTransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		response[i] = CardTransitionResponse{
+			ID:         transition.ID,
+			FromListID: transition.FromListID,
+			ToListID:   transition.ToListID,
+			BoardID:    transition.BoardID,
+			UserID:     transition.UserID,
+			MovedAt:    transition.MovedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"card_id":     card.ID,
+		"transitions": response,
+		"count":       len(response),
+	})
+}
@@ -218,19 +218,16 @@ func DeleteAttachment(c *gin.Context) {
 		return
 	}
 
-	// Delete file from disk
-	filePath := "." + attachment.FileURL
-	if err := os.Remove(filePath); err != nil {
-		
-		fmt.Println("Warning: Could not delete file:", err)
-	}
-
-	// Delete database record
-	if err := database.DB.Delete(&attachment).Error; err != nil {
+	// Soft delete (the file stays on disk until the trash is purged)
+	if err := moveToTrash(&attachment, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attachment"})
 		return
 	}
 
+	utils.LogActivity("deleted_file", "attachment", attachment.ID, attachment.Card.List.BoardID, userID, attachment.Filename, map[string]interface{}{
+		"card_id": attachment.CardID,
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Attachment deleted successfully",
 		"id":      attachmentID,
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+)
+
+// AutomationRuleRequest creates or replaces an automation rule. Schedule rules
+// need a cron expression and act on the cards of filter.list_id.
+type AutomationRuleRequest struct {
+	Name     string                    `json:"name" binding:"required,min=1,max=100"`
+	Trigger  string                    `json:"trigger" binding:"required,oneof=card_created card_moved label_added label_removed member_assigned member_unassigned card_linked schedule"`
+	Filter   models.AutomationFilter   `json:"filter"`
+	Actions  []models.AutomationAction `json:"actions" binding:"required,min=1,max=10,dive"`
+	Cron     string                    `json:"cron" binding:"required_if=Trigger schedule,max=100"` // Schedule: minute hour day-of-month month day-of-week
+	Timezone string                    `json:"timezone" binding:"max=64"`                           // IANA name, default UTC
+	Enabled  *bool                     `json:"enabled"`                                             // Default true
+}
+
+// AutomationRuleResponse represents an automation rule
+type AutomationRuleResponse struct {
+	ID        uint                      `json:"id"`
+	BoardID   uint                      `json:"board_id"`
+	Name      string                    `json:"name"`
+	Trigger   string                    `json:"trigger"`
+	Filter    models.AutomationFilter   `json:"filter"`
+	Actions   []models.AutomationAction `json:"actions"`
+	Cron      string                    `json:"cron,omitempty"`
+	Timezone  string                    `json:"timezone"`
+	NextRunAt *time.Time                `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time                `json:"last_run_at,omitempty"`
+	Enabled   bool                      `json:"enabled"`
+	CreatedBy uint                      `json:"created_by"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
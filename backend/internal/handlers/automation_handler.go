@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// automationRunPaginator pages a board's automation run log, newest first
+var automationRunPaginator = &Paginator[models.AutomationRun]{
+	Sorts: map[string]SortKey[models.AutomationRun]{
+		"created_at": {Column: "automation_runs.created_at", Value: func(r models.AutomationRun) interface{} { return r.CreatedAt }},
+	},
+	DefaultSort:  "-created_at",
+	IDColumn:     "automation_runs.id",
+	ID:           func(r models.AutomationRun) uint { return r.ID },
+	DefaultLimit: 50,
+	MaxLimit:     200,
+}
+
+// GetAutomationRules returns a board's automation rules
+func GetAutomationRules(c *gin.Context) {
+	boardID := c.Param("id")
+
+	var rules []models.AutomationRule
+	if err := database.DB.Where("board_id = ?", boardID).Order("id ASC").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch automation rules"})
+		return
+	}
+
+	response := make([]AutomationRuleResponse, len(rules))
+	for i, rule := range rules {
+		response[i] = automationRuleResponse(&rule)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": response,
+		"count": len(response),
+	})
+}
+
+// CreateAutomationRule adds an automation rule to a board
+func CreateAutomationRule(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	var req AutomationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := models.AutomationRule{BoardID: uint(boardID), CreatedBy: userID}
+	if !setAutomationRule(c, &rule, &req) {
+		return
+	}
+
+	if err := database.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create automation rule"})
+		return
+	}
+
+	utils.LogActivity("created_automation", "automation", rule.ID, rule.BoardID, userID, rule.Name, map[string]interface{}{
+		"trigger": rule.Trigger,
+	})
+
+	c.JSON(http.StatusCreated, automationRuleResponse(&rule))
+}
+
+// UpdateAutomationRule replaces an automation rule
+func UpdateAutomationRule(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req AutomationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, ok := findAutomationRule(c, userID)
+	if !ok {
+		return
+	}
+	before := *rule
+	if !setAutomationRule(c, rule, &req) {
+		return
+	}
+
+	if err := database.DB.Save(rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update automation rule"})
+		return
+	}
+
+	var changes utils.FieldChanges
+	changes.Add("name", before.Name, rule.Name)
+	changes.Add("trigger", before.Trigger, rule.Trigger)
+	changes.Add("filter", before.Filter, rule.Filter)
+	changes.Add("actions", before.Actions, rule.Actions)
+	changes.Add("cron", before.Cron, rule.Cron)
+	changes.Add("timezone", before.Timezone, rule.Timezone)
+	changes.Add("enabled", before.Enabled, rule.Enabled)
+	utils.LogChanges("updated_automation", "automation", rule.ID, rule.BoardID, userID, rule.Name, changes, nil)
+
+	c.JSON(http.StatusOK, automationRuleResponse(rule))
+}
+
+// DeleteAutomationRule deletes an automation rule. Its run log is kept.
+func DeleteAutomationRule(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	rule, ok := findAutomationRule(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete automation rule"})
+		return
+	}
+
+	utils.LogActivity("deleted_automation", "automation", rule.ID, rule.BoardID, userID, rule.Name, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Automation rule deleted successfully",
+		"id":      rule.ID,
+	})
+}
+
+// GetAutomationRuns returns a board's automation run log, one page at a
+// time. It can be narrowed with rule_id and status.
+func GetAutomationRuns(c *gin.Context) {
+	boardID := c.Param("id")
+
+	page, ok := automationRunPaginator.Parse(c)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Where("board_id = ?", boardID)
+	if ruleID := c.Query("rule_id"); ruleID != "" {
+		query = query.Where("rule_id = ?", ruleID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var runs []models.AutomationRun
+	hasMore, err := page.Find(query, &runs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch automation runs"})
+		return
+	}
+
+	page.Respond(c, "runs", runs, runs, hasMore)
+}
+
+// setAutomationRule validates req and copies it onto rule. On failure it
+// responds and returns false.
+func setAutomationRule(c *gin.Context, rule *models.AutomationRule, req *AutomationRuleRequest) bool {
+	for _, listID := range []uint{req.Filter.ListID, req.Filter.FromListID} {
+		var list models.List
+		if listID != 0 && database.DB.Where("id = ? AND board_id = ?", listID, rule.BoardID).First(&list).Error != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Filter list not found on this board"})
+			return false
+		}
+	}
+
+	automationService := &services.AutomationService{}
+	for i := range req.Actions {
+		if err := automationService.ValidateAction(database.DB, rule.BoardID, &req.Actions[i]); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+	}
+
+	rule.Name = req.Name
+	rule.Trigger = req.Trigger
+	rule.Filter = req.Filter
+	rule.Actions = req.Actions
+	rule.Cron = ""
+	rule.Timezone = req.Timezone
+	rule.NextRunAt = nil
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	if rule.Timezone == "" {
+		rule.Timezone = "UTC"
+	}
+
+	if rule.Trigger == services.TriggerSchedule {
+		if rule.Filter.ListID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Schedule rules need filter.list_id, the list whose cards they act on"})
+			return false
+		}
+		rule.Cron = req.Cron
+		if err := automationService.ScheduleNext(rule, time.Now()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule: " + err.Error()})
+			return false
+		}
+	}
+	return true
+}
+
+// findAutomationRule loads the rule in the :id param and checks the user may
+// edit its board. On failure it responds and returns false.
+func findAutomationRule(c *gin.Context, userID uint) (*models.AutomationRule, bool) {
+	var rule models.AutomationRule
+	if err := database.DB.First(&rule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Automation rule not found"})
+		return nil, false
+	}
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, rule.BoardID, "edit_board") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return nil, false
+	}
+	return &rule, true
+}
+
+func automationRuleResponse(rule *models.AutomationRule) AutomationRuleResponse {
+	response := AutomationRuleResponse{
+		ID:        rule.ID,
+		BoardID:   rule.BoardID,
+		Name:      rule.Name,
+		Trigger:   rule.Trigger,
+		Filter:    rule.Filter,
+		Actions:   rule.Actions,
+		Cron:      rule.Cron,
+		Timezone:  rule.Timezone,
+		NextRunAt: rule.NextRunAt,
+		LastRunAt: rule.LastRunAt,
+		Enabled:   rule.Enabled,
+		CreatedBy: rule.CreatedBy,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+	if response.Actions == nil {
+		response.Actions = []models.AutomationAction{}
+	}
+	return response
+}
@@ -27,16 +27,26 @@ type BoardResponse struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
-// BoardDetailResponse includes lists (for single board view)
+// BoardDetailResponse includes lists (for single board view). Groups is set
+// when the board is requested with group_by.
 type BoardDetailResponse struct {
 	BoardResponse
-	Lists []ListResponse `json:"lists"`
+	Lists   []ListResponse       `json:"lists"`
+	Lanes   []LaneResponse       `json:"lanes"`
+	GroupBy string               `json:"group_by,omitempty"` // lane, assignee or label
+	Groups  []BoardGroupResponse `json:"groups,omitempty"`
 }
 
 // ListResponse represents list data (we'll use this later)
 type ListResponse struct {
-	ID       uint   `json:"id"`
-	Title    string `json:"title"`
-	BoardID  uint   `json:"board_id"`
-	Position int    `json:"position"`
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	BoardID   uint   `json:"board_id"`
+	Rank      string `json:"rank"`
+	Version   int    `json:"version"`
+	IsDone    bool   `json:"is_done"`
+	WIPLimit  *int   `json:"wip_limit,omitempty"`
+	WIPMode   string `json:"wip_mode"`
+	CardCount *int   `json:"card_count,omitempty"` // Unarchived cards; set when listing a board's lists
+	OverLimit bool   `json:"over_limit,omitempty"` // More cards than the WIP limit
 }
@@ -5,6 +5,8 @@ import (
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -28,7 +30,7 @@ func CreateBoard(c *gin.Context) {
 		backgroundColor = "#0079BF" 
 	}
 
-	
+	var created models.Board
 	err := database.DB.Transaction(func(tx *gorm.DB) error {
 		
 		board := models.Board{
@@ -58,6 +60,7 @@ func CreateBoard(c *gin.Context) {
 		if err := tx.Create(&boardMember).Error; err != nil {
 			return err
 		}
+		created = board
 
 
 		c.JSON(http.StatusCreated, gin.H{
@@ -80,21 +83,41 @@ func CreateBoard(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create board"})
 		return
 	}
+
+	// Log activity
+	utils.LogActivity("created_board", "board", created.ID, created.ID, userID, created.Title, nil)
+}
+
+// boardPaginator pages GetBoards, newest first by default
+var boardPaginator = &Paginator[models.Board]{
+	Sorts: map[string]SortKey[models.Board]{
+		"created_at": {Column: "boards.created_at", Value: func(b models.Board) interface{} { return b.CreatedAt }},
+		"updated_at": {Column: "boards.updated_at", Value: func(b models.Board) interface{} { return b.UpdatedAt }},
+		"title":      {Column: "boards.title", Value: func(b models.Board) interface{} { return b.Title }},
+	},
+	DefaultSort:  "-created_at",
+	IDColumn:     "boards.id",
+	ID:           func(b models.Board) uint { return b.ID },
+	DefaultLimit: 0, // Every row unless the client pages
+	MaxLimit:     200,
 }
 
-// GetBoards returns all boards the current user has access to
+// GetBoards returns the boards the current user has access to, one page at a time
 func GetBoards(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
+	page, ok := boardPaginator.Parse(c)
+	if !ok {
+		return
+	}
+
 	var boards []models.Board
 
 	
-	err := database.DB.
+	hasMore, err := page.Find(database.DB.
 		Joins("JOIN board_members ON boards.id = board_members.board_id").
 		Where("board_members.user_id = ? AND board_members.status = ?", userID, "active").
-		Preload("Owner").
-		Order("boards.created_at DESC").
-		Find(&boards).Error
+		Preload("Owner"), &boards)
 
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch boards"})
@@ -115,10 +138,7 @@ func GetBoards(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"boards": response,
-		"count":  len(response),
-	})
+	page.Respond(c, "boards", boards, response, hasMore)
 }
 
 // GetBoard returns a single board by ID with its lists
@@ -129,25 +149,48 @@ func GetBoard(c *gin.Context) {
 	var board models.Board
 	
 	if err := database.DB.Where("id = ? AND owner_id = ?", boardID, userID).
-		Preload("Lists").
+		Preload("Lists", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank ASC, id ASC")
+		}).
 		First(&board).Error; err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Board not found"})
 		return
 	}
 
 	
+	listIDs := make([]uint, len(board.Lists))
+	for i, list := range board.Lists {
+		listIDs[i] = list.ID
+	}
+	listService := &services.ListService{}
+	counts, err := listService.CardCounts(database.DB, listIDs)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to count cards"})
+		return
+	}
+
 	lists := make([]ListResponse, len(board.Lists))
 	for i, list := range board.Lists {
 		lists[i] = ListResponse{
 			ID:       list.ID,
 			Title:    list.Title,
 			BoardID:  list.BoardID,
-			Position: list.Position,
+			Rank:     list.Rank,
+			Version:  list.Version,
+			IsDone:   list.IsDone,
+			WIPLimit: list.WIPLimit,
+			WIPMode:  list.WIPMode,
 		}
+		setListCount(&lists[i], counts[list.ID])
 	}
 
-	// Return response
-	c.JSON(http.StatusOK, BoardDetailResponse{
+	lanes, err := boardLanes(board.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lanes"})
+		return
+	}
+
+	response := BoardDetailResponse{
 		BoardResponse: BoardResponse{
 			ID:              board.ID,
 			Title:           board.Title,
@@ -158,7 +201,26 @@ func GetBoard(c *gin.Context) {
 			UpdatedAt:       board.UpdatedAt,
 		},
 		Lists: lists,
-	})
+		Lanes: lanes,
+	}
+
+	// Optionally return the cards grouped by lane, assignee or label
+	switch groupBy := c.Query("group_by"); groupBy {
+	case "":
+	case GroupByLane, GroupByAssignee, GroupByLabel:
+		groups, err := boardGroups(lists, lanes, groupBy)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cards"})
+			return
+		}
+		response.GroupBy, response.Groups = groupBy, groups
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid group_by, use lane, assignee or label"})
+		return
+	}
+
+	// Return response
+	c.JSON(http.StatusOK, response)
 }
 
 
@@ -166,6 +228,7 @@ func GetBoard(c *gin.Context) {
 func UpdateBoard(c *gin.Context) {
 
 	boardID := c.Param("id")
+	userID := c.GetUint("user_id")
 
 	var req UpdateBoardRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -180,7 +243,7 @@ func UpdateBoard(c *gin.Context) {
 		return
 	}
 
-	
+	before := board
 	if req.Title != "" {
 		board.Title = req.Title
 	}
@@ -197,6 +260,13 @@ func UpdateBoard(c *gin.Context) {
 		return
 	}
 
+	// Log activity
+	var changes utils.FieldChanges
+	changes.Add("title", before.Title, board.Title)
+	changes.Add("description", before.Description, board.Description)
+	changes.Add("background_color", before.BackgroundColor, board.BackgroundColor)
+	utils.LogChanges("updated_board", "board", board.ID, board.ID, userID, board.Title, changes, nil)
+
 	c.JSON(http.StatusOK, BoardResponse{
 		ID:              board.ID,
 		Title:           board.Title,
@@ -226,6 +296,9 @@ func DeleteBoard(c *gin.Context) {
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("deleted_board", "board", board.ID, board.ID, userID, board.Title, nil)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Board deleted successfully",
 		"id":      boardID,
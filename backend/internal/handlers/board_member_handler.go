@@ -7,6 +7,7 @@ import (
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -62,6 +63,11 @@ func InviteMember(c *gin.Context) {
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("added_member", "member", user.ID, uint(boardID), inviterIDUint, user.Username, map[string]interface{}{
+		"role": role.Name,
+	})
+
 	database.DB.Preload("User").Preload("Role").First(&boardMember, boardMember.ID)
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -74,6 +80,7 @@ func InviteMember(c *gin.Context) {
 func RemoveMember(c *gin.Context) {
 
 	memberID, _ := strconv.ParseUint(c.Param("member_id"), 10, 32)
+	userID := c.GetUint("user_id")
 
 	// Find the board member
 	var boardMember models.BoardMember
@@ -97,6 +104,18 @@ func RemoveMember(c *gin.Context) {
 	boardMember.UpdatedAt = time.Now()
 	database.DB.Save(&boardMember)
 
+	// Log activity
+	var member models.User
+	database.DB.First(&member, boardMember.UserID)
+	utils.LogActivity("removed_member", "member", member.ID, boardMember.BoardID, userID, member.Username, map[string]interface{}{
+		"role": boardMember.Role.Name,
+	})
+
+	// Their open connections stop receiving the board
+	if WSHub != nil {
+		WSHub.RecheckAccess(boardMember.UserID, boardMember.BoardID)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
 }
 
@@ -138,12 +157,22 @@ func UpdateMemberRole(c *gin.Context) {
 	}
 
 	// Update member role
+	oldRole := boardMember.Role.Name
 	boardMember.RoleID = role.ID
 	boardMember.UpdatedAt = time.Now()
 	database.DB.Save(&boardMember)
 
 	database.DB.Preload("User").Preload("Role").First(&boardMember, boardMember.ID)
 
+	// Log activity
+	var changes utils.FieldChanges
+	changes.Add("role", oldRole, boardMember.Role.Name)
+	utils.LogChanges("changed_member_role", "member", boardMember.UserID, boardMember.BoardID, c.GetUint("user_id"), boardMember.User.Username, changes, nil)
+
+	if WSHub != nil {
+		WSHub.RecheckAccess(boardMember.UserID, boardMember.BoardID)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Role updated successfully",
 		"member":  boardMember,
@@ -0,0 +1,11 @@
+package handlers
+
+import "time"
+
+// CalendarFeedResponse represents a calendar feed with its secret URL
+type CalendarFeedResponse struct {
+	ID        uint      `json:"id"`
+	BoardID   *uint     `json:"board_id"` // Nil for the personal feed of assigned cards
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/ical"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCalendarFeeds returns the user's enabled calendar feeds
+func GetCalendarFeeds(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var feeds []models.CalendarFeed
+	if err := database.DB.Where("user_id = ?", userID).
+		Order("board_id ASC NULLS FIRST").
+		Find(&feeds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch calendar feeds"})
+		return
+	}
+
+	response := make([]CalendarFeedResponse, len(feeds))
+	for i := range feeds {
+		response[i] = calendarFeedResponse(&feeds[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feeds": response,
+	})
+}
+
+// EnableCalendarFeed creates a secret calendar URL for the due dates of the
+// cards assigned to the user. Calling it again replaces the token, so the
+// old URL stops working.
+func EnableCalendarFeed(c *gin.Context) {
+	enableCalendarFeed(c, c.GetUint("user_id"), nil)
+}
+
+// DisableCalendarFeed revokes the user's personal calendar URL
+func DisableCalendarFeed(c *gin.Context) {
+	disableCalendarFeed(c, c.GetUint("user_id"), nil)
+}
+
+// EnableBoardCalendarFeed creates a secret calendar URL for the due dates on
+// a board. Each member gets their own URL; calling it again replaces it.
+func EnableBoardCalendarFeed(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	id := uint(boardID)
+	enableCalendarFeed(c, c.GetUint("user_id"), &id)
+}
+
+// DisableBoardCalendarFeed revokes the user's calendar URL for a board
+func DisableBoardCalendarFeed(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+	id := uint(boardID)
+	disableCalendarFeed(c, c.GetUint("user_id"), &id)
+}
+
+// GetCalendarFeedEvents serves a calendar feed as iCalendar. It is public:
+// the token in the URL identifies the feed, and cards are still limited to
+// what the feed's owner can see now.
+func GetCalendarFeedEvents(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var feed models.CalendarFeed
+	if token == "" || database.DB.Preload("Board").Where("token = ?", token).First(&feed).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+
+	now := time.Now()
+	calendarService := &services.CalendarService{}
+	cards, err := calendarService.FeedCards(database.DB, &feed, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+
+	name := "FlowBoard: My cards"
+	if feed.Board != nil {
+		name = "FlowBoard: " + feed.Board.Title
+	}
+
+	calendar := ical.Calendar{
+		ProductID: "-//FlowBoard//Due dates//EN",
+		Name:      name,
+		Events:    make([]ical.Event, len(cards)),
+	}
+	for i := range cards {
+		calendar.Events[i] = cardEvent(&cards[i])
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="flowboard.ics"`)
+	c.Status(http.StatusOK)
+	if err := calendar.Write(c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+// enableCalendarFeed creates the user's feed for boardID (nil for the
+// personal feed), or gives an existing one a new token
+func enableCalendarFeed(c *gin.Context, userID uint, boardID *uint) {
+	token, err := utils.GenerateToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable calendar feed"})
+		return
+	}
+
+	feed, err := findCalendarFeed(userID, boardID)
+	switch {
+	case err == nil:
+		err = database.DB.Model(feed).Update("token", token).Error
+		feed.Token = token
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		feed = &models.CalendarFeed{UserID: userID, BoardID: boardID, Token: token}
+		err = database.DB.Create(feed).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse(feed))
+}
+
+// disableCalendarFeed deletes the user's feed for boardID (nil for the
+// personal feed)
+func disableCalendarFeed(c *gin.Context, userID uint, boardID *uint) {
+	feed, err := findCalendarFeed(userID, boardID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar feed not found"})
+		return
+	}
+
+	if err := database.DB.Delete(feed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Calendar feed disabled",
+	})
+}
+
+// findCalendarFeed loads the user's feed for boardID, nil meaning the personal feed
+func findCalendarFeed(userID uint, boardID *uint) (*models.CalendarFeed, error) {
+	query := database.DB.Where("user_id = ?", userID)
+	if boardID == nil {
+		query = query.Where("board_id IS NULL")
+	} else {
+		query = query.Where("board_id = ?", *boardID)
+	}
+
+	var feed models.CalendarFeed
+	if err := query.First(&feed).Error; err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// calendarFeedResponse converts a calendar feed, including its secret URL
+func calendarFeedResponse(feed *models.CalendarFeed) CalendarFeedResponse {
+	return CalendarFeedResponse{
+		ID:        feed.ID,
+		BoardID:   feed.BoardID,
+		URL:       APIBaseURL + "/api/v1/feeds/calendar/" + feed.Token + ".ics",
+		CreatedAt: feed.CreatedAt,
+	}
+}
+
+// cardEvent turns a card with a due date into a calendar event at its due
+// time. The description links back to the card and names its labels.
+func cardEvent(card *models.Card) ical.Event {
+	summary := card.Title
+	if card.DueComplete {
+		summary = "✓ " + summary
+	}
+
+	labels := make([]string, len(card.Labels))
+	for i, label := range card.Labels {
+		labels[i] = label.Name
+	}
+
+	var description strings.Builder
+	fmt.Fprintf(&description, "Board: %s\nList: %s\n", card.List.Board.Title, card.List.Title)
+	if len(labels) > 0 {
+		fmt.Fprintf(&description, "Labels: %s\n", strings.Join(labels, ", "))
+	}
+	link := cardURL(card)
+	if link != "" {
+		fmt.Fprintf(&description, "%s\n", link)
+	}
+	if card.Description != "" {
+		fmt.Fprintf(&description, "\n%s", card.Description)
+	}
+
+	return ical.Event{
+		UID:          fmt.Sprintf("flowboard-card-%d@flowboard", card.ID),
+		Stamp:        card.UpdatedAt,
+		Start:        *card.DueDate,
+		Summary:      summary,
+		Description:  strings.TrimRight(description.String(), "\n"),
+		URL:          link,
+		Categories:   labels,
+		LastModified: card.UpdatedAt,
+	}
+}
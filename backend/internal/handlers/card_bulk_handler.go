@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BulkUpdateCards applies one operation to many cards in a single transaction.
+// Either every card is updated or none is; the response lists each card's result.
+func BulkUpdateCards(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req BulkCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cardIDs := uniqueIDs(req.CardIDs)
+
+	// Find cards
+	var cards []models.Card
+	if err := database.DB.Preload("List").Where("id IN ?", cardIDs).Find(&cards).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cards"})
+		return
+	}
+	if len(cards) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cards not found"})
+		return
+	}
+
+	byID := make(map[uint]*models.Card, len(cards))
+	for i := range cards {
+		byID[cards[i].ID] = &cards[i]
+	}
+
+	// All cards must be on one board: the board of the first card found
+	var boardID uint
+	for _, id := range cardIDs {
+		if card, ok := byID[id]; ok {
+			boardID = card.List.BoardID
+			break
+		}
+	}
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, boardID, bulkPermission(req.Operation)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return
+	}
+
+	if status, message := validateBulkTarget(&req, boardID); status != 0 {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	// Reject the whole request if any card cannot be updated
+	results := make([]BulkCardResult, len(cardIDs))
+	failed := false
+	for i, id := range cardIDs {
+		results[i] = BulkCardResult{CardID: id}
+		card, ok := byID[id]
+		switch {
+		case !ok:
+			results[i].Status, results[i].Error = "failed", "Card not found"
+			failed = true
+		case card.List.BoardID != boardID:
+			results[i].Status, results[i].Error = "failed", "Card belongs to another board"
+			failed = true
+		}
+	}
+	if failed {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Some cards cannot be updated",
+			"results": results,
+		})
+		return
+	}
+
+	op := services.BulkOperation{
+		Type:     req.Operation,
+		ListID:   req.ListID,
+		Position: req.Position,
+		LabelID:  req.LabelID,
+		MemberID: req.MemberID,
+		DueDate:  req.DueDate,
+		UserID:   userID,
+	}
+
+	cardService := &services.CardService{}
+	var changed []uint
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range cardIDs {
+			updated, err := cardService.ApplyBulk(tx, byID[id], op, i)
+			if errors.Is(err, services.ErrWIPLimit) {
+				results[i].Status, results[i].Error = "failed", "List is at its work-in-progress limit"
+				return err
+			}
+			if err != nil {
+				results[i].Status, results[i].Error = "failed", "Failed to update card"
+				return err
+			}
+			results[i].Status = "unchanged"
+			if updated {
+				results[i].Status = "updated"
+				changed = append(changed, id)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, services.ErrWIPLimit) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "List is at its work-in-progress limit",
+			"results": results,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to update cards",
+			"results": results,
+		})
+		return
+	}
+
+	if len(changed) > 0 {
+		details := bulkDetails(&req)
+		details["card_ids"] = changed
+
+		// One grouped activity entry instead of one per card
+		utils.LogActivity("bulk_updated_cards", "card", 0, boardID, userID,
+			fmt.Sprintf("%d cards", len(changed)), details)
+
+		// Broadcast to WebSocket clients
+		if WSHub != nil {
+			WSHub.BroadcastToBoard(boardID, "cards_bulk_updated", details)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Cards updated successfully",
+		"operation": req.Operation,
+		"updated":   len(changed),
+		"results":   results,
+	})
+}
+
+// validateBulkTarget checks the list, label or member an operation refers to.
+// It returns a zero status when the request is valid.
+func validateBulkTarget(req *BulkCardRequest, boardID uint) (int, string) {
+	switch req.Operation {
+	case services.BulkMove:
+		if req.ListID == 0 {
+			return http.StatusBadRequest, "list_id is required"
+		}
+		var list models.List
+		if err := database.DB.First(&list, req.ListID).Error; err != nil || list.BoardID != boardID {
+			return http.StatusNotFound, "List not found"
+		}
+
+	case services.BulkAddLabel, services.BulkRemoveLabel:
+		if req.LabelID == 0 {
+			return http.StatusBadRequest, "label_id is required"
+		}
+		var label models.Label
+		if err := database.DB.First(&label, req.LabelID).Error; err != nil || label.BoardID != boardID {
+			return http.StatusNotFound, "Label not found"
+		}
+
+	case services.BulkAssign, services.BulkUnassign:
+		if req.MemberID == 0 {
+			return http.StatusBadRequest, "member_id is required"
+		}
+		permService := &services.PermissionService{}
+		if req.Operation == services.BulkAssign && !permService.HasBoardAccess(req.MemberID, boardID) {
+			return http.StatusBadRequest, "User is not a member of this board"
+		}
+	}
+	return 0, ""
+}
+
+// bulkPermission maps a bulk operation to the permission it needs
+func bulkPermission(operation string) string {
+	switch operation {
+	case services.BulkMove:
+		return "move_card"
+	case services.BulkDelete:
+		return "delete_card"
+	default:
+		return "edit_card"
+	}
+}
+
+// bulkDetails describes a bulk operation for the activity log and WebSocket clients
+func bulkDetails(req *BulkCardRequest) map[string]interface{} {
+	details := map[string]interface{}{"operation": req.Operation}
+	switch req.Operation {
+	case services.BulkMove:
+		details["list_id"] = req.ListID
+	case services.BulkAddLabel, services.BulkRemoveLabel:
+		details["label_id"] = req.LabelID
+	case services.BulkAssign, services.BulkUnassign:
+		details["member_id"] = req.MemberID
+	case services.BulkSetDueDate:
+		details["due_date"] = req.DueDate
+	}
+	return details
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
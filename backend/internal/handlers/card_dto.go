@@ -8,6 +8,10 @@ type CreateCardRequest struct {
 	Description string     `json:"description" binding:"max=2000"`
 	ListID      uint       `json:"list_id" binding:"required"`
 	DueDate     *time.Time `json:"due_date"` // Pointer = optional
+	LaneID      *uint      `json:"lane_id"`  // Lane on the list's board, default lane if omitted
+
+	// Planned start; must not be after the due date
+	StartDate *time.Time `json:"start_date"`
 }
 
 // UpdateCardRequest represents input for updating a card
@@ -16,26 +20,57 @@ type UpdateCardRequest struct {
 	Description string     `json:"description" binding:"omitempty,max=2000"`
 	Position    *int       `json:"position" binding:"omitempty,min=0"`
 	DueDate     *time.Time `json:"due_date"`
+	DueComplete *bool      `json:"due_complete"`
+	Version     *int       `json:"version"` // Optional: reject the update if the card changed since
+
+	// Expected work in minutes: 0 clears it
+	EstimateMinutes *int `json:"estimate_minutes" binding:"omitempty,min=0"`
+}
+
+// CreateCardResponse is the created card, with a warning when it took its
+// list over a soft WIP limit
+type CreateCardResponse struct {
+	CardResponse
+	WIPWarning string `json:"wip_warning,omitempty"`
 }
 
+// MoveCardRequest moves a card. Version, FromListID and FromPosition are
+// optional; when given, the move is rejected with 409 if they are stale.
+// IfBlocked decides what happens when a blocked card enters a done-list.
 type MoveCardRequest struct {
-	ListID   uint `json:"list_id" binding:"required"`
-	Position int  `json:"position" binding:"min=0"`
+	ListID       uint   `json:"list_id" binding:"required"`
+	Position     int    `json:"position" binding:"min=0"`
+	Version      *int   `json:"version"`
+	FromListID   *uint  `json:"from_list_id"`
+	FromPosition *int   `json:"from_position" binding:"omitempty,min=0"`
+	IfBlocked    string `json:"if_blocked" binding:"omitempty,oneof=warn refuse"` // Moving a blocked card into a done-list: warn (default) or refuse
+	LaneID       *uint  `json:"lane_id"`                                          // Lane on the destination board: 0 is the default lane, omitted keeps the lane
 }
 
 type CardDetailResponse struct {
-	ID          uint                 `json:"id"`
-	Title       string               `json:"title"`
-	Description string               `json:"description"`
-	ListID      uint                 `json:"list_id"`
-	Position    int                  `json:"position"`
-	DueDate     *time.Time           `json:"due_date,omitempty"`
-	CreatedAt   time.Time            `json:"created_at"`
-	UpdatedAt   time.Time            `json:"updated_at"`
-	Members     []UserResponse       `json:"members"`
-	Labels      []LabelResponse      `json:"labels"`
-	Comments    []CommentResponse    `json:"comments"`
-	Attachments []AttachmentResponse `json:"attachments"`
+	ID           uint                     `json:"id"`
+	Title        string                   `json:"title"`
+	Description  string                   `json:"description"`
+	ListID       uint                     `json:"list_id"`
+	Rank         string                   `json:"rank"`
+	Version      int                      `json:"version"`
+	DueDate      *time.Time               `json:"due_date,omitempty"`
+	DueComplete  bool                     `json:"due_complete"`
+	StartDate    *time.Time               `json:"start_date,omitempty"`
+	LaneID       *uint                    `json:"lane_id,omitempty"`
+	Estimate     *int                     `json:"estimate_minutes,omitempty"`
+	TimeSpent    int64                    `json:"time_spent_seconds"` // Tracked on the card by everyone, running timers included
+	ArchivedAt   *time.Time               `json:"archived_at,omitempty"`
+	Blocked      bool                     `json:"blocked"`                   // Blocked by a card that is not done
+	BlockedBy    []uint                   `json:"blocked_by,omitempty"`      // IDs of the blocking cards the user can see
+	HiddenBlocks int                      `json:"hidden_blockers,omitempty"` // Blocking cards on boards the user cannot see
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+	Members      []UserResponse           `json:"members"`
+	Labels       []LabelResponse          `json:"labels"`
+	Comments     []CommentResponse        `json:"comments"`
+	Attachments  []AttachmentResponse     `json:"attachments"`
+	CustomFields []CardFieldValueResponse `json:"custom_fields"`
 }
 
 // LabelResponse for card labels (we'll implement labels later)
@@ -66,3 +101,22 @@ type AttachmentResponse struct {
 	UploadedBy UserResponse `json:"uploaded_by"`
 	CreatedAt  time.Time    `json:"created_at"`
 }
+
+// BulkCardRequest applies one operation to a set of cards on the same board.
+// Which of the optional fields are required depends on Operation.
+type BulkCardRequest struct {
+	CardIDs   []uint     `json:"card_ids" binding:"required,min=1,max=100,dive,required"`
+	Operation string     `json:"operation" binding:"required,oneof=move add_label remove_label assign unassign set_due_date complete_due archive unarchive delete"`
+	ListID    uint       `json:"list_id"`                            // move
+	Position  *int       `json:"position" binding:"omitempty,min=0"` // move: index of the first card, end of list if omitted
+	LabelID   uint       `json:"label_id"`                           // add_label, remove_label
+	MemberID  uint       `json:"member_id"`                          // assign, unassign
+	DueDate   *time.Time `json:"due_date"`                           // set_due_date: null clears it
+}
+
+// BulkCardResult reports what happened to one card of a bulk request
+type BulkCardResult struct {
+	CardID uint   `json:"card_id"`
+	Status string `json:"status"` // updated, unchanged or failed
+	Error  string `json:"error,omitempty"`
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
 	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
 	"github.com/gin-gonic/gin"
@@ -37,26 +40,49 @@ func CreateCard(c *gin.Context) {
 		return
 	}
 
-	// Get the highest position in this list
-	var maxPosition int
-	database.DB.Model(&models.Card{}).
-		Where("list_id = ?", req.ListID).
-		Select("COALESCE(MAX(position), -1)").
-		Scan(&maxPosition)
+	// The lane must be on the list's board
+	if req.LaneID != nil && *req.LaneID != 0 && !laneOnBoard(*req.LaneID, list.BoardID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Lane not found on this board"})
+		return
+	}
+
+	if req.StartDate != nil && req.DueDate != nil && req.StartDate.After(*req.DueDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must not be after due_date"})
+		return
+	}
 
 	// Create card at the end
 	card := models.Card{
 		Title:       req.Title,
 		Description: req.Description,
 		ListID:      req.ListID,
-		Position:    maxPosition + 1,
 		DueDate:     req.DueDate,
+		StartDate:   req.StartDate,
+	}
+	if req.LaneID != nil && *req.LaneID != 0 {
+		card.LaneID = req.LaneID
 	}
 
-	if err := database.DB.Create(&card).Error; err != nil {
+	cardService := &services.CardService{}
+	var wip *services.WIPCheck
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		wip, err = cardService.CreateCard(tx, &card, userID)
+		return err
+	})
+	if errors.Is(err, services.ErrWIPLimit) {
+		card.ID = 0 // Rolled back
+		logWIPBreach(wip, &list, &card, userID)
+		respondWIPLimit(c, wip)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create card"})
 		return
 	}
+	if wip != nil && wip.Over {
+		logWIPBreach(wip, &list, &card, userID)
+	}
 
 	// Log activity
 	utils.LogActivity("created_card", "card", card.ID, list.Board.ID, userID, card.Title, nil)
@@ -68,24 +94,54 @@ func CreateCard(c *gin.Context) {
 			Title:       card.Title,
 			Description: card.Description,
 			ListID:      card.ListID,
-			Position:    card.Position,
+			Rank:        card.Rank,
+			Version:     card.Version,
 			DueDate:     card.DueDate,
+			DueComplete: card.DueComplete,
+			LaneID:      card.LaneID,
 			CreatedAt:   card.CreatedAt,
+			StartDate:   card.StartDate,
 		})
 	}
 
-	c.JSON(http.StatusCreated, CardResponse{
-		ID:          card.ID,
-		Title:       card.Title,
-		Description: card.Description,
-		ListID:      card.ListID,
-		Position:    card.Position,
-		DueDate:     card.DueDate,
-		CreatedAt:   card.CreatedAt,
-	})
+	response := CreateCardResponse{
+		CardResponse: CardResponse{
+			ID:          card.ID,
+			Title:       card.Title,
+			Description: card.Description,
+			ListID:      card.ListID,
+			Rank:        card.Rank,
+			Version:     card.Version,
+			DueDate:     card.DueDate,
+			DueComplete: card.DueComplete,
+			LaneID:      card.LaneID,
+			CreatedAt:   card.CreatedAt,
+			StartDate:   card.StartDate,
+		},
+	}
+	if wip != nil && wip.Over {
+		response.WIPWarning = wipWarning
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// cardPaginator pages GetCards, in board order by default
+var cardPaginator = &Paginator[models.Card]{
+	Sorts: map[string]SortKey[models.Card]{
+		"rank":       {Column: "cards.rank", Value: func(c models.Card) interface{} { return c.Rank }},
+		"created_at": {Column: "cards.created_at", Value: func(c models.Card) interface{} { return c.CreatedAt }},
+		"updated_at": {Column: "cards.updated_at", Value: func(c models.Card) interface{} { return c.UpdatedAt }},
+		"title":      {Column: "cards.title", Value: func(c models.Card) interface{} { return c.Title }},
+	},
+	DefaultSort:  "rank",
+	IDColumn:     "cards.id",
+	ID:           func(c models.Card) uint { return c.ID },
+	DefaultLimit: 0, // Every row unless the client pages
+	MaxLimit:     500,
 }
 
-// GetCards returns all cards in a list
+// GetCards returns the cards in a list, one page at a time. Archived cards
+// are only returned, on their own, with ?archived=true.
 func GetCards(c *gin.Context) {
 	listID := c.Param("list_id")
 	userID := c.GetUint("user_id")
@@ -102,11 +158,30 @@ func GetCards(c *gin.Context) {
 		return
 	}
 
-	// Get all cards ordered by position
+	page, ok := cardPaginator.Parse(c)
+	if !ok {
+		return
+	}
+
+	// Get a page of cards, ordered by rank unless asked otherwise
+	archived := "archived_at IS NULL"
+	if c.Query("archived") == "true" {
+		archived = "archived_at IS NOT NULL"
+	}
+
 	var cards []models.Card
-	if err := database.DB.Where("list_id = ?", listID).
-		Order("position ASC").
-		Find(&cards).Error; err != nil {
+	hasMore, err := page.Find(database.DB.Where("list_id = ?", listID).Where(archived), &cards)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cards"})
+		return
+	}
+
+	ids := make([]uint, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	blockers, err := cardBlockers(ids)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cards"})
 		return
 	}
@@ -119,16 +194,18 @@ func GetCards(c *gin.Context) {
 			Title:       card.Title,
 			Description: card.Description,
 			ListID:      card.ListID,
-			Position:    card.Position,
+			Rank:        card.Rank,
+			Version:     card.Version,
 			DueDate:     card.DueDate,
+			DueComplete: card.DueComplete,
+			LaneID:      card.LaneID,
+			ArchivedAt:  card.ArchivedAt,
+			Blocked:     len(blockers[card.ID]) > 0,
 			CreatedAt:   card.CreatedAt,
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"cards": response,
-		"count": len(response),
-	})
+	page.Respond(c, "cards", cards, response, hasMore)
 }
 
 // GetCard returns a single card with all details
@@ -214,19 +291,61 @@ func GetCard(c *gin.Context) {
 		}
 	}
 
+	// Custom field values
+	fieldValues, err := cardFieldValues([]uint{card.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom fields"})
+		return
+	}
+
+	// Open cards blocking this one
+	blockers, err := cardBlockers([]uint{card.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch card relations"})
+		return
+	}
+	shownBlockers, err := visibleBlockers(blockers, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch card relations"})
+		return
+	}
+
+	// Time tracked by everyone
+	timeService := &services.TimeService{}
+	totals, err := timeService.CardTotals(database.DB, card.ID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tracked time"})
+		return
+	}
+	var timeSpent int64
+	for _, total := range totals {
+		timeSpent += total.Seconds
+	}
+
 	c.JSON(http.StatusOK, CardDetailResponse{
-		ID:          card.ID,
-		Title:       card.Title,
-		Description: card.Description,
-		ListID:      card.ListID,
-		Position:    card.Position,
-		DueDate:     card.DueDate,
-		CreatedAt:   card.CreatedAt,
-		UpdatedAt:   card.UpdatedAt,
-		Members:     members,
-		Labels:      labels,
-		Comments:    comments,
-		Attachments: attachments,
+		ID:           card.ID,
+		Title:        card.Title,
+		Description:  card.Description,
+		ListID:       card.ListID,
+		Rank:         card.Rank,
+		Version:      card.Version,
+		DueDate:      card.DueDate,
+		DueComplete:  card.DueComplete,
+		StartDate:    card.StartDate,
+		LaneID:       card.LaneID,
+		Estimate:     card.EstimateMinutes,
+		TimeSpent:    timeSpent,
+		ArchivedAt:   card.ArchivedAt,
+		Blocked:      len(blockers[card.ID]) > 0,
+		BlockedBy:    shownBlockers[card.ID],
+		HiddenBlocks: len(blockers[card.ID]) - len(shownBlockers[card.ID]),
+		CreatedAt:    card.CreatedAt,
+		UpdatedAt:    card.UpdatedAt,
+		Members:      members,
+		Labels:       labels,
+		Comments:     comments,
+		Attachments:  attachments,
+		CustomFields: cardFieldValueList(fieldValues, card.ID),
 	})
 }
 
@@ -254,31 +373,108 @@ func UpdateCard(c *gin.Context) {
 		return
 	}
 
-	// Update fields
-	if req.Title != "" {
-		card.Title = req.Title
-	}
-	if req.Description != "" {
-		card.Description = req.Description
-	}
-	if req.Position != nil {
-		card.Position = *req.Position
-	}
-	// DueDate can be set or cleared
-	card.DueDate = req.DueDate
+	// The edit is applied to the card as locked inside the transaction, so
+	// the revisions written with it record the values it replaced
+	var before models.Card
+	var changes utils.FieldChanges
+	cardService := &services.CardService{}
+	revisionService := &services.RevisionService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// The card is locked before its list, as moves do
+		locked, err := cardService.LockCard(tx, card.ID)
+		if err != nil {
+			return err
+		}
+		locked.List = card.List
+		before, card = *locked, *locked
 
-	if err := database.DB.Save(&card).Error; err != nil {
+		// Update fields
+		if req.Title != "" {
+			card.Title = req.Title
+		}
+		if req.Description != "" {
+			card.Description = req.Description
+		}
+		// DueDate can be set or cleared
+		card.DueDate = req.DueDate
+
+		// Only write the edited columns so a concurrent move is not overwritten
+		updates := map[string]interface{}{
+			"title":       card.Title,
+			"description": card.Description,
+			"due_date":    card.DueDate,
+			"version":     gorm.Expr("version + 1"),
+		}
+		if req.DueComplete != nil {
+			card.DueComplete = *req.DueComplete
+			updates["due_complete"] = card.DueComplete
+		}
+		if req.EstimateMinutes != nil {
+			card.EstimateMinutes = req.EstimateMinutes
+			if *req.EstimateMinutes == 0 {
+				card.EstimateMinutes = nil
+			}
+			updates["estimate_minutes"] = card.EstimateMinutes
+		}
+
+		changes = nil
+		changes.Add("title", before.Title, card.Title)
+		changes.Add("description", before.Description, card.Description)
+		changes.Add("due_date", before.DueDate, card.DueDate)
+		changes.Add("due_complete", before.DueComplete, card.DueComplete)
+		changes.Add("estimate_minutes", before.EstimateMinutes, card.EstimateMinutes)
+
+		if req.Position != nil {
+			// Re-rank within the same list
+			if err := services.LockLists(tx, card.ListID); err != nil {
+				return err
+			}
+			ordering := &services.OrderingService{}
+			rank, err := ordering.CardRankAt(tx, card.ListID, *req.Position, card.ID)
+			if err != nil {
+				return err
+			}
+			card.Rank = rank
+			updates["rank"] = rank
+		}
+
+		query := tx.Model(&card)
+		if req.Version != nil {
+			query = query.Where("version = ?", *req.Version)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrStaleCard
+		}
+		return revisionService.Record(tx, card.ID, userID, changes)
+	})
+	if err == services.ErrStaleCard {
+		respondStaleCard(c, card.ID)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update card"})
 		return
 	}
+	card.Version++
+
+	// Log activity
+	changes.Add("rank", before.Rank, card.Rank)
+	utils.LogChanges("updated_card", "card", card.ID, card.List.BoardID, userID, card.Title, changes, nil)
 
 	c.JSON(http.StatusOK, CardResponse{
 		ID:          card.ID,
 		Title:       card.Title,
 		Description: card.Description,
 		ListID:      card.ListID,
-		Position:    card.Position,
+		Rank:        card.Rank,
+		Version:     card.Version,
 		DueDate:     card.DueDate,
+		DueComplete: card.DueComplete,
+		LaneID:      card.LaneID,
 		CreatedAt:   card.CreatedAt,
 	})
 }
@@ -320,59 +516,150 @@ func MoveCard(c *gin.Context) {
 	}
 
 	oldListID := card.ListID
-	oldPosition := card.Position
-	newListID := req.ListID
-	newPosition := req.Position
-
-	// Moving to different list
-	if oldListID != newListID {
-		// Remove from old list: decrease position of cards after this one
-		database.DB.Model(&models.Card{}).
-			Where("list_id = ? AND position > ?", oldListID, oldPosition).
-			UpdateColumn("position", gorm.Expr("position - 1"))
-
-		// Make space in new list: increase position of cards at/after new position
-		database.DB.Model(&models.Card{}).
-			Where("list_id = ? AND position >= ?", newListID, newPosition).
-			UpdateColumn("position", gorm.Expr("position + 1"))
-
-		// Update card
-		card.ListID = newListID
-		card.Position = newPosition
-	} else {
-		// Moving within same list
-		if newPosition > oldPosition {
-			// Moving down: decrease position of cards in between
-			database.DB.Model(&models.Card{}).
-				Where("list_id = ? AND position > ? AND position <= ?", oldListID, oldPosition, newPosition).
-				UpdateColumn("position", gorm.Expr("position - 1"))
-		} else if newPosition < oldPosition {
-			// Moving up: increase position of cards in between
-			database.DB.Model(&models.Card{}).
-				Where("list_id = ? AND position >= ? AND position < ?", oldListID, newPosition, oldPosition).
-				UpdateColumn("position", gorm.Expr("position + 1"))
+
+	// Lanes belong to a board, so a card leaving its board leaves its lane
+	laneID := req.LaneID
+	if laneID != nil && *laneID != 0 && !laneOnBoard(*laneID, destList.BoardID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Lane not found on the destination board"})
+		return
+	}
+	if laneID == nil && card.LaneID != nil && destList.BoardID != card.List.BoardID {
+		defaultLane := uint(0)
+		laneID = &defaultLane
+	}
+
+	// A blocked card entering a done-list is refused or moved with a warning.
+	// Blockers on boards the user cannot see are only counted.
+	var blockedBy []uint
+	var hiddenBlockers int
+	if destList.IsDone && destList.ID != oldListID {
+		blockers, err := cardBlockers([]uint{card.ID})
+		if err == nil {
+			var shown map[uint][]uint
+			shown, err = visibleBlockers(blockers, userID)
+			blockedBy = shown[card.ID]
+			hiddenBlockers = len(blockers[card.ID]) - len(blockedBy)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move card"})
+			return
 		}
+	}
+	blocked := len(blockedBy)+hiddenBlockers > 0
+	if blocked && req.IfBlocked == "refuse" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":           "Card is blocked by cards that are not done",
+			"blocked_by":      blockedBy,
+			"hidden_blockers": hiddenBlockers,
+		})
+		return
+	}
 
-		card.Position = newPosition
+	// Lock, check for a stale client state and write only the moved row.
+	// Cards entering another list count towards its WIP limit.
+	cardService := &services.CardService{}
+	var moved *models.Card
+	var wip *services.WIPCheck
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		moved, wip, err = cardService.MoveCard(tx, card.ID, services.MoveCardInput{
+			ListID:       req.ListID,
+			Position:     req.Position,
+			Version:      req.Version,
+			FromListID:   req.FromListID,
+			FromPosition: req.FromPosition,
+			LaneID:       laneID,
+			UserID:       userID,
+		})
+		return err
+	})
+	if err == services.ErrStaleCard {
+		respondStaleCard(c, card.ID)
+		return
+	}
+	if errors.Is(err, services.ErrWIPLimit) {
+		logWIPBreach(wip, &destList, &card, userID)
+		respondWIPLimit(c, wip)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move card"})
+		return
+	}
+	if wip != nil && wip.Over {
+		logWIPBreach(wip, &destList, &card, userID)
 	}
 
-	database.DB.Save(&card)
+	// Log activity
+	var changes utils.FieldChanges
+	changes.Add("list_id", oldListID, moved.ListID)
+	changes.Add("lane_id", card.LaneID, moved.LaneID)
+	changes.Add("rank", card.Rank, moved.Rank)
+	utils.LogChanges("moved_card", "card", card.ID, card.List.BoardID, userID, card.Title, changes, map[string]interface{}{
+		"from_list": card.List.Title,
+		"to_list":   destList.Title,
+		"position":  req.Position,
+	})
 
 	// Broadcast to WebSocket clients
 	if WSHub != nil {
 		WSHub.BroadcastToBoard(card.List.Board.ID, "card_moved", gin.H{
-			"card_id":      card.ID,
+			"card_id":      moved.ID,
 			"old_list_id":  oldListID,
-			"new_list_id":  card.ListID,
-			"new_position": card.Position,
+			"new_list_id":  moved.ListID,
+			"new_position": req.Position,
+			"lane_id":      moved.LaneID,
+			"rank":         moved.Rank,
+			"version":      moved.Version,
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message":      "Card moved successfully",
-		"id":           card.ID,
-		"new_list_id":  card.ListID,
-		"new_position": card.Position,
+		"id":           moved.ID,
+		"new_list_id":  moved.ListID,
+		"new_position": req.Position,
+		"lane_id":      moved.LaneID,
+		"rank":         moved.Rank,
+		"version":      moved.Version,
+	}
+	if blocked {
+		response["warning"] = "Card is blocked by cards that are not done"
+		response["blocked_by"] = blockedBy
+		response["hidden_blockers"] = hiddenBlockers
+	}
+	if wip != nil && wip.Over {
+		response["wip_warning"] = wipWarning
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// respondStaleCard returns 409 with the card's current state so the client can resync
+func respondStaleCard(c *gin.Context, cardID uint) {
+	var card models.Card
+	if err := database.DB.First(&card, cardID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card not found"})
+		return
+	}
+
+	ordering := &services.OrderingService{}
+	position, _ := ordering.CardIndex(database.DB, &card)
+
+	c.JSON(http.StatusConflict, gin.H{
+		"error":    "Card was changed by someone else",
+		"position": position,
+		"card": CardResponse{
+			ID:          card.ID,
+			Title:       card.Title,
+			Description: card.Description,
+			ListID:      card.ListID,
+			Rank:        card.Rank,
+			Version:     card.Version,
+			DueDate:     card.DueDate,
+			DueComplete: card.DueComplete,
+			LaneID:      card.LaneID,
+			CreatedAt:   card.CreatedAt,
+		},
 	})
 }
 
@@ -398,12 +685,17 @@ func DeleteCard(c *gin.Context) {
 	boardID := card.List.Board.ID
 	listID := card.ListID
 
-	// Soft delete
-	if err := database.DB.Delete(&card).Error; err != nil {
+	// Soft delete (moves the card to the board's trash)
+	if err := moveToTrash(&card, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete card"})
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("deleted_card", "card", card.ID, boardID, userID, card.Title, map[string]interface{}{
+		"list_id": listID,
+	})
+
 	// Broadcast to WebSocket clients
 	if WSHub != nil {
 		WSHub.BroadcastToBoard(boardID, "card_deleted", gin.H{
@@ -412,13 +704,37 @@ func DeleteCard(c *gin.Context) {
 		})
 	}
 
-	// Adjust positions of remaining cards
-	database.DB.Model(&models.Card{}).
-		Where("list_id = ? AND position > ?", listID, card.Position).
-		UpdateColumn("position", gorm.Expr("position - 1"))
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Card deleted successfully",
 		"id":      cardID,
 	})
 }
+
+// wipWarning is returned when a card is added to a list over its soft WIP limit
+const wipWarning = "List is over its work-in-progress limit"
+
+// respondWIPLimit returns 409 for a card refused by a hard WIP limit
+func respondWIPLimit(c *gin.Context, wip *services.WIPCheck) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error":      "List is at its work-in-progress limit",
+		"list_id":    wip.ListID,
+		"wip_limit":  wip.Limit,
+		"card_count": wip.Count - 1,
+	})
+}
+
+// logWIPBreach records a card taking a list over its WIP limit, or being
+// refused by it, in the activity log
+func logWIPBreach(wip *services.WIPCheck, list *models.List, card *models.Card, userID uint) {
+	metadata := map[string]interface{}{
+		"card_title": card.Title,
+		"card_count": wip.Count,
+		"wip_limit":  wip.Limit,
+		"wip_mode":   wip.Mode,
+		"refused":    wip.Mode == services.WIPHard,
+	}
+	if card.ID != 0 {
+		metadata["card_id"] = card.ID
+	}
+	utils.LogActivity("exceeded_wip_limit", "list", list.ID, list.BoardID, userID, list.Title, metadata)
+}
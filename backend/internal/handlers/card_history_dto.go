@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/textdiff"
+)
+
+// CardRevisionResponse is one change in a card's history
+type CardRevisionResponse struct {
+	ID        uint            `json:"id"`
+	Field     string          `json:"field"`
+	OldValue  json.RawMessage `json:"old_value"`
+	NewValue  json.RawMessage `json:"new_value"`
+	User      *UserResponse   `json:"user"` // Nil for changes made by the system
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// RevisionDiffResponse is a line diff between two versions of a text field
+type RevisionDiffResponse struct {
+	RevisionID uint            `json:"revision_id"`
+	AgainstID  *uint           `json:"against_id"` // Revision compared with; nil for the revision's own old value
+	Field      string          `json:"field"`
+	Inserted   int             `json:"inserted"`
+	Deleted    int             `json:"deleted"`
+	Lines      []textdiff.Line `json:"lines"`
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/textdiff"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// revisionPaginator pages a card's history, newest first by default
+var revisionPaginator = &Paginator[models.CardRevision]{
+	Sorts: map[string]SortKey[models.CardRevision]{
+		"created_at": {Column: "card_revisions.created_at", Value: func(r models.CardRevision) interface{} { return r.CreatedAt }},
+	},
+	DefaultSort:  "-created_at",
+	IDColumn:     "card_revisions.id",
+	ID:           func(r models.CardRevision) uint { return r.ID },
+	DefaultLimit: 50,
+	MaxLimit:     200,
+}
+
+// GetCardHistory returns the changes to a card's title, description, due
+// date, list, labels and members, one page at a time. It can be narrowed to
+// one field with field.
+func GetCardHistory(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "view_board")
+	if !ok {
+		return
+	}
+
+	page, ok := revisionPaginator.Parse(c)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Where("card_revisions.card_id = ?", card.ID)
+	if field := c.Query("field"); field != "" {
+		if !services.IsRevisionField(field) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown field"})
+			return
+		}
+		query = query.Where("card_revisions.field = ?", field)
+	}
+
+	var revisions []models.CardRevision
+	hasMore, err := page.Find(query.Preload("User"), &revisions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch card history"})
+		return
+	}
+
+	response := make([]CardRevisionResponse, len(revisions))
+	for i := range revisions {
+		response[i] = cardRevisionResponse(&revisions[i])
+	}
+
+	page.Respond(c, "revisions", revisions, response, hasMore)
+}
+
+// GetCardRevisionDiff returns a line diff of a title or description
+// revision: from its old value to its new one, or from the new value of the
+// revision in against to its own
+func GetCardRevisionDiff(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "view_board")
+	if !ok {
+		return
+	}
+	revision, ok := findCardRevision(c, card.ID, c.Param("revision_id"))
+	if !ok {
+		return
+	}
+	if revision.Field != services.RevisionTitle && revision.Field != services.RevisionDescription {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only title and description revisions can be diffed"})
+		return
+	}
+
+	response := RevisionDiffResponse{RevisionID: revision.ID, Field: revision.Field}
+	oldValue := revision.OldValue
+	if value := c.Query("against"); value != "" {
+		against, ok := findCardRevision(c, card.ID, value)
+		if !ok {
+			return
+		}
+		if against.Field != revision.Field {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Revisions must be of the same field"})
+			return
+		}
+		oldValue = against.NewValue
+		response.AgainstID = &against.ID
+	}
+
+	var oldText, newText string
+	if json.Unmarshal(oldValue, &oldText) != nil || json.Unmarshal(revision.NewValue, &newText) != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read revision"})
+		return
+	}
+
+	response.Lines = textdiff.Lines(oldText, newText)
+	if response.Lines == nil {
+		response.Lines = []textdiff.Line{}
+	}
+	response.Inserted, response.Deleted = textdiff.Stats(response.Lines)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevertCardRevision sets a card's field back to the value a revision gave
+// it, or with before=true to the value it had just before the revision. The
+// revert is itself recorded as a new revision.
+func RevertCardRevision(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+	revision, ok := findCardRevision(c, card.ID, c.Param("revision_id"))
+	if !ok {
+		return
+	}
+
+	value := revision.NewValue
+	if c.Query("before") == "true" {
+		value = revision.OldValue
+	}
+
+	revisionService := &services.RevisionService{}
+	var change *models.FieldChange
+	var wip *services.WIPCheck
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		change, wip, err = revisionService.Revert(tx, card, revision.Field, value, userID)
+		return err
+	})
+	if errors.Is(err, services.ErrRevisionList) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrWIPLimit) {
+		respondWIPLimit(c, wip)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revert card"})
+		return
+	}
+
+	blockers, err := cardBlockers([]uint{card.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revert card"})
+		return
+	}
+	response := gin.H{
+		"message":  "Card reverted successfully",
+		"field":    revision.Field,
+		"reverted": change != nil,
+		"card":     scheduleCardResponse(card, blockers),
+	}
+	if wip != nil && wip.Over {
+		response["wip_warning"] = wipWarning
+	}
+
+	if change != nil {
+		utils.LogChanges("reverted_card", "card", card.ID, card.List.BoardID, userID, card.Title, utils.FieldChanges{*change}, map[string]interface{}{
+			"revision_id": revision.ID,
+		})
+
+		if WSHub != nil {
+			WSHub.BroadcastToBoard(card.List.BoardID, "card_reverted", gin.H{
+				"field": revision.Field,
+				"card":  response["card"],
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// findCardRevision loads a revision of the card. On failure it responds and
+// returns false.
+func findCardRevision(c *gin.Context, cardID uint, revisionID string) (*models.CardRevision, bool) {
+	id, err := strconv.ParseUint(revisionID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID"})
+		return nil, false
+	}
+
+	var revision models.CardRevision
+	if err := database.DB.Where("id = ? AND card_id = ?", id, cardID).First(&revision).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return nil, false
+	}
+	return &revision, true
+}
+
+// cardRevisionResponse converts a revision with its user loaded
+func cardRevisionResponse(revision *models.CardRevision) CardRevisionResponse {
+	response := CardRevisionResponse{
+		ID:        revision.ID,
+		Field:     revision.Field,
+		OldValue:  revision.OldValue,
+		NewValue:  revision.NewValue,
+		CreatedAt: revision.CreatedAt,
+	}
+	if revision.User != nil {
+		response.User = &UserResponse{
+			ID:        revision.User.ID,
+			Username:  revision.User.Username,
+			Email:     revision.User.Email,
+			AvatarURL: revision.User.AvatarURL,
+		}
+	}
+	return response
+}
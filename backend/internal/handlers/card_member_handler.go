@@ -5,7 +5,10 @@ import (
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AssignMemberToCard assigns a user to a card
@@ -50,11 +53,31 @@ func AssignMemberToCard(c *gin.Context) {
 	}
 
 	// Assign member to card (many-to-many)
-	if err := database.DB.Model(&card).Association("Members").Append(&member); err != nil {
+	revisionService := &services.RevisionService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return revisionService.TrackSet(tx, card.ID, userID, services.RevisionMembers, func() error {
+			return tx.Model(&card).Association("Members").Append(&member)
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign member to card"})
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("assigned_member", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"member_id": member.ID,
+		"member":    member.Username,
+	})
+
+	// Broadcast to WebSocket clients
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(card.List.Board.ID, "card_member_assigned", gin.H{
+			"card_id":   card.ID,
+			"member_id": member.ID,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "Member assigned to card successfully",
 		"card_id":   cardID,
@@ -130,11 +153,31 @@ func UnassignMemberFromCard(c *gin.Context) {
 	}
 
 	// Remove member from card
-	if err := database.DB.Model(&card).Association("Members").Delete(&member); err != nil {
+	revisionService := &services.RevisionService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return revisionService.TrackSet(tx, card.ID, userID, services.RevisionMembers, func() error {
+			return tx.Model(&card).Association("Members").Delete(&member)
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unassign member from card"})
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("unassigned_member", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"member_id": member.ID,
+		"member":    member.Username,
+	})
+
+	// Broadcast to WebSocket clients
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(card.List.Board.ID, "card_member_unassigned", gin.H{
+			"card_id":   card.ID,
+			"member_id": member.ID,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "Member unassigned from card successfully",
 		"card_id":   cardID,
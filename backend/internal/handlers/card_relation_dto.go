@@ -0,0 +1,27 @@
+package handlers
+
+import "time"
+
+// CreateCardRelationRequest links a card to another card, on any board the
+// user can see. blocked_by is stored as a blocks relation from the other card.
+type CreateCardRelationRequest struct {
+	CardID uint   `json:"card_id" binding:"required"`
+	Type   string `json:"type" binding:"required,oneof=blocks blocked_by relates_to"`
+}
+
+// CardRelationResponse is a relation seen from one of its cards
+type CardRelationResponse struct {
+	ID        uint                `json:"id"`
+	Type      string              `json:"type"` // blocks, blocked_by or relates_to, from this card's side
+	Card      RelatedCardResponse `json:"card"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// RelatedCardResponse is the card on the other side of a relation
+type RelatedCardResponse struct {
+	ID      uint   `json:"id"`
+	Title   string `json:"title"`
+	ListID  uint   `json:"list_id"`
+	BoardID uint   `json:"board_id"`
+	Done    bool   `json:"done"` // In a done-list
+}
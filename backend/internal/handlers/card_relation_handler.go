@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCardRelations returns a card's relations to cards on boards the user can see
+func GetCardRelations(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "view_board")
+	if !ok {
+		return
+	}
+
+	var relations []models.CardRelation
+	if err := database.DB.
+		Where("source_card_id = ? OR target_card_id = ?", card.ID, card.ID).
+		Order("created_at ASC").
+		Find(&relations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch relations"})
+		return
+	}
+
+	// Load the other side of each relation, skipping cards in the trash and
+	// on boards the user is not a member of
+	otherIDs := make([]uint, len(relations))
+	for i, relation := range relations {
+		otherIDs[i] = relation.SourceCardID
+		if relation.SourceCardID == card.ID {
+			otherIDs[i] = relation.TargetCardID
+		}
+	}
+
+	var others []models.Card
+	if err := database.DB.
+		Preload("List").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Joins("JOIN board_members ON board_members.board_id = lists.board_id AND board_members.user_id = ? AND board_members.status = ?", userID, "active").
+		Where("cards.id IN ?", otherIDs).
+		Find(&others).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch relations"})
+		return
+	}
+
+	byID := make(map[uint]models.Card, len(others))
+	for _, other := range others {
+		byID[other.ID] = other
+	}
+
+	response := make([]CardRelationResponse, 0, len(relations))
+	for i, relation := range relations {
+		other, ok := byID[otherIDs[i]]
+		if !ok {
+			continue
+		}
+		response = append(response, cardRelationResponse(&relation, card.ID, &other))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"relations": response,
+		"count":     len(response),
+	})
+}
+
+// CreateCardRelation links a card to another card, which may be on another board
+func CreateCardRelation(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req CreateCardRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	// The other card only needs to be visible to the user
+	var other models.Card
+	if err := database.DB.Preload("List").First(&other, req.CardID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Related card not found"})
+		return
+	}
+	permService := &services.PermissionService{}
+	if !permService.HasBoardAccess(userID, other.List.BoardID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Related card not found"})
+		return
+	}
+
+	sourceID, targetID, relationType := card.ID, other.ID, req.Type
+	if relationType == "blocked_by" {
+		sourceID, targetID, relationType = other.ID, card.ID, services.RelationBlocks
+	}
+
+	relationService := &services.RelationService{}
+	var relation *models.CardRelation
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		relation, err = relationService.Link(tx, sourceID, targetID, relationType, userID)
+		return err
+	})
+	switch err {
+	case nil:
+	case services.ErrSelfRelation:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case services.ErrRelationExists, services.ErrRelationCycle:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link cards"})
+		return
+	}
+
+	// Log activity
+	utils.LogActivity("linked_card", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"relation_id": relation.ID,
+		"type":        req.Type,
+		"card_id":     other.ID,
+		"card_title":  other.Title,
+	})
+
+	response := cardRelationResponse(relation, card.ID, &other)
+	broadcastRelation("card_relation_created", relation, card, &other)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// DeleteCardRelation removes a relation from either of its cards
+func DeleteCardRelation(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	var relation models.CardRelation
+	if err := database.DB.
+		Where("id = ? AND (source_card_id = ? OR target_card_id = ?)", c.Param("relation_id"), card.ID, card.ID).
+		First(&relation).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Relation not found"})
+		return
+	}
+
+	if err := database.DB.Delete(&relation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink cards"})
+		return
+	}
+
+	otherID := relation.SourceCardID
+	if otherID == card.ID {
+		otherID = relation.TargetCardID
+	}
+
+	// Log activity
+	utils.LogActivity("unlinked_card", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"relation_id": relation.ID,
+		"type":        relation.Type,
+		"card_id":     otherID,
+	})
+
+	var other models.Card
+	if err := database.DB.Preload("List").First(&other, otherID).Error; err == nil {
+		broadcastRelation("card_relation_deleted", &relation, card, &other)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cards unlinked successfully",
+		"id":      relation.ID,
+	})
+}
+
+// findPermittedCard loads a card and checks the user has permission on its
+// board. On failure it responds and returns false.
+func findPermittedCard(c *gin.Context, cardID string, userID uint, permission string) (*models.Card, bool) {
+	var card models.Card
+	if err := database.DB.Preload("List").First(&card, cardID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card not found"})
+		return nil, false
+	}
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, card.List.BoardID, permission) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return nil, false
+	}
+	return &card, true
+}
+
+// cardRelationResponse describes relation from the side of the card with cardID
+func cardRelationResponse(relation *models.CardRelation, cardID uint, other *models.Card) CardRelationResponse {
+	relationType := relation.Type
+	if relationType == services.RelationBlocks && relation.TargetCardID == cardID {
+		relationType = "blocked_by"
+	}
+
+	return CardRelationResponse{
+		ID:   relation.ID,
+		Type: relationType,
+		Card: RelatedCardResponse{
+			ID:      other.ID,
+			Title:   other.Title,
+			ListID:  other.ListID,
+			BoardID: other.List.BoardID,
+			Done:    other.List.IsDone,
+		},
+		CreatedAt: relation.CreatedAt,
+	}
+}
+
+// broadcastRelation tells both boards about a relation change, each from its
+// own card's side
+func broadcastRelation(event string, relation *models.CardRelation, card, other *models.Card) {
+	if WSHub == nil {
+		return
+	}
+
+	WSHub.BroadcastToBoard(card.List.BoardID, event, gin.H{
+		"card_id":  card.ID,
+		"relation": cardRelationResponse(relation, card.ID, other),
+	})
+	if other.List.BoardID != card.List.BoardID {
+		WSHub.BroadcastToBoard(other.List.BoardID, event, gin.H{
+			"card_id":  other.ID,
+			"relation": cardRelationResponse(relation, other.ID, card),
+		})
+	}
+}
+
+// cardBlockers returns the open blockers of each blocked card in cardIDs
+func cardBlockers(cardIDs []uint) (map[uint][]uint, error) {
+	relationService := &services.RelationService{}
+	return relationService.Blockers(database.DB, cardIDs)
+}
+
+// visibleBlockers keeps the blockers on boards the user is a member of. The
+// others still block their cards, but are only reported as a count.
+func visibleBlockers(blockers map[uint][]uint, userID uint) (map[uint][]uint, error) {
+	var ids []uint
+	for _, cardBlockers := range blockers {
+		ids = append(ids, cardBlockers...)
+	}
+	visible := make(map[uint][]uint)
+	if len(ids) == 0 {
+		return visible, nil
+	}
+
+	var visibleIDs []uint
+	if err := database.DB.Model(&models.Card{}).
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Joins("JOIN board_members ON board_members.board_id = lists.board_id AND board_members.user_id = ? AND board_members.status = ?", userID, "active").
+		Where("cards.id IN ?", ids).
+		Pluck("cards.id", &visibleIDs).Error; err != nil {
+		return nil, err
+	}
+	canSee := make(map[uint]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		canSee[id] = true
+	}
+
+	for cardID, cardBlockers := range blockers {
+		for _, blockerID := range cardBlockers {
+			if canSee[blockerID] {
+				visible[cardID] = append(visible[cardID], blockerID)
+			}
+		}
+	}
+	return visible, nil
+}
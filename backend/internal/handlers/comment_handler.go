@@ -5,6 +5,7 @@ import (
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -47,6 +48,11 @@ func CreateComment(c *gin.Context) {
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("created_comment", "comment", comment.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"card_id": card.ID,
+	})
+
 	// Load user info for response
 	database.DB.Preload("User").First(&comment, comment.ID)
 
@@ -64,7 +70,20 @@ func CreateComment(c *gin.Context) {
 	})
 }
 
-// GetComments returns all comments for a card
+// commentPaginator pages GetComments, oldest first by default
+var commentPaginator = &Paginator[models.Comment]{
+	Sorts: map[string]SortKey[models.Comment]{
+		"created_at": {Column: "comments.created_at", Value: func(c models.Comment) interface{} { return c.CreatedAt }},
+		"updated_at": {Column: "comments.updated_at", Value: func(c models.Comment) interface{} { return c.UpdatedAt }},
+	},
+	DefaultSort:  "created_at",
+	IDColumn:     "comments.id",
+	ID:           func(c models.Comment) uint { return c.ID },
+	DefaultLimit: 0, // Every row unless the client pages
+	MaxLimit:     200,
+}
+
+// GetComments returns the comments on a card, one page at a time
 func GetComments(c *gin.Context) {
 	cardID := c.Param("card_id")
 	userID := c.GetUint("user_id")
@@ -81,12 +100,15 @@ func GetComments(c *gin.Context) {
 		return
 	}
 
-	// Get all comments ordered by creation date
+	page, ok := commentPaginator.Parse(c)
+	if !ok {
+		return
+	}
+
+	// Get a page of comments, oldest first unless asked otherwise
 	var comments []models.Comment
-	if err := database.DB.Where("card_id = ?", cardID).
-		Preload("User").
-		Order("created_at ASC").
-		Find(&comments).Error; err != nil {
+	hasMore, err := page.Find(database.DB.Where("card_id = ?", cardID).Preload("User"), &comments)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
 		return
 	}
@@ -108,10 +130,7 @@ func GetComments(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"comments": response,
-		"count":    len(response),
-	})
+	page.Respond(c, "comments", comments, response, hasMore)
 }
 
 // UpdateComment updates a comment
@@ -142,12 +161,20 @@ func UpdateComment(c *gin.Context) {
 	}
 
 	// Update comment
+	oldContent := comment.Content
 	comment.Content = req.Content
 	if err := database.DB.Save(&comment).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
 		return
 	}
 
+	// Log activity
+	var changes utils.FieldChanges
+	changes.Add("content", oldContent, comment.Content)
+	utils.LogChanges("updated_comment", "comment", comment.ID, comment.Card.List.BoardID, userID, comment.Card.Title, changes, map[string]interface{}{
+		"card_id": comment.CardID,
+	})
+
 	// Load user for response
 	database.DB.Preload("User").First(&comment, comment.ID)
 
@@ -183,12 +210,17 @@ func DeleteComment(c *gin.Context) {
 		return
 	}
 
-	// Soft delete
-	if err := database.DB.Delete(&comment).Error; err != nil {
+	// Soft delete (moves the comment to the board's trash)
+	if err := moveToTrash(&comment, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("deleted_comment", "comment", comment.ID, comment.Card.List.BoardID, userID, comment.Card.Title, map[string]interface{}{
+		"card_id": comment.CardID,
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Comment deleted successfully",
 		"id":      commentID,
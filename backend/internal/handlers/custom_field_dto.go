@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateCustomFieldRequest represents input for defining a custom field on a board
+type CreateCustomFieldRequest struct {
+	Name    string   `json:"name" binding:"required,min=1,max=50"`
+	Type    string   `json:"type" binding:"required,oneof=text number date checkbox select multi_select"`
+	Options []string `json:"options" binding:"max=50,dive,required,max=50"` // Required for select and multi_select
+}
+
+// UpdateCustomFieldRequest represents input for renaming a custom field or
+// changing its options. The type cannot be changed.
+type UpdateCustomFieldRequest struct {
+	Name     string   `json:"name" binding:"omitempty,min=1,max=50"`
+	Options  []string `json:"options" binding:"omitempty,max=50,dive,required,max=50"` // Values using removed options are cleared
+	Position *int     `json:"position" binding:"omitempty,min=0"`
+}
+
+// SetCardFieldValueRequest sets a card's value for a custom field. The JSON
+// type of Value depends on the field: string (text, select), number, RFC 3339
+// date, boolean (checkbox) or list of strings (multi_select). null clears it.
+type SetCardFieldValueRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// CustomFieldResponse represents a custom field definition
+type CustomFieldResponse struct {
+	ID        uint      `json:"id"`
+	BoardID   uint      `json:"board_id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CardFieldValueResponse is a card's value for one custom field
+type CardFieldValueResponse struct {
+	FieldID uint        `json:"field_id"`
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Value   interface{} `json:"value"`
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCustomFields returns the custom fields defined on a board
+func GetCustomFields(c *gin.Context) {
+	boardID := c.Param("id")
+
+	var fields []models.CustomField
+	if err := database.DB.Where("board_id = ?", boardID).
+		Order("position ASC, id ASC").
+		Find(&fields).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom fields"})
+		return
+	}
+
+	response := make([]CustomFieldResponse, len(fields))
+	for i, field := range fields {
+		response[i] = customFieldResponse(&field)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"fields": response,
+		"count":  len(response),
+	})
+}
+
+// CreateCustomField defines a new custom field on a board
+func CreateCustomField(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	var req CreateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if msg := validateFieldOptions(req.Type, req.Options); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	// Check name is not already used on the board
+	var existing models.CustomField
+	if err := database.DB.Where("board_id = ? AND name = ?", boardID, req.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A field with this name already exists"})
+		return
+	}
+
+	// New fields go last
+	var count int64
+	database.DB.Model(&models.CustomField{}).Where("board_id = ?", boardID).Count(&count)
+
+	field := models.CustomField{
+		BoardID:  uint(boardID),
+		Name:     req.Name,
+		Type:     req.Type,
+		Options:  req.Options,
+		Position: int(count),
+	}
+	if !services.HasOptions(field.Type) {
+		field.Options = []string{}
+	}
+
+	if err := database.DB.Create(&field).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create custom field"})
+		return
+	}
+
+	utils.LogActivity("created_custom_field", "custom_field", field.ID, field.BoardID, userID, field.Name, map[string]interface{}{
+		"type": field.Type,
+	})
+
+	c.JSON(http.StatusCreated, customFieldResponse(&field))
+}
+
+// UpdateCustomField renames a custom field, reorders it or changes its options
+func UpdateCustomField(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req UpdateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	field, ok := findCustomField(c, userID)
+	if !ok {
+		return
+	}
+	before := *field
+
+	if req.Name != "" && req.Name != field.Name {
+		var existing models.CustomField
+		if err := database.DB.Where("board_id = ? AND name = ? AND id <> ?", field.BoardID, req.Name, field.ID).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "A field with this name already exists"})
+			return
+		}
+		field.Name = req.Name
+	}
+	if req.Position != nil {
+		field.Position = *req.Position
+	}
+
+	pruneOptions := false
+	if req.Options != nil {
+		if msg := validateFieldOptions(field.Type, req.Options); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		field.Options = req.Options
+		pruneOptions = services.HasOptions(field.Type)
+	}
+
+	customFieldService := &services.CustomFieldService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(field).Error; err != nil {
+			return err
+		}
+		if pruneOptions {
+			return customFieldService.PruneOptions(tx, field)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update custom field"})
+		return
+	}
+
+	var changes utils.FieldChanges
+	changes.Add("name", before.Name, field.Name)
+	changes.Add("position", before.Position, field.Position)
+	changes.Add("options", before.Options, field.Options)
+	utils.LogChanges("updated_custom_field", "custom_field", field.ID, field.BoardID, userID, field.Name, changes, nil)
+
+	c.JSON(http.StatusOK, customFieldResponse(field))
+}
+
+// DeleteCustomField deletes a custom field and every card's value for it
+func DeleteCustomField(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	field, ok := findCustomField(c, userID)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("field_id = ?", field.ID).Delete(&models.CardFieldValue{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(field).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete custom field"})
+		return
+	}
+
+	utils.LogActivity("deleted_custom_field", "custom_field", field.ID, field.BoardID, userID, field.Name, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Custom field deleted successfully",
+		"id":      field.ID,
+	})
+}
+
+// SetCardFieldValue sets or clears a card's value for a custom field
+func SetCardFieldValue(c *gin.Context) {
+	cardID := c.Param("id")
+	fieldID := c.Param("field_id")
+	userID := c.GetUint("user_id")
+
+	var req SetCardFieldValueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Find card
+	var card models.Card
+	if err := database.DB.Preload("List").First(&card, cardID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card not found"})
+		return
+	}
+	boardID := card.List.BoardID
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, boardID, "edit_card") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return
+	}
+
+	// The field must be defined on the card's board
+	var field models.CustomField
+	if err := database.DB.Where("id = ? AND board_id = ?", fieldID, boardID).First(&field).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+		return
+	}
+
+	customFieldService := &services.CustomFieldService{}
+	var value *models.CardFieldValue
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if value, err = customFieldService.SetValue(tx, &field, card.ID, req.Value); err != nil {
+			return err
+		}
+		return tx.Model(&card).Updates(map[string]interface{}{"version": gorm.Expr("version + 1")}).Error
+	})
+	if errors.Is(err, services.ErrInvalidFieldValue) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set field value"})
+		return
+	}
+
+	response := CardFieldValueResponse{FieldID: field.ID, Name: field.Name, Type: field.Type}
+	if value != nil {
+		response.Value = customFieldService.Value(&field, value)
+	}
+
+	// Log activity
+	utils.LogActivity("updated_card_field", "card", card.ID, boardID, userID, card.Title, map[string]interface{}{
+		"field_id": field.ID,
+		"field":    field.Name,
+		"value":    response.Value,
+	})
+
+	// Broadcast to WebSocket clients
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(boardID, "card_field_updated", gin.H{
+			"card_id": card.ID,
+			"field":   response,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// findCustomField loads the field in the :id param and checks the user may
+// edit its board. On failure it responds and returns false.
+func findCustomField(c *gin.Context, userID uint) (*models.CustomField, bool) {
+	var field models.CustomField
+	if err := database.DB.First(&field, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom field not found"})
+		return nil, false
+	}
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, field.BoardID, "edit_board") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return nil, false
+	}
+	return &field, true
+}
+
+// validateFieldOptions checks the options given for a field type, returning
+// an error message or ""
+func validateFieldOptions(fieldType string, options []string) string {
+	if !services.HasOptions(fieldType) {
+		if len(options) > 0 {
+			return "Only select and multi_select fields have options"
+		}
+		return ""
+	}
+
+	if len(options) == 0 {
+		return "Select fields need at least one option"
+	}
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if seen[option] {
+			return "Options must be unique"
+		}
+		seen[option] = true
+	}
+	return ""
+}
+
+func customFieldResponse(field *models.CustomField) CustomFieldResponse {
+	options := field.Options
+	if options == nil {
+		options = []string{}
+	}
+	return CustomFieldResponse{
+		ID:        field.ID,
+		BoardID:   field.BoardID,
+		Name:      field.Name,
+		Type:      field.Type,
+		Options:   options,
+		Position:  field.Position,
+		CreatedAt: field.CreatedAt,
+	}
+}
+
+// cardFieldValues loads the custom field values of cards, keyed by card ID
+// and ordered by field position
+func cardFieldValues(cardIDs []uint) (map[uint][]CardFieldValueResponse, error) {
+	byCard := make(map[uint][]CardFieldValueResponse, len(cardIDs))
+	if len(cardIDs) == 0 {
+		return byCard, nil
+	}
+
+	var values []models.CardFieldValue
+	if err := database.DB.
+		Joins("Field").
+		Where("card_field_values.card_id IN ?", cardIDs).
+		Order(`"Field".position ASC, "Field".id ASC`).
+		Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	customFieldService := &services.CustomFieldService{}
+	for _, value := range values {
+		byCard[value.CardID] = append(byCard[value.CardID], CardFieldValueResponse{
+			FieldID: value.FieldID,
+			Name:    value.Field.Name,
+			Type:    value.Field.Type,
+			Value:   customFieldService.Value(&value.Field, &value),
+		})
+	}
+	return byCard, nil
+}
+
+// cardFieldValueList returns a card's entry from cardFieldValues, never nil
+func cardFieldValueList(byCard map[uint][]CardFieldValueResponse, cardID uint) []CardFieldValueResponse {
+	if values, ok := byCard[cardID]; ok {
+		return values
+	}
+	return []CardFieldValueResponse{}
+}
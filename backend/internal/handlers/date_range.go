@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dateRange is an inclusive range of UTC calendar days
+type dateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// End returns the exclusive end of the range, midnight after its last day
+func (r dateRange) End() time.Time {
+	return r.To.AddDate(0, 0, 1)
+}
+
+// parseDateRange reads the from and to query parameters (YYYY-MM-DD) for a
+// range looking back. A missing to is today and a missing from makes the
+// range defaultDays long. On failure it responds and returns false.
+func parseDateRange(c *gin.Context, defaultDays, maxDays int) (dateRange, bool) {
+	var r dateRange
+	var ok bool
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if r.To, ok = queryDate(c, "to", today); !ok {
+		return r, false
+	}
+	if r.From, ok = queryDate(c, "from", r.To.AddDate(0, 0, 1-defaultDays)); !ok {
+		return r, false
+	}
+	return r, validateDateRange(c, r, maxDays)
+}
+
+// parseDateWindow is parseDateRange for a range looking ahead: a missing from
+// is today and a missing to makes the range defaultDays long
+func parseDateWindow(c *gin.Context, defaultDays, maxDays int) (dateRange, bool) {
+	var r dateRange
+	var ok bool
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if r.From, ok = queryDate(c, "from", today); !ok {
+		return r, false
+	}
+	if r.To, ok = queryDate(c, "to", r.From.AddDate(0, 0, defaultDays-1)); !ok {
+		return r, false
+	}
+	return r, validateDateRange(c, r, maxDays)
+}
+
+// queryDate reads a YYYY-MM-DD query parameter, or returns fallback when it
+// is missing. On failure it responds and returns false.
+func queryDate(c *gin.Context, name string, fallback time.Time) (time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, true
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + " date, expected YYYY-MM-DD"})
+		return date, false
+	}
+	return date, true
+}
+
+// validateDateRange checks the range is in order and at most maxDays long.
+// On failure it responds and returns false.
+func validateDateRange(c *gin.Context, r dateRange, maxDays int) bool {
+	if r.To.Before(r.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return false
+	}
+	if r.End().Sub(r.From) > time.Duration(maxDays)*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Date range is limited to %d days", maxDays)})
+		return false
+	}
+	return true
+}
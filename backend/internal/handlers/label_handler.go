@@ -5,7 +5,10 @@ import (
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // CreateLabel creates a label for a board
@@ -42,6 +45,11 @@ func CreateLabel(c *gin.Context) {
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("created_label", "label", label.ID, label.BoardID, userID, label.Name, map[string]interface{}{
+		"color": label.Color,
+	})
+
 	c.JSON(http.StatusCreated, LabelResponse{
 		ID:      label.ID,
 		Name:    label.Name,
@@ -114,6 +122,8 @@ func UpdateLabel(c *gin.Context) {
 		return
 	}
 
+	before := label
+
 	// Update fields
 	if req.Name != "" {
 		label.Name = req.Name
@@ -127,6 +137,12 @@ func UpdateLabel(c *gin.Context) {
 		return
 	}
 
+	// Log activity
+	var changes utils.FieldChanges
+	changes.Add("name", before.Name, label.Name)
+	changes.Add("color", before.Color, label.Color)
+	utils.LogChanges("updated_label", "label", label.ID, label.BoardID, userID, label.Name, changes, nil)
+
 	c.JSON(http.StatusOK, LabelResponse{
 		ID:      label.ID,
 		Name:    label.Name,
@@ -159,6 +175,9 @@ func DeleteLabel(c *gin.Context) {
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("deleted_label", "label", label.ID, label.BoardID, userID, label.Name, nil)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Label deleted successfully",
 		"id":      labelID,
@@ -206,11 +225,31 @@ func AddLabelToCard(c *gin.Context) {
 	}
 
 	// Add label to card (many-to-many)
-	if err := database.DB.Model(&card).Association("Labels").Append(&label); err != nil {
+	revisionService := &services.RevisionService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return revisionService.TrackSet(tx, card.ID, userID, services.RevisionLabels, func() error {
+			return tx.Model(&card).Association("Labels").Append(&label)
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add label to card"})
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("added_label", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"label_id": label.ID,
+		"label":    label.Name,
+	})
+
+	// Broadcast to WebSocket clients
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(card.List.Board.ID, "card_label_added", gin.H{
+			"card_id":  card.ID,
+			"label_id": label.ID,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "Label added to card successfully",
 		"card_id":  cardID,
@@ -245,11 +284,31 @@ func RemoveLabelFromCard(c *gin.Context) {
 	}
 
 	// Remove label from card
-	if err := database.DB.Model(&card).Association("Labels").Delete(&label); err != nil {
+	revisionService := &services.RevisionService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return revisionService.TrackSet(tx, card.ID, userID, services.RevisionLabels, func() error {
+			return tx.Model(&card).Association("Labels").Delete(&label)
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove label from card"})
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("removed_label", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"label_id": label.ID,
+		"label":    label.Name,
+	})
+
+	// Broadcast to WebSocket clients
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(card.List.Board.ID, "card_label_removed", gin.H{
+			"card_id":  card.ID,
+			"label_id": label.ID,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "Label removed from card successfully",
 		"card_id":  cardID,
@@ -0,0 +1,37 @@
+package handlers
+
+import "time"
+
+// CreateLaneRequest represents input for adding a swimlane to a board
+type CreateLaneRequest struct {
+	Title string `json:"title" binding:"required,min=1,max=100"`
+}
+
+// UpdateLaneRequest represents input for renaming or reordering a swimlane
+type UpdateLaneRequest struct {
+	Title    string `json:"title" binding:"omitempty,min=1,max=100"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+// LaneResponse represents a swimlane
+type LaneResponse struct {
+	ID        uint      `json:"id"`
+	BoardID   uint      `json:"board_id"`
+	Title     string    `json:"title"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BoardGroupResponse is one row of a grouped board: the cards of one lane,
+// assignee or label, split by list
+type BoardGroupResponse struct {
+	ID    *uint                    `json:"id"` // Nil for cards with no lane, assignee or label
+	Title string                   `json:"title"`
+	Lists []BoardGroupListResponse `json:"lists"`
+}
+
+// BoardGroupListResponse is the cards of a group in one list
+type BoardGroupListResponse struct {
+	ListID uint           `json:"list_id"`
+	Cards  []CardResponse `json:"cards"`
+}
@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Ways GetBoard can group cards
+const (
+	GroupByLane     = "lane"
+	GroupByAssignee = "assignee"
+	GroupByLabel    = "label"
+)
+
+// GetLanes returns a board's swimlanes in display order
+func GetLanes(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	lanes, err := boardLanes(uint(boardID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lanes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lanes": lanes,
+		"count": len(lanes),
+	})
+}
+
+// CreateLane adds a swimlane to the bottom of a board
+func CreateLane(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	var req CreateLaneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// New lanes go last
+	laneService := &services.LaneService{}
+	lane := models.Lane{
+		BoardID: uint(boardID),
+		Title:   req.Title,
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		return laneService.CreateLane(tx, &lane)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create lane"})
+		return
+	}
+
+	utils.LogActivity("created_lane", "lane", lane.ID, lane.BoardID, userID, lane.Title, nil)
+
+	response := laneResponse(&lane)
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(lane.BoardID, "lane_created", response)
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateLane renames or reorders a swimlane
+func UpdateLane(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req UpdateLaneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lane, ok := findLane(c, userID)
+	if !ok {
+		return
+	}
+	before := *lane
+
+	if req.Title != "" {
+		lane.Title = req.Title
+	}
+
+	// Moving a lane shifts the ones between its old and new place
+	laneService := &services.LaneService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(lane).UpdateColumns(map[string]interface{}{
+			"title":      lane.Title,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		if req.Position == nil {
+			return nil
+		}
+		return laneService.MoveLane(tx, lane, *req.Position)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update lane"})
+		return
+	}
+
+	var changes utils.FieldChanges
+	changes.Add("title", before.Title, lane.Title)
+	changes.Add("position", before.Position, lane.Position)
+	utils.LogChanges("updated_lane", "lane", lane.ID, lane.BoardID, userID, lane.Title, changes, nil)
+
+	response := laneResponse(lane)
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(lane.BoardID, "lane_updated", response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteLane deletes a swimlane. Its cards move to the default lane.
+func DeleteLane(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	lane, ok := findLane(c, userID)
+	if !ok {
+		return
+	}
+
+	laneService := &services.LaneService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return laneService.DeleteLane(tx, lane)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete lane"})
+		return
+	}
+
+	utils.LogActivity("deleted_lane", "lane", lane.ID, lane.BoardID, userID, lane.Title, nil)
+
+	// Clients move the lane's cards to the default lane and close the gap
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(lane.BoardID, "lane_deleted", gin.H{
+			"lane_id":  lane.ID,
+			"position": lane.Position,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Lane deleted successfully",
+		"id":      lane.ID,
+	})
+}
+
+// findLane loads the lane in the :id param and checks the user may edit its
+// board. On failure it responds and returns false.
+func findLane(c *gin.Context, userID uint) (*models.Lane, bool) {
+	var lane models.Lane
+	if err := database.DB.First(&lane, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lane not found"})
+		return nil, false
+	}
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, lane.BoardID, "edit_board") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return nil, false
+	}
+	return &lane, true
+}
+
+// laneOnBoard reports whether laneID is a lane of boardID
+func laneOnBoard(laneID, boardID uint) bool {
+	var lane models.Lane
+	return database.DB.Where("id = ? AND board_id = ?", laneID, boardID).First(&lane).Error == nil
+}
+
+// boardLanes returns a board's lanes in display order
+func boardLanes(boardID uint) ([]LaneResponse, error) {
+	var lanes []models.Lane
+	if err := database.DB.Where("board_id = ?", boardID).Order("position ASC, id ASC").Find(&lanes).Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]LaneResponse, len(lanes))
+	for i, lane := range lanes {
+		response[i] = laneResponse(&lane)
+	}
+	return response, nil
+}
+
+func laneResponse(lane *models.Lane) LaneResponse {
+	return LaneResponse{
+		ID:        lane.ID,
+		BoardID:   lane.BoardID,
+		Title:     lane.Title,
+		Position:  lane.Position,
+		CreatedAt: lane.CreatedAt,
+	}
+}
+
+// boardGroups splits the unarchived cards of lists into rows by lane,
+// assignee or label, each row holding every list in order. A card with
+// several assignees or labels appears in each of their rows. Cards with none
+// go in a last row without an ID.
+func boardGroups(lists []ListResponse, lanes []LaneResponse, groupBy string) ([]BoardGroupResponse, error) {
+	listIDs := make([]uint, len(lists))
+	listIndex := make(map[uint]int, len(lists))
+	for i, list := range lists {
+		listIDs[i] = list.ID
+		listIndex[list.ID] = i
+	}
+
+	query := database.DB.Where("list_id IN ? AND archived_at IS NULL", listIDs).Order("rank ASC, id ASC")
+	switch groupBy {
+	case GroupByAssignee:
+		query = query.Preload("Members")
+	case GroupByLabel:
+		query = query.Preload("Labels")
+	}
+	var cards []models.Card
+	if err := query.Find(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	blockers, err := cardBlockers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows in display order: lanes by position, people by username, labels by name
+	type row struct {
+		id    uint
+		title string
+	}
+	var rows []row
+	noneTitle := "No lane"
+	switch groupBy {
+	case GroupByLane:
+		for _, lane := range lanes {
+			rows = append(rows, row{lane.ID, lane.Title})
+		}
+	case GroupByAssignee:
+		noneTitle = "Unassigned"
+		seen := make(map[uint]bool)
+		for _, card := range cards {
+			for _, member := range card.Members {
+				if !seen[member.ID] {
+					seen[member.ID] = true
+					rows = append(rows, row{member.ID, member.Username})
+				}
+			}
+		}
+	case GroupByLabel:
+		noneTitle = "No label"
+		seen := make(map[uint]bool)
+		for _, card := range cards {
+			for _, label := range card.Labels {
+				if !seen[label.ID] {
+					seen[label.ID] = true
+					rows = append(rows, row{label.ID, label.Name})
+				}
+			}
+		}
+	}
+	if groupBy != GroupByLane {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].title < rows[j].title })
+	}
+
+	groups := make([]BoardGroupResponse, len(rows)+1)
+	groupIndex := make(map[uint]int, len(rows))
+	for i := range groups {
+		if i < len(rows) {
+			id := rows[i].id
+			groups[i].ID, groups[i].Title = &id, rows[i].title
+			groupIndex[id] = i
+		} else {
+			groups[i].Title = noneTitle
+		}
+		groups[i].Lists = make([]BoardGroupListResponse, len(lists))
+		for j, list := range lists {
+			groups[i].Lists[j] = BoardGroupListResponse{ListID: list.ID, Cards: []CardResponse{}}
+		}
+	}
+	none := len(rows)
+
+	for _, card := range cards {
+		var keys []uint
+		switch groupBy {
+		case GroupByLane:
+			if card.LaneID != nil {
+				keys = append(keys, *card.LaneID)
+			}
+		case GroupByAssignee:
+			for _, member := range card.Members {
+				keys = append(keys, member.ID)
+			}
+		case GroupByLabel:
+			for _, label := range card.Labels {
+				keys = append(keys, label.ID)
+			}
+		}
+
+		targets := make([]int, 0, len(keys))
+		for _, key := range keys {
+			if i, ok := groupIndex[key]; ok {
+				targets = append(targets, i)
+			}
+		}
+		if len(targets) == 0 {
+			targets = append(targets, none)
+		}
+
+		response := CardResponse{
+			ID:          card.ID,
+			Title:       card.Title,
+			Description: card.Description,
+			ListID:      card.ListID,
+			Rank:        card.Rank,
+			Version:     card.Version,
+			DueDate:     card.DueDate,
+			DueComplete: card.DueComplete,
+			LaneID:      card.LaneID,
+			Blocked:     len(blockers[card.ID]) > 0,
+			CreatedAt:   card.CreatedAt,
+		}
+		for _, i := range targets {
+			cell := &groups[i].Lists[listIndex[card.ListID]]
+			cell.Cards = append(cell.Cards, response)
+		}
+	}
+	return groups, nil
+}
@@ -4,19 +4,27 @@ import "time"
 
 // CreateListRequest represents input for creating a list
 type CreateListRequest struct {
-	Title   string `json:"title" binding:"required,min=1,max=100"`
-	BoardID uint   `json:"board_id" binding:"required"`
+	Title    string `json:"title" binding:"required,min=1,max=100"`
+	BoardID  uint   `json:"board_id" binding:"required"`
+	WIPLimit *int   `json:"wip_limit" binding:"omitempty,min=1"`
+	WIPMode  string `json:"wip_mode" binding:"omitempty,oneof=soft hard"` // Default soft
 }
 
 // UpdateListRequest represents input for updating a list
 type UpdateListRequest struct {
 	Title    string `json:"title" binding:"omitempty,min=1,max=100"`
-	Position *int   `json:"position" binding:"omitempty,min=0"` // Pointer = can be null
+	Position *int   `json:"position" binding:"omitempty,min=0"`           // Pointer = can be null
+	IsDone   *bool  `json:"is_done"`                                      // Marks the list as a done-list
+	WIPLimit *int   `json:"wip_limit" binding:"omitempty,min=0"`          // 0 removes the limit
+	WIPMode  string `json:"wip_mode" binding:"omitempty,oneof=soft hard"` // soft warns, hard refuses cards over the limit
+	Version  *int   `json:"version"`                                      // Optional: reject the update if the list changed since
 }
 
-// MoveListRequest for reordering lists
+// MoveListRequest for reordering lists. Version is optional; when given, a
+// stale move is rejected with 409.
 type MoveListRequest struct {
-	Position int `json:"position" binding:"required,min=0"`
+	Position int  `json:"position" binding:"required,min=0"`
+	Version  *int `json:"version"`
 }
 
 // ListDetailResponse includes cards
@@ -24,7 +32,11 @@ type ListDetailResponse struct {
 	ID        uint           `json:"id"`
 	Title     string         `json:"title"`
 	BoardID   uint           `json:"board_id"`
-	Position  int            `json:"position"`
+	Rank      string         `json:"rank"`
+	Version   int            `json:"version"`
+	IsDone    bool           `json:"is_done"`
+	WIPLimit  *int           `json:"wip_limit,omitempty"`
+	WIPMode   string         `json:"wip_mode"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	Cards     []CardResponse `json:"cards"`
@@ -36,7 +48,15 @@ type CardResponse struct {
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
 	ListID      uint       `json:"list_id"`
-	Position    int        `json:"position"`
+	Rank        string     `json:"rank"`
+	Version     int        `json:"version"`
 	DueDate     *time.Time `json:"due_date,omitempty"`
+	DueComplete bool       `json:"due_complete"`
+	LaneID      *uint      `json:"lane_id,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	Blocked     bool       `json:"blocked"` // Blocked by a card that is not done
 	CreatedAt   time.Time  `json:"created_at"`
+
+	// Planned start, shown with the due date on the timeline
+	StartDate *time.Time `json:"start_date,omitempty"`
 }
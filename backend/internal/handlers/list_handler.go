@@ -5,8 +5,11 @@ import (
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CreateList creates a new list in a board
@@ -27,30 +30,49 @@ func CreateList(c *gin.Context) {
 		return
 	}
 
-	// Get the highest position in this board
-	var maxPosition int
-	database.DB.Model(&models.List{}).
-		Where("board_id = ?", req.BoardID).
-		Select("COALESCE(MAX(position), -1)").
-		Scan(&maxPosition)
-
-	// Create list at the end (max position + 1)
+	// Create list at the end
 	list := models.List{
 		Title:    req.Title,
 		BoardID:  req.BoardID,
-		Position: maxPosition + 1,
+		WIPLimit: req.WIPLimit,
+		WIPMode:  req.WIPMode,
+	}
+	if list.WIPMode == "" {
+		list.WIPMode = services.WIPSoft
 	}
 
-	if err := database.DB.Create(&list).Error; err != nil {
+	// Lock the board so concurrent creates do not get the same rank
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Board{}, req.BoardID).Error; err != nil {
+			return err
+		}
+
+		ordering := &services.OrderingService{}
+		rank, err := ordering.NextListRank(tx, req.BoardID)
+		if err != nil {
+			return err
+		}
+		list.Rank = rank
+
+		return tx.Create(&list).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create list"})
 		return
 	}
 
+	// Log activity
+	utils.LogActivity("created_list", "list", list.ID, list.BoardID, userID, list.Title, nil)
+
 	c.JSON(http.StatusCreated, ListResponse{
 		ID:       list.ID,
 		Title:    list.Title,
 		BoardID:  list.BoardID,
-		Position: list.Position,
+		Rank:     list.Rank,
+		Version:  list.Version,
+		IsDone:   list.IsDone,
+		WIPLimit: list.WIPLimit,
+		WIPMode:  list.WIPMode,
 	})
 }
 
@@ -66,15 +88,26 @@ func GetLists(c *gin.Context) {
 		return
 	}
 
-	// Get all lists ordered by position
+	// Get all lists ordered by rank
 	var lists []models.List
 	if err := database.DB.Where("board_id = ?", boardID).
-		Order("position ASC").
+		Order("rank ASC, id ASC").
 		Find(&lists).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lists"})
 		return
 	}
 
+	listIDs := make([]uint, len(lists))
+	for i, list := range lists {
+		listIDs[i] = list.ID
+	}
+	listService := &services.ListService{}
+	counts, err := listService.CardCounts(database.DB, listIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count cards"})
+		return
+	}
+
 	// Convert to response
 	response := make([]ListResponse, len(lists))
 	for i, list := range lists {
@@ -82,8 +115,13 @@ func GetLists(c *gin.Context) {
 			ID:       list.ID,
 			Title:    list.Title,
 			BoardID:  list.BoardID,
-			Position: list.Position,
+			Rank:     list.Rank,
+			Version:  list.Version,
+			IsDone:   list.IsDone,
+			WIPLimit: list.WIPLimit,
+			WIPMode:  list.WIPMode,
 		}
+		setListCount(&response[i], counts[list.ID])
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -100,7 +138,7 @@ func GetList(c *gin.Context) {
 	// Find list and verify user owns the board
 	var list models.List
 	if err := database.DB.Preload("Cards", func(db *gorm.DB) *gorm.DB {
-		return db.Order("position ASC")
+		return db.Where("archived_at IS NULL").Order("rank ASC, id ASC")
 	}).First(&list, listID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
 		return
@@ -113,6 +151,16 @@ func GetList(c *gin.Context) {
 		return
 	}
 
+	ids := make([]uint, len(list.Cards))
+	for i, card := range list.Cards {
+		ids[i] = card.ID
+	}
+	blockers, err := cardBlockers(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch list"})
+		return
+	}
+
 	// Convert cards to response
 	cards := make([]CardResponse, len(list.Cards))
 	for i, card := range list.Cards {
@@ -121,8 +169,12 @@ func GetList(c *gin.Context) {
 			Title:       card.Title,
 			Description: card.Description,
 			ListID:      card.ListID,
-			Position:    card.Position,
+			Rank:        card.Rank,
+			Version:     card.Version,
 			DueDate:     card.DueDate,
+			DueComplete: card.DueComplete,
+			LaneID:      card.LaneID,
+			Blocked:     len(blockers[card.ID]) > 0,
 			CreatedAt:   card.CreatedAt,
 		}
 	}
@@ -131,7 +183,11 @@ func GetList(c *gin.Context) {
 		ID:        list.ID,
 		Title:     list.Title,
 		BoardID:   list.BoardID,
-		Position:  list.Position,
+		Rank:      list.Rank,
+		Version:   list.Version,
+		IsDone:    list.IsDone,
+		WIPLimit:  list.WIPLimit,
+		WIPMode:   list.WIPMode,
 		CreatedAt: list.CreatedAt,
 		UpdatedAt: list.UpdatedAt,
 		Cards:     cards,
@@ -163,24 +219,90 @@ func UpdateList(c *gin.Context) {
 		return
 	}
 
+	before := list
+
 	// Update fields
 	if req.Title != "" {
 		list.Title = req.Title
 	}
-	if req.Position != nil {
-		list.Position = *req.Position
+	if req.IsDone != nil {
+		list.IsDone = *req.IsDone
+	}
+	if req.WIPLimit != nil {
+		list.WIPLimit = req.WIPLimit
+		if *req.WIPLimit == 0 {
+			list.WIPLimit = nil
+		}
+	}
+	if req.WIPMode != "" {
+		list.WIPMode = req.WIPMode
+	}
+
+	// Only write the edited columns so a concurrent move is not overwritten
+	updates := map[string]interface{}{
+		"title":     list.Title,
+		"is_done":   list.IsDone,
+		"wip_limit": list.WIPLimit,
+		"wip_mode":  list.WIPMode,
+		"version":   gorm.Expr("version + 1"),
 	}
 
-	if err := database.DB.Save(&list).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if req.Position != nil {
+			// Re-rank within the board
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Board{}, list.BoardID).Error; err != nil {
+				return err
+			}
+			ordering := &services.OrderingService{}
+			rank, err := ordering.ListRankAt(tx, list.BoardID, *req.Position, list.ID)
+			if err != nil {
+				return err
+			}
+			list.Rank = rank
+			updates["rank"] = rank
+		}
+
+		query := tx.Model(&list)
+		if req.Version != nil {
+			query = query.Where("version = ?", *req.Version)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrStaleList
+		}
+		return nil
+	})
+	if err == services.ErrStaleList {
+		respondStaleList(c, list.ID)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update list"})
 		return
 	}
+	list.Version++
+
+	// Log activity
+	var changes utils.FieldChanges
+	changes.Add("title", before.Title, list.Title)
+	changes.Add("is_done", before.IsDone, list.IsDone)
+	changes.Add("wip_limit", before.WIPLimit, list.WIPLimit)
+	changes.Add("wip_mode", before.WIPMode, list.WIPMode)
+	changes.Add("rank", before.Rank, list.Rank)
+	utils.LogChanges("updated_list", "list", list.ID, list.BoardID, userID, list.Title, changes, nil)
 
 	c.JSON(http.StatusOK, ListResponse{
 		ID:       list.ID,
 		Title:    list.Title,
 		BoardID:  list.BoardID,
-		Position: list.Position,
+		Rank:     list.Rank,
+		Version:  list.Version,
+		IsDone:   list.IsDone,
+		WIPLimit: list.WIPLimit,
+		WIPMode:  list.WIPMode,
 	})
 }
 
@@ -209,30 +331,127 @@ func MoveList(c *gin.Context) {
 		return
 	}
 
-	oldPosition := list.Position
-	newPosition := req.Position
-
-	// Update positions of affected lists
-	if newPosition > oldPosition {
-		// Moving right: decrease position of lists in between
-		database.DB.Model(&models.List{}).
-			Where("board_id = ? AND position > ? AND position <= ?", list.BoardID, oldPosition, newPosition).
-			UpdateColumn("position", gorm.Expr("position - 1"))
-	} else if newPosition < oldPosition {
-		// Moving left: increase position of lists in between
-		database.DB.Model(&models.List{}).
-			Where("board_id = ? AND position >= ? AND position < ?", list.BoardID, newPosition, oldPosition).
-			UpdateColumn("position", gorm.Expr("position + 1"))
+	// Pick a rank between the new neighbours; other lists keep their keys
+	listService := &services.ListService{}
+	var moved *models.List
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		moved, err = listService.MoveList(tx, list.ID, req.Position, req.Version)
+		return err
+	})
+	if err == services.ErrStaleList {
+		respondStaleList(c, list.ID)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move list"})
+		return
 	}
 
-	// Update list position
-	list.Position = newPosition
-	database.DB.Save(&list)
+	// Log activity
+	var changes utils.FieldChanges
+	changes.Add("rank", list.Rank, moved.Rank)
+	utils.LogChanges("moved_list", "list", list.ID, list.BoardID, userID, list.Title, changes, map[string]interface{}{
+		"position": req.Position,
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":      "List moved successfully",
-		"id":           list.ID,
-		"new_position": newPosition,
+		"id":           moved.ID,
+		"new_position": req.Position,
+		"rank":         moved.Rank,
+		"version":      moved.Version,
+	})
+}
+
+// RepairListOrder rewrites a list's card ranks as evenly spaced keys,
+// keeping the current order. Used when a client detects a corrupted order.
+func RepairListOrder(c *gin.Context) {
+	listID := c.Param("id")
+	userID := c.GetUint("user_id")
+
+	// Find list
+	var list models.List
+	if err := database.DB.First(&list, listID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		return
+	}
+
+	// Verify ownership
+	var board models.Board
+	if err := database.DB.Where("id = ? AND owner_id = ?", list.BoardID, userID).First(&board).Error; err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	listService := &services.ListService{}
+	var cards []models.Card
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		cards, err = listService.RepairCardOrder(tx, list.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to repair list"})
+		return
+	}
+
+	// Log activity
+	utils.LogActivity("repaired_list_order", "list", list.ID, list.BoardID, userID, list.Title, map[string]interface{}{
+		"cards": len(cards),
+	})
+
+	order := make([]gin.H, len(cards))
+	for i, card := range cards {
+		order[i] = gin.H{
+			"id":       card.ID,
+			"position": i,
+			"rank":     card.Rank,
+			"version":  card.Version,
+		}
+	}
+
+	// Broadcast to WebSocket clients
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(list.BoardID, "cards_reordered", gin.H{
+			"list_id": list.ID,
+			"cards":   order,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "List order repaired",
+		"list_id": list.ID,
+		"cards":   order,
+	})
+}
+
+// respondStaleList returns 409 with the list's current state so the client can resync
+func respondStaleList(c *gin.Context, listID uint) {
+	var list models.List
+	if err := database.DB.First(&list, listID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		return
+	}
+
+	var position int64
+	database.DB.Model(&models.List{}).
+		Where("board_id = ? AND (rank < ? OR (rank = ? AND id < ?))", list.BoardID, list.Rank, list.Rank, list.ID).
+		Count(&position)
+
+	c.JSON(http.StatusConflict, gin.H{
+		"error":    "List was changed by someone else",
+		"position": position,
+		"list": ListResponse{
+			ID:       list.ID,
+			Title:    list.Title,
+			BoardID:  list.BoardID,
+			Rank:     list.Rank,
+			Version:  list.Version,
+			IsDone:   list.IsDone,
+			WIPLimit: list.WIPLimit,
+			WIPMode:  list.WIPMode,
+		},
 	})
 }
 
@@ -255,19 +474,23 @@ func DeleteList(c *gin.Context) {
 		return
 	}
 
-	// Soft delete (CASCADE will delete all cards)
-	if err := database.DB.Delete(&list).Error; err != nil {
+	// Soft delete (moves the list and its cards to the board's trash)
+	if err := moveToTrash(&list, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete list"})
 		return
 	}
 
-	// Adjust positions of remaining lists
-	database.DB.Model(&models.List{}).
-		Where("board_id = ? AND position > ?", list.BoardID, list.Position).
-		UpdateColumn("position", gorm.Expr("position - 1"))
+	// Log activity
+	utils.LogActivity("deleted_list", "list", list.ID, list.BoardID, userID, list.Title, nil)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "List deleted successfully",
 		"id":      listID,
 	})
 }
+
+// setListCount adds a list's card count and whether it is over its WIP limit
+func setListCount(response *ListResponse, count int) {
+	response.CardCount = &count
+	response.OverLimit = response.WIPLimit != nil && count > *response.WIPLimit
+}
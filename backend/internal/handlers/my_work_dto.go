@@ -0,0 +1,23 @@
+package handlers
+
+import "time"
+
+// WorkCardResponse is a card in the "my work" view, with where it lives
+type WorkCardResponse struct {
+	CardResponse
+	BoardID    uint            `json:"board_id"`
+	BoardTitle string          `json:"board_title"`
+	ListTitle  string          `json:"list_title"`
+	Labels     []LabelResponse `json:"labels"`
+}
+
+// MentionResponse is a comment that mentions the user
+type MentionResponse struct {
+	CommentID uint         `json:"comment_id"`
+	Content   string       `json:"content"`
+	CardID    uint         `json:"card_id"`
+	CardTitle string       `json:"card_title"`
+	BoardID   uint         `json:"board_id"`
+	Author    UserResponse `json:"author"`
+	CreatedAt time.Time    `json:"created_at"`
+}
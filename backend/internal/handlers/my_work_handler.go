@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// mentionLimit is how many recent mentions GetMyWork returns
+const mentionLimit = 20
+
+// GetMyWork returns the user's work across every board they belong to: open
+// cards assigned to them grouped by due date, recent comments mentioning
+// them and their running timers. Days are taken in the tz query parameter
+// (an IANA zone name), UTC by default.
+func GetMyWork(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz, expected an IANA time zone such as Europe/London"})
+			return
+		}
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	workService := &services.WorkService{}
+	cards, err := workService.AssignedCards(database.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assigned cards"})
+		return
+	}
+
+	ids := make([]uint, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	blockers, err := cardBlockers(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assigned cards"})
+		return
+	}
+
+	now := time.Now()
+	buckets := make(map[string][]WorkCardResponse, len(services.DueBuckets))
+	counts := make(map[string]int, len(services.DueBuckets))
+	for _, bucket := range services.DueBuckets {
+		buckets[bucket] = []WorkCardResponse{}
+		counts[bucket] = 0
+	}
+	for _, card := range cards {
+		labels := make([]LabelResponse, len(card.Labels))
+		for j, label := range card.Labels {
+			labels[j] = LabelResponse{
+				ID:      label.ID,
+				Name:    label.Name,
+				Color:   label.Color,
+				BoardID: label.BoardID,
+			}
+		}
+
+		bucket := workService.DueBucket(card.DueDate, now, loc)
+		buckets[bucket] = append(buckets[bucket], WorkCardResponse{
+			CardResponse: CardResponse{
+				ID:          card.ID,
+				Title:       card.Title,
+				Description: card.Description,
+				ListID:      card.ListID,
+				Rank:        card.Rank,
+				Version:     card.Version,
+				DueDate:     card.DueDate,
+				DueComplete: card.DueComplete,
+				LaneID:      card.LaneID,
+				Blocked:     len(blockers[card.ID]) > 0,
+				CreatedAt:   card.CreatedAt,
+			},
+			BoardID:    card.List.BoardID,
+			BoardTitle: card.List.Board.Title,
+			ListTitle:  card.List.Title,
+			Labels:     labels,
+		})
+		counts[bucket]++
+	}
+
+	comments, err := workService.Mentions(database.DB, &user, mentionLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mentions"})
+		return
+	}
+	mentions := make([]MentionResponse, len(comments))
+	for i, comment := range comments {
+		mentions[i] = MentionResponse{
+			CommentID: comment.ID,
+			Content:   comment.Content,
+			CardID:    comment.CardID,
+			CardTitle: comment.Card.Title,
+			BoardID:   comment.Card.List.BoardID,
+			Author: UserResponse{
+				ID:        comment.User.ID,
+				Username:  comment.User.Username,
+				Email:     comment.User.Email,
+				AvatarURL: comment.User.AvatarURL,
+			},
+			CreatedAt: comment.CreatedAt,
+		}
+	}
+
+	timeService := &services.TimeService{}
+	timers := []TimeEntryResponse{}
+	running, err := timeService.RunningTimer(database.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timers"})
+		return
+	}
+	if running != nil {
+		running.User = user
+		timers = append(timers, timeEntryResponse(running, now))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cards":          buckets,
+		"counts":         counts,
+		"mentions":       mentions,
+		"running_timers": timers,
+		"timezone":       loc.String(),
+	})
+}
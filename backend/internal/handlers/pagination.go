@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SortKey is a column a list endpoint can be sorted by. The column must be
+// NOT NULL so that keyset comparisons never skip rows.
+type SortKey[T any] struct {
+	Column string              // Qualified SQL column, e.g. "cards.created_at"
+	Value  func(T) interface{} // Reads the column's value from a loaded row
+}
+
+// Paginator pages a list endpoint with keyset cursors. Clients pass:
+//
+//	limit  - page size, up to MaxLimit
+//	sort   - a key of Sorts, prefixed with "-" for descending order
+//	after  - the next_cursor of the previous page
+//	fields - comma-separated JSON fields to keep in each item
+//
+// Rows with equal sort values are ordered by ID, so pages never overlap.
+//
+// A DefaultLimit of 0 keeps an endpoint that predates paging unbounded:
+// without limit or after every row is returned, and the response's limit is
+// null. Paging starts when the client asks for it.
+type Paginator[T any] struct {
+	Sorts        map[string]SortKey[T]
+	DefaultSort  string
+	IDColumn     string
+	ID           func(T) uint
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Page is the parsed paging request for one call
+type Page[T any] struct {
+	paginator *Paginator[T]
+	Limit     int    // 0 for every row
+	Sort      string // As given by the client, e.g. "-created_at"
+	key       SortKey[T]
+	desc      bool
+	after     *pageCursor
+	fields    []string
+}
+
+// pageCursor is the position after the last row of a page
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// Parse reads the paging parameters. On invalid input it responds with 400
+// and returns false.
+func (p *Paginator[T]) Parse(c *gin.Context) (*Page[T], bool) {
+	page := &Page[T]{paginator: p, Limit: p.DefaultLimit, Sort: c.DefaultQuery("sort", p.DefaultSort)}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > p.MaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", p.MaxLimit)})
+			return nil, false
+		}
+		page.Limit = limit
+	}
+
+	name := strings.TrimPrefix(page.Sort, "-")
+	key, ok := p.Sorts[name]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort, use one of: " + strings.Join(p.sortNames(), ", ")})
+		return nil, false
+	}
+	page.key = key
+	page.desc = strings.HasPrefix(page.Sort, "-")
+
+	if value := c.Query("after"); value != "" {
+		cursor, err := decodePageCursor(value)
+		if err != nil || cursor.Sort != page.Sort {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return nil, false
+		}
+		page.after = cursor
+		if page.Limit == 0 {
+			page.Limit = p.MaxLimit
+		}
+	}
+
+	if value := c.Query("fields"); value != "" {
+		page.fields = strings.Split(value, ",")
+	}
+
+	return page, true
+}
+
+// Find loads one page into dest. It fetches one extra row to tell whether
+// another page follows, and reports it.
+func (page *Page[T]) Find(db *gorm.DB, dest *[]T) (bool, error) {
+	p := page.paginator
+	op, direction := ">", "ASC"
+	if page.desc {
+		op, direction = "<", "DESC"
+	}
+
+	if page.after != nil {
+		db = db.Where(
+			fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", page.key.Column, op, page.key.Column, p.IDColumn, op),
+			page.after.Value, page.after.Value, page.after.ID,
+		)
+	}
+
+	db = db.Order(page.key.Column + " " + direction).
+		Order(p.IDColumn + " " + direction)
+	if page.Limit > 0 {
+		db = db.Limit(page.Limit + 1)
+	}
+	if err := db.Find(dest).Error; err != nil {
+		return false, err
+	}
+
+	hasMore := page.Limit > 0 && len(*dest) > page.Limit
+	if hasMore {
+		*dest = (*dest)[:page.Limit]
+	}
+	return hasMore, nil
+}
+
+// Respond writes items under key with paging metadata. When another page
+// follows, next_cursor is set and a Link header points at it. limit is the
+// page size, null when every row was returned.
+func (page *Page[T]) Respond(c *gin.Context, key string, rows []T, items interface{}, hasMore bool) {
+	var nextCursor *string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		encoded := encodePageCursor(pageCursor{
+			Sort:  page.Sort,
+			Value: formatSortValue(page.key.Value(last)),
+			ID:    page.paginator.ID(last),
+		})
+		nextCursor = &encoded
+
+		next := *c.Request.URL
+		query := next.Query()
+		query.Set("after", encoded)
+		query.Set("limit", strconv.Itoa(page.Limit))
+		query.Set("sort", page.Sort)
+		next.RawQuery = query.Encode()
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+
+	selected, err := selectFields(items, page.fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode response"})
+		return
+	}
+
+	var limit *int
+	if page.Limit > 0 {
+		limit = &page.Limit
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		key:           selected,
+		"count":       len(rows),
+		"limit":       limit,
+		"has_more":    hasMore,
+		"next_cursor": nextCursor,
+	})
+}
+
+func (p *Paginator[T]) sortNames() []string {
+	names := make([]string, 0, len(p.Sorts))
+	for name := range p.Sorts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatSortValue renders a sort value so Postgres can compare it with the column
+func formatSortValue(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+func encodePageCursor(cursor pageCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageCursor(encoded string) (*pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// selectFields keeps only the requested top-level JSON fields of each item.
+// Unknown field names are ignored. With no fields, items are returned as is.
+func selectFields(items interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var decoded []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+
+	selected := make([]map[string]json.RawMessage, len(decoded))
+	for i, item := range decoded {
+		selected[i] = make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := item[strings.TrimSpace(field)]; ok {
+				selected[i][strings.TrimSpace(field)] = value
+			}
+		}
+	}
+	return selected, nil
+}
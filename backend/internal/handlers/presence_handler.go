@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+// GetBoardPresence returns the users viewing a board over WebSocket
+// connections to any instance of the API, with the card each has open and
+// whether they are idle
+func GetBoardPresence(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	viewers := []ws.Viewer{}
+	if WSHub != nil {
+		viewers = WSHub.Presence(uint(boardID))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board_id": boardID,
+		"count":    len(viewers),
+		"viewers":  viewers,
+	})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import "time"
+
+// CardRecurrenceRequest sets how a card recurs. Each occurrence adds a copy
+// of the card to the target list.
+type CardRecurrenceRequest struct {
+	Frequency    string     `json:"frequency" binding:"required,oneof=daily weekly monthly cron"`
+	Interval     int        `json:"interval" binding:"omitempty,min=1,max=365"`        // Every n days, weeks or months; default 1
+	Weekdays     []int      `json:"weekdays" binding:"max=7,dive,min=0,max=6"`         // Weekly: 0 (Sunday) to 6, default the start's weekday
+	DayOfMonth   int        `json:"day_of_month" binding:"omitempty,min=1,max=31"`     // Monthly: default the start's day
+	TimeOfDay    string     `json:"time_of_day" binding:"omitempty,datetime=15:04"`    // HH:MM, default 09:00
+	Cron         string     `json:"cron" binding:"required_if=Frequency cron,max=100"` // Cron: minute hour day-of-month month day-of-week
+	Timezone     string     `json:"timezone" binding:"max=64"`                         // IANA name, default UTC
+	StartsAt     *time.Time `json:"starts_at"`                                         // Default now
+	TargetListID uint       `json:"target_list_id"`                                    // Default the card's list
+	Paused       bool       `json:"paused"`
+}
+
+// CardRecurrenceResponse represents a card's recurrence with its next occurrences
+type CardRecurrenceResponse struct {
+	ID           uint        `json:"id"`
+	CardID       uint        `json:"card_id"`
+	TargetListID uint        `json:"target_list_id"`
+	Frequency    string      `json:"frequency"`
+	Interval     int         `json:"interval"`
+	Weekdays     []int       `json:"weekdays"`
+	DayOfMonth   int         `json:"day_of_month,omitempty"`
+	TimeOfDay    string      `json:"time_of_day,omitempty"`
+	Cron         string      `json:"cron,omitempty"`
+	Timezone     string      `json:"timezone"`
+	StartsAt     time.Time   `json:"starts_at"`
+	NextRunAt    *time.Time  `json:"next_run_at"` // Null while paused
+	LastRunAt    *time.Time  `json:"last_run_at,omitempty"`
+	Paused       bool        `json:"paused"`
+	Upcoming     []time.Time `json:"upcoming"` // The next few occurrences
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// upcomingOccurrences is how many future occurrences a recurrence response lists
+const upcomingOccurrences = 5
+
+// GetCardRecurrence returns a card's recurrence
+func GetCardRecurrence(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "view_board")
+	if !ok {
+		return
+	}
+
+	schedule, ok := findCardRecurrence(c, card.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, cardRecurrenceResponse(schedule))
+}
+
+// SetCardRecurrence creates or replaces a card's recurrence
+func SetCardRecurrence(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req CardRecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	// Copies go to a list on the same board
+	targetListID := card.ListID
+	if req.TargetListID != 0 {
+		var target models.List
+		if err := database.DB.Where("id = ? AND board_id = ?", req.TargetListID, card.List.BoardID).First(&target).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Target list must be on the card's board"})
+			return
+		}
+		targetListID = target.ID
+	}
+
+	now := time.Now()
+	schedule := models.CardRecurrence{CardID: card.ID, CreatedBy: userID}
+	database.DB.Where("card_id = ?", card.ID).First(&schedule)
+
+	schedule.TargetListID = targetListID
+	schedule.Frequency = req.Frequency
+	schedule.Interval = max(req.Interval, 1)
+	schedule.Weekdays = req.Weekdays
+	schedule.DayOfMonth = req.DayOfMonth
+	schedule.TimeOfDay = req.TimeOfDay
+	schedule.Cron = req.Cron
+	schedule.Timezone = req.Timezone
+	schedule.StartsAt = now
+	schedule.Paused = req.Paused
+	if schedule.Weekdays == nil {
+		schedule.Weekdays = []int{}
+	}
+	if schedule.TimeOfDay == "" {
+		schedule.TimeOfDay = "09:00"
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	if req.StartsAt != nil {
+		schedule.StartsAt = *req.StartsAt
+	}
+
+	// Validates the rule; occurrences before now are never made
+	recurrenceService := &services.RecurrenceService{}
+	if err := recurrenceService.ScheduleNext(&schedule, now); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if schedule.ID == 0 {
+		status = http.StatusCreated
+	}
+	if err := database.DB.Save(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save recurrence"})
+		return
+	}
+
+	// Log activity
+	utils.LogActivity("updated_card_recurrence", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"frequency": schedule.Frequency,
+		"paused":    schedule.Paused,
+	})
+
+	c.JSON(status, cardRecurrenceResponse(&schedule))
+}
+
+// PauseCardRecurrence stops a card's recurrence until it is resumed
+func PauseCardRecurrence(c *gin.Context) {
+	setCardRecurrencePaused(c, true)
+}
+
+// ResumeCardRecurrence restarts a paused recurrence. Occurrences missed while
+// paused are skipped.
+func ResumeCardRecurrence(c *gin.Context) {
+	setCardRecurrencePaused(c, false)
+}
+
+func setCardRecurrencePaused(c *gin.Context, paused bool) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	schedule, ok := findCardRecurrence(c, card.ID)
+	if !ok {
+		return
+	}
+
+	updates := map[string]interface{}{"paused": paused}
+	if !paused {
+		recurrenceService := &services.RecurrenceService{}
+		if err := recurrenceService.ScheduleNext(schedule, time.Now()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+			return
+		}
+		updates["next_run_at"] = schedule.NextRunAt
+	}
+
+	if err := database.DB.Model(schedule).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update recurrence"})
+		return
+	}
+
+	action := "resumed_card_recurrence"
+	if paused {
+		action = "paused_card_recurrence"
+	}
+	utils.LogActivity(action, "card", card.ID, card.List.BoardID, userID, card.Title, nil)
+
+	c.JSON(http.StatusOK, cardRecurrenceResponse(schedule))
+}
+
+// DeleteCardRecurrence stops a card from recurring. Copies already made stay.
+func DeleteCardRecurrence(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	schedule, ok := findCardRecurrence(c, card.ID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete recurrence"})
+		return
+	}
+
+	utils.LogActivity("deleted_card_recurrence", "card", card.ID, card.List.BoardID, userID, card.Title, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recurrence deleted successfully",
+		"id":      schedule.ID,
+	})
+}
+
+// findCardRecurrence loads a card's recurrence, responding 404 if it has none
+func findCardRecurrence(c *gin.Context, cardID uint) (*models.CardRecurrence, bool) {
+	var schedule models.CardRecurrence
+	if err := database.DB.Where("card_id = ?", cardID).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card does not recur"})
+		return nil, false
+	}
+	return &schedule, true
+}
+
+func cardRecurrenceResponse(schedule *models.CardRecurrence) CardRecurrenceResponse {
+	response := CardRecurrenceResponse{
+		ID:           schedule.ID,
+		CardID:       schedule.CardID,
+		TargetListID: schedule.TargetListID,
+		Frequency:    schedule.Frequency,
+		Interval:     schedule.Interval,
+		Weekdays:     schedule.Weekdays,
+		DayOfMonth:   schedule.DayOfMonth,
+		TimeOfDay:    schedule.TimeOfDay,
+		Cron:         schedule.Cron,
+		Timezone:     schedule.Timezone,
+		StartsAt:     schedule.StartsAt,
+		LastRunAt:    schedule.LastRunAt,
+		Paused:       schedule.Paused,
+		Upcoming:     []time.Time{},
+		CreatedAt:    schedule.CreatedAt,
+		UpdatedAt:    schedule.UpdatedAt,
+	}
+	if response.Weekdays == nil {
+		response.Weekdays = []int{}
+	}
+	if schedule.Paused {
+		return response
+	}
+
+	response.NextRunAt = &schedule.NextRunAt
+	recurrenceService := &services.RecurrenceService{}
+	if rule, err := recurrenceService.Rule(schedule); err == nil {
+		for at := schedule.NextRunAt; !at.IsZero() && len(response.Upcoming) < upcomingOccurrences; at = rule.Next(schedule.StartsAt, at) {
+			response.Upcoming = append(response.Upcoming, at)
+		}
+	}
+	return response
+}
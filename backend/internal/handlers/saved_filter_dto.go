@@ -0,0 +1,28 @@
+package handlers
+
+import "time"
+
+// SavedFilterRequest represents input for creating or updating a saved filter
+type SavedFilterRequest struct {
+	Name            string `json:"name" binding:"required,min=1,max=100"`
+	Query           string `json:"query" binding:"required,max=1000"`
+	ShowOnDashboard bool   `json:"show_on_dashboard"`
+}
+
+// SavedFilterResponse represents a saved filter in API responses
+type SavedFilterResponse struct {
+	ID              uint      `json:"id"`
+	Name            string    `json:"name"`
+	Query           string    `json:"query"`
+	ShowOnDashboard bool      `json:"show_on_dashboard"`
+	FeedURL         string    `json:"feed_url,omitempty"` // Only set while the RSS feed is enabled
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// DashboardPanel is one saved filter on the dashboard with its newest cards
+type DashboardPanel struct {
+	Filter SavedFilterResponse `json:"filter"`
+	Count  int64               `json:"count"`
+	Cards  []CardResponse      `json:"cards"`
+}
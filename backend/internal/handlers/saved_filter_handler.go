@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/search"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// APIBaseURL is the public URL of this API, used to build feed links. Set from main.
+var APIBaseURL string
+
+// FrontendURL is the web app's URL, used to link feed items to cards. Set from main.
+var FrontendURL string
+
+// dashboardCardLimit is how many cards each dashboard panel shows
+const dashboardCardLimit = 5
+
+// feedItemLimit is how many cards an RSS feed lists
+const feedItemLimit = 50
+
+// GetSavedFilters returns the user's saved filters
+func GetSavedFilters(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var filters []models.SavedFilter
+	if err := database.DB.Where("user_id = ?", userID).Order("name ASC").Find(&filters).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch filters"})
+		return
+	}
+
+	response := make([]SavedFilterResponse, len(filters))
+	for i, filter := range filters {
+		response[i] = savedFilterResponse(&filter)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"filters": response,
+		"count":   len(response),
+	})
+}
+
+// CreateSavedFilter saves a named search query
+func CreateSavedFilter(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req SavedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := search.Parse(req.Query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query: " + err.Error()})
+		return
+	}
+
+	// Check name is not already used
+	var existing models.SavedFilter
+	if err := database.DB.Where("user_id = ? AND name = ?", userID, req.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A filter with this name already exists"})
+		return
+	}
+
+	filter := models.SavedFilter{
+		UserID:          userID,
+		Name:            req.Name,
+		Query:           req.Query,
+		ShowOnDashboard: req.ShowOnDashboard,
+	}
+
+	if err := database.DB.Create(&filter).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save filter"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, savedFilterResponse(&filter))
+}
+
+// UpdateSavedFilter renames or changes a saved filter
+func UpdateSavedFilter(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req SavedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, ok := findSavedFilter(c, userID)
+	if !ok {
+		return
+	}
+
+	if _, err := search.Parse(req.Query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query: " + err.Error()})
+		return
+	}
+
+	// Check name is not used by another filter
+	var existing models.SavedFilter
+	if err := database.DB.Where("user_id = ? AND name = ? AND id <> ?", userID, req.Name, filter.ID).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A filter with this name already exists"})
+		return
+	}
+
+	filter.Name = req.Name
+	filter.Query = req.Query
+	filter.ShowOnDashboard = req.ShowOnDashboard
+
+	if err := database.DB.Save(filter).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update filter"})
+		return
+	}
+
+	c.JSON(http.StatusOK, savedFilterResponse(filter))
+}
+
+// DeleteSavedFilter deletes a saved filter and disables its feed
+func DeleteSavedFilter(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	filter, ok := findSavedFilter(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(filter).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete filter"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Filter deleted successfully",
+		"id":      filter.ID,
+	})
+}
+
+// GetSavedFilterCards runs a saved filter and returns a page of matching cards
+func GetSavedFilterCards(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	filter, ok := findSavedFilter(c, userID)
+	if !ok {
+		return
+	}
+
+	parsed, err := search.Parse(filter.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query: " + err.Error()})
+		return
+	}
+
+	respondCardSearch(c, services.SearchFilter{Query: parsed}, gin.H{
+		"filter": savedFilterResponse(filter),
+	})
+}
+
+// EnableSavedFilterFeed creates a new secret RSS feed URL for a filter.
+// Calling it again replaces the token, so the old URL stops working.
+func EnableSavedFilterFeed(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	filter, ok := findSavedFilter(c, userID)
+	if !ok {
+		return
+	}
+
+	token, err := utils.GenerateToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable feed"})
+		return
+	}
+
+	if err := database.DB.Model(filter).Update("feed_token", token).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable feed"})
+		return
+	}
+	filter.FeedToken = &token
+
+	c.JSON(http.StatusOK, savedFilterResponse(filter))
+}
+
+// DisableSavedFilterFeed revokes a filter's RSS feed URL
+func DisableSavedFilterFeed(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	filter, ok := findSavedFilter(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Model(filter).Update("feed_token", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable feed"})
+		return
+	}
+	filter.FeedToken = nil
+
+	c.JSON(http.StatusOK, savedFilterResponse(filter))
+}
+
+// GetDashboard returns each saved filter marked for the dashboard with its
+// match count and most recently updated cards
+func GetDashboard(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var filters []models.SavedFilter
+	if err := database.DB.Where("user_id = ? AND show_on_dashboard = ?", userID, true).
+		Order("name ASC").
+		Find(&filters).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dashboard"})
+		return
+	}
+
+	searchService := &services.SearchService{}
+	panels := make([]DashboardPanel, 0, len(filters))
+	for i := range filters {
+		parsed, err := search.Parse(filters[i].Query)
+		if err != nil {
+			continue // Saved before a grammar change; shown in GetSavedFilters to fix
+		}
+		searchFilter := services.SearchFilter{Query: parsed}
+
+		count, err := searchService.CountCards(userID, searchFilter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dashboard"})
+			return
+		}
+
+		ids, err := searchService.RecentCards(userID, searchFilter, dashboardCardLimit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dashboard"})
+			return
+		}
+
+		var cards []models.Card
+		if len(ids) > 0 {
+			database.DB.Where("id IN ?", ids).Order("updated_at DESC, id DESC").Find(&cards)
+		}
+
+		response := make([]CardResponse, len(cards))
+		for j, card := range cards {
+			response[j] = CardResponse{
+				ID:          card.ID,
+				Title:       card.Title,
+				Description: card.Description,
+				ListID:      card.ListID,
+				Rank:        card.Rank,
+				Version:     card.Version,
+				DueDate:     card.DueDate,
+				DueComplete: card.DueComplete,
+				LaneID:      card.LaneID,
+				CreatedAt:   card.CreatedAt,
+			}
+		}
+
+		panels = append(panels, DashboardPanel{
+			Filter: savedFilterResponse(&filters[i]),
+			Count:  count,
+			Cards:  response,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"panels": panels,
+	})
+}
+
+// rssFeed is the subset of RSS 2.0 used by saved filter feeds
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// GetSavedFilterFeed serves a saved filter as an RSS feed. It is public: the
+// token in the URL identifies the filter, and cards are still limited to
+// boards the filter's owner is a member of.
+func GetSavedFilterFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".rss")
+
+	var filter models.SavedFilter
+	if token == "" || database.DB.Where("feed_token = ?", token).First(&filter).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+
+	parsed, err := search.Parse(filter.Query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query: " + err.Error()})
+		return
+	}
+
+	searchService := &services.SearchService{}
+	ids, err := searchService.RecentCards(filter.UserID, services.SearchFilter{Query: parsed}, feedItemLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+
+	var cards []models.Card
+	if len(ids) > 0 {
+		database.DB.Preload("List").Where("id IN ?", ids).Order("updated_at DESC, id DESC").Find(&cards)
+	}
+
+	items := make([]rssItem, len(cards))
+	for i, card := range cards {
+		items[i] = rssItem{
+			Title:       card.Title,
+			Link:        cardURL(&card),
+			Description: card.Description,
+			GUID:        rssGUID{Value: fmt.Sprintf("flowboard-card-%d-v%d", card.ID, card.Version)},
+			PubDate:     card.UpdatedAt.UTC().Format(http.TimeFormat),
+		}
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       "FlowBoard: " + filter.Name,
+			Link:        FrontendURL,
+			Description: filter.Query,
+			Items:       items,
+		},
+	}
+
+	c.Header("Content-Type", "application/rss+xml; charset=utf-8")
+	c.Status(http.StatusOK)
+	c.Writer.WriteString(xml.Header)
+	if err := xml.NewEncoder(c.Writer).Encode(feed); err != nil {
+		c.Error(err)
+	}
+}
+
+// findSavedFilter loads the :id filter owned by the user, responding 404 if it does not exist
+func findSavedFilter(c *gin.Context, userID uint) (*models.SavedFilter, bool) {
+	var filter models.SavedFilter
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&filter).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Filter not found"})
+		return nil, false
+	}
+	return &filter, true
+}
+
+// savedFilterResponse converts a saved filter, including its feed URL when enabled
+func savedFilterResponse(filter *models.SavedFilter) SavedFilterResponse {
+	response := SavedFilterResponse{
+		ID:              filter.ID,
+		Name:            filter.Name,
+		Query:           filter.Query,
+		ShowOnDashboard: filter.ShowOnDashboard,
+		CreatedAt:       filter.CreatedAt,
+		UpdatedAt:       filter.UpdatedAt,
+	}
+	if filter.FeedToken != nil {
+		response.FeedURL = APIBaseURL + "/api/v1/feeds/filters/" + *filter.FeedToken + ".rss"
+	}
+	return response
+}
+
+// cardURL links to a card's board in the web app, or returns "" when the app URL is not configured
+func cardURL(card *models.Card) string {
+	if FrontendURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/board/%d", strings.TrimRight(FrontendURL, "/"), card.List.BoardID)
+}
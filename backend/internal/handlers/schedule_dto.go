@@ -0,0 +1,34 @@
+package handlers
+
+import "time"
+
+// RescheduleCardRequest represents input for dragging a card to new dates.
+// Omitted dates are left as they are; the clear flags remove one.
+type RescheduleCardRequest struct {
+	StartDate      *time.Time `json:"start_date"`
+	DueDate        *time.Time `json:"due_date"`
+	ClearStartDate bool       `json:"clear_start_date"`
+	ClearDueDate   bool       `json:"clear_due_date"`
+	Version        *int       `json:"version"` // Optional: reject the change if the card changed since
+}
+
+// CalendarPeriodResponse is the cards due in one day, week or month
+type CalendarPeriodResponse struct {
+	Start string         `json:"start"` // First day, YYYY-MM-DD
+	End   string         `json:"end"`   // Last day, YYYY-MM-DD
+	Cards []CardResponse `json:"cards"`
+}
+
+// TimelineCardResponse is a card as a bar on the timeline
+type TimelineCardResponse struct {
+	CardResponse
+	Start time.Time `json:"start"` // Start date, or the due date if it has none
+	End   time.Time `json:"end"`   // Due date, or the start date if it has none
+}
+
+// DependencyResponse is a blocking edge between two timeline cards
+type DependencyResponse struct {
+	ID           uint `json:"id"`
+	SourceCardID uint `json:"source_card_id"` // The blocking card
+	TargetCardID uint `json:"target_card_id"` // The blocked card
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errStartAfterDue refuses a reschedule that would start a card after it is due
+var errStartAfterDue = errors.New("start_date must not be after due_date")
+
+// Calendar and timeline date range limits
+const (
+	scheduleDefaultDays = 35
+	scheduleMaxDays     = 366
+)
+
+// GetBoardCalendar returns a board's cards due between from and to (default
+// the next five weeks), grouped by group_by: day (default), week or month.
+// Every period overlapping the range is listed, including empty ones.
+func GetBoardCalendar(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	period := c.DefaultQuery("group_by", services.PeriodDay)
+	if period != services.PeriodDay && period != services.PeriodWeek && period != services.PeriodMonth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be day, week or month"})
+		return
+	}
+
+	dates, ok := parseDateWindow(c, scheduleDefaultDays, scheduleMaxDays)
+	if !ok {
+		return
+	}
+
+	calendarService := &services.CalendarService{}
+	cards, err := calendarService.DueCards(database.DB, uint(boardID), dates.From, dates.End())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch calendar"})
+		return
+	}
+	blockers, err := cardBlockers(cardIDs(cards))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch calendar"})
+		return
+	}
+
+	periods := []CalendarPeriodResponse{}
+	index := make(map[string]int) // By first day
+	for start := calendarService.PeriodStart(dates.From, period); start.Before(dates.End()); start = calendarService.NextPeriod(start, period) {
+		index[start.Format("2006-01-02")] = len(periods)
+		periods = append(periods, CalendarPeriodResponse{
+			Start: start.Format("2006-01-02"),
+			End:   calendarService.NextPeriod(start, period).AddDate(0, 0, -1).Format("2006-01-02"),
+			Cards: []CardResponse{},
+		})
+	}
+	for i := range cards {
+		p := &periods[index[calendarService.PeriodStart(*cards[i].DueDate, period).Format("2006-01-02")]]
+		p.Cards = append(p.Cards, scheduleCardResponse(&cards[i], blockers))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board_id": boardID,
+		"from":     dates.From.Format("2006-01-02"),
+		"to":       dates.To.Format("2006-01-02"),
+		"group_by": period,
+		"periods":  periods,
+		"total":    len(cards),
+	})
+}
+
+// GetBoardTimeline returns a board's cards whose start-to-due span overlaps
+// from and to (default the next five weeks), with the blocking relations
+// between them as dependency edges
+func GetBoardTimeline(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	dates, ok := parseDateWindow(c, scheduleDefaultDays, scheduleMaxDays)
+	if !ok {
+		return
+	}
+
+	calendarService := &services.CalendarService{}
+	cards, err := calendarService.TimelineCards(database.DB, uint(boardID), dates.From, dates.End())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timeline"})
+		return
+	}
+	blockers, err := cardBlockers(cardIDs(cards))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timeline"})
+		return
+	}
+
+	items := make([]TimelineCardResponse, len(cards))
+	for i := range cards {
+		card := &cards[i]
+		start, end := card.StartDate, card.DueDate
+		if start == nil {
+			start = end
+		}
+		if end == nil {
+			end = start
+		}
+		items[i] = TimelineCardResponse{
+			CardResponse: scheduleCardResponse(card, blockers),
+			Start:        *start,
+			End:          *end,
+		}
+	}
+
+	relationService := &services.RelationService{}
+	relations, err := relationService.Dependencies(database.DB, cardIDs(cards))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timeline"})
+		return
+	}
+	dependencies := make([]DependencyResponse, len(relations))
+	for i, relation := range relations {
+		dependencies[i] = DependencyResponse{
+			ID:           relation.ID,
+			SourceCardID: relation.SourceCardID,
+			TargetCardID: relation.TargetCardID,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board_id":     boardID,
+		"from":         dates.From.Format("2006-01-02"),
+		"to":           dates.To.Format("2006-01-02"),
+		"cards":        items,
+		"dependencies": dependencies,
+	})
+}
+
+// RescheduleCard changes a card's start and due dates, as when it is dragged
+// on the calendar or timeline
+func RescheduleCard(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req RescheduleCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.StartDate == nil && req.DueDate == nil && !req.ClearStartDate && !req.ClearDueDate {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to reschedule"})
+		return
+	}
+	if (req.StartDate != nil && req.ClearStartDate) || (req.DueDate != nil && req.ClearDueDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A date cannot be both set and cleared"})
+		return
+	}
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	// The dates are changed on the card as locked inside the transaction, so
+	// the revisions written with it record the values they replaced
+	var startDate, dueDate *time.Time
+	var changes utils.FieldChanges
+	cardService := &services.CardService{}
+	revisionService := &services.RevisionService{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		locked, err := cardService.LockCard(tx, card.ID)
+		if err != nil {
+			return err
+		}
+		card.StartDate, card.DueDate, card.Version = locked.StartDate, locked.DueDate, locked.Version
+
+		startDate, dueDate = card.StartDate, card.DueDate
+		if req.StartDate != nil || req.ClearStartDate {
+			startDate = req.StartDate
+		}
+		if req.DueDate != nil || req.ClearDueDate {
+			dueDate = req.DueDate
+		}
+		if startDate != nil && dueDate != nil && startDate.After(*dueDate) {
+			return errStartAfterDue
+		}
+
+		changes = nil
+		changes.Add("start_date", card.StartDate, startDate)
+		changes.Add("due_date", card.DueDate, dueDate)
+
+		query := tx.Model(card)
+		if req.Version != nil {
+			query = query.Where("version = ?", *req.Version)
+		}
+		result := query.Updates(map[string]interface{}{
+			"start_date": startDate,
+			"due_date":   dueDate,
+			"version":    gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrStaleCard
+		}
+		return revisionService.Record(tx, card.ID, userID, changes)
+	})
+	if err == errStartAfterDue {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err == services.ErrStaleCard {
+		respondStaleCard(c, card.ID)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reschedule card"})
+		return
+	}
+
+	utils.LogChanges("rescheduled_card", "card", card.ID, card.List.BoardID, userID, card.Title, changes, nil)
+
+	card.StartDate, card.DueDate = startDate, dueDate
+	card.Version++
+
+	blockers, err := cardBlockers([]uint{card.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reschedule card"})
+		return
+	}
+	response := scheduleCardResponse(card, blockers)
+
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(card.List.BoardID, "card_rescheduled", response)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// cardIDs returns the IDs of cards
+func cardIDs(cards []models.Card) []uint {
+	ids := make([]uint, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	return ids
+}
+
+// scheduleCardResponse converts a card for the calendar and timeline
+func scheduleCardResponse(card *models.Card, blockers map[uint][]uint) CardResponse {
+	return CardResponse{
+		ID:          card.ID,
+		Title:       card.Title,
+		Description: card.Description,
+		ListID:      card.ListID,
+		Rank:        card.Rank,
+		Version:     card.Version,
+		DueDate:     card.DueDate,
+		DueComplete: card.DueComplete,
+		LaneID:      card.LaneID,
+		Blocked:     len(blockers[card.ID]) > 0,
+		CreatedAt:   card.CreatedAt,
+		StartDate:   card.StartDate,
+	}
+}
@@ -2,79 +2,132 @@ package handlers
 
 import (
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/search"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-// SearchCards searches cards across all user's boards
+// CardSearchResult is a card found by SearchCards, with its score and the
+// highlighted snippets that matched
+type CardSearchResult struct {
+	CardDetailResponse
+	BoardID    uint              `json:"board_id"`
+	Score      float32           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// SearchCards runs a ranked search across every board the user is a member of.
+// q uses the search query grammar, e.g. `label:bug AND member:me AND due<7d NOT list:Done`.
 func SearchCards(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	query := c.Query("q")                 // Search query
-	boardID := c.Query("board")           // Optional: filter by board
-	listID := c.Query("list")             // Optional: filter by list
-	labelID := c.Query("label")           // Optional: filter by label
-	memberID := c.Query("member")         // Optional: filter by assigned member
-	hasDueDate := c.Query("has_due_date") // Optional: filter cards with due dates
-
-	// Build query
-	db := database.DB.Model(&models.Card{}).
-		Joins("JOIN lists ON lists.id = cards.list_id").
-		Joins("JOIN boards ON boards.id = lists.board_id").
-		Where("boards.owner_id = ?", userID)
+	query := c.Query("q") // Search query
 
-	// Search by title or description
-	if query != "" {
-		searchTerm := "%" + strings.ToLower(query) + "%"
-		db = db.Where("LOWER(cards.title) LIKE ? OR LOWER(cards.description) LIKE ?", searchTerm, searchTerm)
+	parsed, err := search.Parse(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query: " + err.Error()})
+		return
 	}
 
-	// Filter by board
-	if boardID != "" {
-		db = db.Where("boards.id = ?", boardID)
+	filter := services.SearchFilter{Query: parsed}
+	for param, target := range map[string]*uint{
+		"board":  &filter.BoardID,  // Optional: filter by board
+		"list":   &filter.ListID,   // Optional: filter by list
+		"label":  &filter.LabelID,  // Optional: filter by label
+		"member": &filter.MemberID, // Optional: filter by assigned member
+	} {
+		if value := c.Query(param); value != "" {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " filter"})
+				return
+			}
+			*target = uint(id)
+		}
 	}
 
-	// Filter by list
-	if listID != "" {
-		db = db.Where("cards.list_id = ?", listID)
+	// Optional: filter cards with due dates
+	if hasDueDate := c.Query("has_due_date"); hasDueDate == "true" || hasDueDate == "false" {
+		value := hasDueDate == "true"
+		filter.HasDueDate = &value
 	}
 
-	// Filter by label
-	if labelID != "" {
-		db = db.Joins("JOIN card_labels ON card_labels.card_id = cards.id").
-			Where("card_labels.label_id = ?", labelID)
+	respondCardSearch(c, filter, gin.H{"query": query})
+}
+
+// respondCardSearch runs a search for the current user and writes one page of
+// results, reading limit and cursor from the query string. extra is merged
+// into the response.
+func respondCardSearch(c *gin.Context, filter services.SearchFilter, extra gin.H) {
+	userID := c.GetUint("user_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 50"})
+		return
+	}
+
+	var after *services.SearchCursor
+	if cursor := c.Query("cursor"); cursor != "" {
+		if after, err = services.DecodeSearchCursor(cursor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 	}
 
-	// Filter by assigned member
-	if memberID != "" {
-		db = db.Joins("JOIN card_members ON card_members.card_id = cards.id").
-			Where("card_members.user_id = ?", memberID)
+	searchService := &services.SearchService{}
+	hits, next, err := searchService.SearchCards(userID, filter, after, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search cards"})
+		return
 	}
 
-	// Filter by due date presence
-	if hasDueDate == "true" {
-		db = db.Where("cards.due_date IS NOT NULL")
-	} else if hasDueDate == "false" {
-		db = db.Where("cards.due_date IS NULL")
+	// Load the matched cards; hits are already in rank order
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.CardID
 	}
 
-	// Get cards
 	var cards []models.Card
-	if err := db.Preload("List").
-		Preload("Members").
+	if err := database.DB.Preload("Members").
 		Preload("Labels").
-		Order("cards.created_at DESC").
-		Limit(50). // Limit to 50 results
+		Where("id IN ?", ids).
 		Find(&cards).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search cards"})
 		return
 	}
 
+	byID := make(map[uint]models.Card, len(cards))
+	for _, card := range cards {
+		byID[card.ID] = card
+	}
+
+	fieldValues, err := cardFieldValues(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search cards"})
+		return
+	}
+	blockers, err := cardBlockers(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search cards"})
+		return
+	}
+	shownBlockers, err := visibleBlockers(blockers, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search cards"})
+		return
+	}
+
 	// Convert to response
-	response := make([]CardDetailResponse, len(cards))
-	for i, card := range cards {
+	response := make([]CardSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		card, ok := byID[hit.CardID]
+		if !ok {
+			continue // Deleted between the two queries
+		}
+
 		// Members
 		members := make([]UserResponse, len(card.Members))
 		for j, member := range card.Members {
@@ -97,27 +150,49 @@ func SearchCards(c *gin.Context) {
 			}
 		}
 
-		response[i] = CardDetailResponse{
-			ID:          card.ID,
-			Title:       card.Title,
-			Description: card.Description,
-			ListID:      card.ListID,
-			Position:    card.Position,
-			DueDate:     card.DueDate,
-			CreatedAt:   card.CreatedAt,
-			UpdatedAt:   card.UpdatedAt,
-			Members:     members,
-			Labels:      labels,
-			Comments:    []CommentResponse{},    
-			Attachments: []AttachmentResponse{}, 
-		}
+		response = append(response, CardSearchResult{
+			CardDetailResponse: CardDetailResponse{
+				ID:           card.ID,
+				Title:        card.Title,
+				Description:  card.Description,
+				ListID:       card.ListID,
+				Rank:         card.Rank,
+				Version:      card.Version,
+				DueDate:      card.DueDate,
+				DueComplete:  card.DueComplete,
+				LaneID:       card.LaneID,
+				Blocked:      len(blockers[card.ID]) > 0,
+				BlockedBy:    shownBlockers[card.ID],
+				HiddenBlocks: len(blockers[card.ID]) - len(shownBlockers[card.ID]),
+				CreatedAt:    card.CreatedAt,
+				UpdatedAt:    card.UpdatedAt,
+				Members:      members,
+				Labels:       labels,
+				Comments:     []CommentResponse{},
+				Attachments:  []AttachmentResponse{},
+				CustomFields: cardFieldValueList(fieldValues, card.ID),
+			},
+			BoardID:    hit.BoardID,
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"cards": response,
-		"count": len(response),
-		"query": query,
-	})
+	var nextCursor *string
+	if next != nil {
+		encoded := next.Encode()
+		nextCursor = &encoded
+	}
+
+	body := gin.H{
+		"cards":       response,
+		"count":       len(response),
+		"next_cursor": nextCursor,
+	}
+	for key, value := range extra {
+		body[key] = value
+	}
+	c.JSON(http.StatusOK, body)
 }
 
 // GetOverdueCards returns cards with due dates in the past
@@ -125,10 +200,10 @@ func GetOverdueCards(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
 	var cards []models.Card
-	if err := database.DB.
+	if err := services.MemberBoards(database.DB.
 		Joins("JOIN lists ON lists.id = cards.list_id").
-		Joins("JOIN boards ON boards.id = lists.board_id").
-		Where("boards.owner_id = ? AND cards.due_date < NOW() AND cards.due_date IS NOT NULL", userID).
+		Joins("JOIN boards ON boards.id = lists.board_id"), userID).
+		Where("cards.due_date < NOW() AND cards.due_date IS NOT NULL").
 		Preload("List").
 		Preload("Members").
 		Preload("Labels").
@@ -138,6 +213,26 @@ func GetOverdueCards(c *gin.Context) {
 		return
 	}
 
+	ids := make([]uint, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	fieldValues, err := cardFieldValues(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overdue cards"})
+		return
+	}
+	blockers, err := cardBlockers(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overdue cards"})
+		return
+	}
+	shownBlockers, err := visibleBlockers(blockers, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overdue cards"})
+		return
+	}
+
 	// Convert to response
 	response := make([]CardDetailResponse, len(cards))
 	for i, card := range cards {
@@ -164,18 +259,25 @@ func GetOverdueCards(c *gin.Context) {
 		}
 
 		response[i] = CardDetailResponse{
-			ID:          card.ID,
-			Title:       card.Title,
-			Description: card.Description,
-			ListID:      card.ListID,
-			Position:    card.Position,
-			DueDate:     card.DueDate,
-			CreatedAt:   card.CreatedAt,
-			UpdatedAt:   card.UpdatedAt,
-			Members:     members,
-			Labels:      labels,
-			Comments:    []CommentResponse{},
-			Attachments: []AttachmentResponse{},
+			ID:           card.ID,
+			Title:        card.Title,
+			Description:  card.Description,
+			ListID:       card.ListID,
+			Rank:         card.Rank,
+			Version:      card.Version,
+			DueDate:      card.DueDate,
+			DueComplete:  card.DueComplete,
+			LaneID:       card.LaneID,
+			Blocked:      len(blockers[card.ID]) > 0,
+			BlockedBy:    shownBlockers[card.ID],
+			HiddenBlocks: len(blockers[card.ID]) - len(shownBlockers[card.ID]),
+			CreatedAt:    card.CreatedAt,
+			UpdatedAt:    card.UpdatedAt,
+			Members:      members,
+			Labels:       labels,
+			Comments:     []CommentResponse{},
+			Attachments:  []AttachmentResponse{},
+			CustomFields: cardFieldValueList(fieldValues, card.ID),
 		}
 	}
 
@@ -190,10 +292,10 @@ func GetUpcomingCards(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
 	var cards []models.Card
-	if err := database.DB.
+	if err := services.MemberBoards(database.DB.
 		Joins("JOIN lists ON lists.id = cards.list_id").
-		Joins("JOIN boards ON boards.id = lists.board_id").
-		Where("boards.owner_id = ? AND cards.due_date BETWEEN NOW() AND NOW() + INTERVAL '7 days'", userID).
+		Joins("JOIN boards ON boards.id = lists.board_id"), userID).
+		Where("cards.due_date BETWEEN NOW() AND NOW() + INTERVAL '7 days'").
 		Preload("List").
 		Preload("Members").
 		Preload("Labels").
@@ -203,6 +305,26 @@ func GetUpcomingCards(c *gin.Context) {
 		return
 	}
 
+	ids := make([]uint, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	fieldValues, err := cardFieldValues(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upcoming cards"})
+		return
+	}
+	blockers, err := cardBlockers(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upcoming cards"})
+		return
+	}
+	shownBlockers, err := visibleBlockers(blockers, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch upcoming cards"})
+		return
+	}
+
 	// Convert to response
 	response := make([]CardDetailResponse, len(cards))
 	for i, card := range cards {
@@ -229,18 +351,25 @@ func GetUpcomingCards(c *gin.Context) {
 		}
 
 		response[i] = CardDetailResponse{
-			ID:          card.ID,
-			Title:       card.Title,
-			Description: card.Description,
-			ListID:      card.ListID,
-			Position:    card.Position,
-			DueDate:     card.DueDate,
-			CreatedAt:   card.CreatedAt,
-			UpdatedAt:   card.UpdatedAt,
-			Members:     members,
-			Labels:      labels,
-			Comments:    []CommentResponse{},
-			Attachments: []AttachmentResponse{},
+			ID:           card.ID,
+			Title:        card.Title,
+			Description:  card.Description,
+			ListID:       card.ListID,
+			Rank:         card.Rank,
+			Version:      card.Version,
+			DueDate:      card.DueDate,
+			DueComplete:  card.DueComplete,
+			LaneID:       card.LaneID,
+			Blocked:      len(blockers[card.ID]) > 0,
+			BlockedBy:    shownBlockers[card.ID],
+			HiddenBlocks: len(blockers[card.ID]) - len(shownBlockers[card.ID]),
+			CreatedAt:    card.CreatedAt,
+			UpdatedAt:    card.UpdatedAt,
+			Members:      members,
+			Labels:       labels,
+			Comments:     []CommentResponse{},
+			Attachments:  []AttachmentResponse{},
+			CustomFields: cardFieldValueList(fieldValues, card.ID),
 		}
 	}
 
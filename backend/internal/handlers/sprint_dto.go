@@ -0,0 +1,72 @@
+package handlers
+
+import "time"
+
+// CreateSprintRequest represents input for planning a sprint or milestone.
+// Dates are YYYY-MM-DD and both days are included.
+type CreateSprintRequest struct {
+	Name          string `json:"name" binding:"required,min=1,max=100"`
+	Goal          string `json:"goal" binding:"max=2000"`
+	StartDate     string `json:"start_date" binding:"required"`
+	EndDate       string `json:"end_date" binding:"required"`
+	LabelID       *uint  `json:"label_id"`        // Cards with this label are in the sprint
+	PointsFieldID *uint  `json:"points_field_id"` // Number custom field holding story points
+	CardIDs       []uint `json:"card_ids" binding:"max=500"`
+}
+
+// UpdateSprintRequest represents input for changing a sprint. For label_id
+// and points_field_id, 0 clears the setting.
+type UpdateSprintRequest struct {
+	Name          string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Goal          *string `json:"goal" binding:"omitempty,max=2000"`
+	StartDate     string  `json:"start_date"`
+	EndDate       string  `json:"end_date"`
+	LabelID       *uint   `json:"label_id"`
+	PointsFieldID *uint   `json:"points_field_id"`
+}
+
+// SprintCardsRequest represents cards to add to a sprint
+type SprintCardsRequest struct {
+	CardIDs []uint `json:"card_ids" binding:"required,min=1,max=500"`
+}
+
+// SprintResponse represents a sprint
+type SprintResponse struct {
+	ID            uint      `json:"id"`
+	BoardID       uint      `json:"board_id"`
+	Name          string    `json:"name"`
+	Goal          string    `json:"goal"`
+	StartDate     string    `json:"start_date"`
+	EndDate       string    `json:"end_date"`
+	LabelID       *uint     `json:"label_id,omitempty"`
+	PointsFieldID *uint     `json:"points_field_id,omitempty"`
+	CreatedBy     uint      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SprintDetailResponse is a sprint with the cards currently in it
+type SprintDetailResponse struct {
+	SprintResponse
+	Cards []CardResponse `json:"cards"`
+}
+
+// BurndownDayResponse is the work left at the end of one sprint day. Actual
+// values are nil for days still to come; points are only set when the sprint
+// has a points source.
+type BurndownDayResponse struct {
+	Date            string   `json:"date"`
+	Remaining       *int     `json:"remaining"`
+	Ideal           float64  `json:"ideal"`
+	RemainingPoints *float64 `json:"remaining_points,omitempty"`
+	IdealPoints     *float64 `json:"ideal_points,omitempty"`
+}
+
+// BurnupDayResponse is the scope and completed work at the end of one sprint
+// day. Actual values are nil for days still to come.
+type BurnupDayResponse struct {
+	Date        string   `json:"date"`
+	Scope       *int     `json:"scope"`
+	Done        *int     `json:"done"`
+	ScopePoints *float64 `json:"scope_points,omitempty"`
+	DonePoints  *float64 `json:"done_points,omitempty"`
+}
@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sprintMaxDays caps how long a sprint or milestone can run
+const sprintMaxDays = 366
+
+// GetSprints returns a board's sprints, latest first
+func GetSprints(c *gin.Context) {
+	boardID := c.Param("id")
+
+	var sprints []models.Sprint
+	if err := database.DB.Where("board_id = ?", boardID).Order("start_date DESC, id DESC").Find(&sprints).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sprints"})
+		return
+	}
+
+	response := make([]SprintResponse, len(sprints))
+	for i, sprint := range sprints {
+		response[i] = sprintResponse(&sprint)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sprints": response,
+		"count":   len(response),
+	})
+}
+
+// CreateSprint plans a sprint on a board
+func CreateSprint(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	var req CreateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprint := models.Sprint{
+		BoardID:   uint(boardID),
+		Name:      req.Name,
+		Goal:      req.Goal,
+		CreatedBy: userID,
+	}
+	if req.LabelID != nil && *req.LabelID != 0 {
+		sprint.LabelID = req.LabelID
+	}
+	if req.PointsFieldID != nil && *req.PointsFieldID != 0 {
+		sprint.PointsFieldID = req.PointsFieldID
+	}
+	if !setSprintDates(c, &sprint, req.StartDate, req.EndDate) || !checkSprintSettings(c, &sprint) {
+		return
+	}
+	if !checkSprintCards(c, sprint.BoardID, req.CardIDs) {
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sprint).Error; err != nil {
+			return err
+		}
+		return addSprintCards(tx, sprint.ID, req.CardIDs)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sprint"})
+		return
+	}
+
+	utils.LogActivity("created_sprint", "sprint", sprint.ID, sprint.BoardID, userID, sprint.Name, map[string]interface{}{
+		"card_ids": req.CardIDs,
+	})
+
+	c.JSON(http.StatusCreated, sprintResponse(&sprint))
+}
+
+// GetSprint returns a sprint with the cards currently in it
+func GetSprint(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	sprint, ok := findSprint(c, userID, "view_board")
+	if !ok {
+		return
+	}
+
+	sprintService := &services.SprintService{}
+	cards, err := sprintService.ScopeCards(database.DB, sprint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sprint cards"})
+		return
+	}
+
+	response := SprintDetailResponse{
+		SprintResponse: sprintResponse(sprint),
+		Cards:          make([]CardResponse, len(cards)),
+	}
+	for i, card := range cards {
+		response.Cards[i] = CardResponse{
+			ID:          card.ID,
+			Title:       card.Title,
+			Description: card.Description,
+			ListID:      card.ListID,
+			Rank:        card.Rank,
+			Version:     card.Version,
+			DueDate:     card.DueDate,
+			DueComplete: card.DueComplete,
+			LaneID:      card.LaneID,
+			ArchivedAt:  card.ArchivedAt,
+			CreatedAt:   card.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateSprint changes a sprint's name, goal, dates or settings
+func UpdateSprint(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req UpdateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprint, ok := findSprint(c, userID, "edit_board")
+	if !ok {
+		return
+	}
+	before := *sprint
+
+	if req.Name != "" {
+		sprint.Name = req.Name
+	}
+	if req.Goal != nil {
+		sprint.Goal = *req.Goal
+	}
+	if req.LabelID != nil {
+		sprint.LabelID = req.LabelID
+		if *req.LabelID == 0 {
+			sprint.LabelID = nil
+		}
+	}
+	if req.PointsFieldID != nil {
+		sprint.PointsFieldID = req.PointsFieldID
+		if *req.PointsFieldID == 0 {
+			sprint.PointsFieldID = nil
+		}
+	}
+	startDate, endDate := req.StartDate, req.EndDate
+	if startDate == "" {
+		startDate = sprint.StartDate.Format("2006-01-02")
+	}
+	if endDate == "" {
+		endDate = sprint.EndDate.Format("2006-01-02")
+	}
+	if !setSprintDates(c, sprint, startDate, endDate) || !checkSprintSettings(c, sprint) {
+		return
+	}
+
+	if err := database.DB.Save(sprint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update sprint"})
+		return
+	}
+
+	var changes utils.FieldChanges
+	changes.Add("name", before.Name, sprint.Name)
+	changes.Add("goal", before.Goal, sprint.Goal)
+	changes.Add("start_date", before.StartDate, sprint.StartDate)
+	changes.Add("end_date", before.EndDate, sprint.EndDate)
+	changes.Add("label_id", before.LabelID, sprint.LabelID)
+	changes.Add("points_field_id", before.PointsFieldID, sprint.PointsFieldID)
+	utils.LogChanges("updated_sprint", "sprint", sprint.ID, sprint.BoardID, userID, sprint.Name, changes, nil)
+
+	c.JSON(http.StatusOK, sprintResponse(sprint))
+}
+
+// DeleteSprint deletes a sprint. Its cards are not affected.
+func DeleteSprint(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	sprint, ok := findSprint(c, userID, "edit_board")
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("sprint_id = ?", sprint.ID).Delete(&models.SprintCard{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("sprint_id = ?", sprint.ID).Delete(&models.SprintCardSpan{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(sprint).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete sprint"})
+		return
+	}
+
+	utils.LogActivity("deleted_sprint", "sprint", sprint.ID, sprint.BoardID, userID, sprint.Name, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sprint deleted successfully",
+		"id":      sprint.ID,
+	})
+}
+
+// AddSprintCards adds cards of the sprint's board to a sprint. Cards already
+// in it keep the time they joined.
+func AddSprintCards(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req SprintCardsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprint, ok := findSprint(c, userID, "edit_card")
+	if !ok {
+		return
+	}
+	if !checkSprintCards(c, sprint.BoardID, req.CardIDs) {
+		return
+	}
+
+	if err := addSprintCards(database.DB, sprint.ID, req.CardIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add cards to sprint"})
+		return
+	}
+
+	utils.LogActivity("added_sprint_cards", "sprint", sprint.ID, sprint.BoardID, userID, sprint.Name, map[string]interface{}{
+		"card_ids": req.CardIDs,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Cards added to sprint",
+		"sprint_id": sprint.ID,
+		"card_ids":  req.CardIDs,
+	})
+}
+
+// RemoveSprintCard takes a card that was added explicitly out of a sprint.
+// Cards that are in it through its label stay.
+func RemoveSprintCard(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	sprint, ok := findSprint(c, userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	result := database.DB.Where("sprint_id = ? AND card_id = ?", sprint.ID, c.Param("card_id")).Delete(&models.SprintCard{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove card from sprint"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card is not in this sprint"})
+		return
+	}
+
+	cardID, _ := strconv.ParseUint(c.Param("card_id"), 10, 32)
+	utils.LogActivity("removed_sprint_card", "sprint", sprint.ID, sprint.BoardID, userID, sprint.Name, map[string]interface{}{
+		"card_id": cardID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Card removed from sprint"})
+}
+
+// GetSprintBurndown returns the work left at the end of each sprint day next
+// to the ideal straight line from the first day's scope to zero
+func GetSprintBurndown(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	sprint, ok := findSprint(c, userID, "view_board")
+	if !ok {
+		return
+	}
+	pointsSource, days, ok := sprintProgress(c, sprint)
+	if !ok {
+		return
+	}
+
+	dates := sprintDates(sprint)
+	response := make([]BurndownDayResponse, len(dates))
+	var startScope, startPoints float64
+	if len(days) > 0 {
+		startScope, startPoints = float64(days[0].Scope), days[0].ScopePoints
+	}
+	for i, date := range dates {
+		left := 1.0
+		if len(dates) > 1 {
+			left = float64(len(dates)-1-i) / float64(len(dates)-1)
+		}
+		response[i] = BurndownDayResponse{
+			Date:  date.Format("2006-01-02"),
+			Ideal: roundPoints(startScope * left),
+		}
+		if pointsSource != "" {
+			ideal := roundPoints(startPoints * left)
+			response[i].IdealPoints = &ideal
+		}
+		if i < len(days) {
+			remaining := days[i].Scope - days[i].Done
+			response[i].Remaining = &remaining
+			if pointsSource != "" {
+				remainingPoints := roundPoints(days[i].ScopePoints - days[i].DonePoints)
+				response[i].RemainingPoints = &remainingPoints
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sprint":        sprintResponse(sprint),
+		"points_source": pointsSource,
+		"days":          response,
+	})
+}
+
+// GetSprintBurnup returns the scope and completed work at the end of each
+// sprint day
+func GetSprintBurnup(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	sprint, ok := findSprint(c, userID, "view_board")
+	if !ok {
+		return
+	}
+	pointsSource, days, ok := sprintProgress(c, sprint)
+	if !ok {
+		return
+	}
+
+	dates := sprintDates(sprint)
+	response := make([]BurnupDayResponse, len(dates))
+	for i, date := range dates {
+		response[i] = BurnupDayResponse{Date: date.Format("2006-01-02")}
+		if i < len(days) {
+			scope, done := days[i].Scope, days[i].Done
+			response[i].Scope, response[i].Done = &scope, &done
+			if pointsSource != "" {
+				scopePoints, donePoints := roundPoints(days[i].ScopePoints), roundPoints(days[i].DonePoints)
+				response[i].ScopePoints, response[i].DonePoints = &scopePoints, &donePoints
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sprint":        sprintResponse(sprint),
+		"points_source": pointsSource,
+		"days":          response,
+	})
+}
+
+// sprintProgress replays a sprint's history. On failure it responds and
+// returns false.
+func sprintProgress(c *gin.Context, sprint *models.Sprint) (string, []services.SprintDay, bool) {
+	sprintService := &services.SprintService{}
+	pointsSource, err := sprintService.PointsSource(database.DB, sprint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute sprint progress"})
+		return "", nil, false
+	}
+	days, err := sprintService.Progress(database.DB, sprint, pointsSource, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute sprint progress"})
+		return "", nil, false
+	}
+	return pointsSource, days, true
+}
+
+// sprintDates lists every day of a sprint
+func sprintDates(sprint *models.Sprint) []time.Time {
+	var dates []time.Time
+	for day := sprint.StartDate; !day.After(sprint.EndDate); day = day.AddDate(0, 0, 1) {
+		dates = append(dates, day)
+	}
+	return dates
+}
+
+// roundPoints rounds to two decimals for display
+func roundPoints(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// setSprintDates parses and checks a sprint's dates. On failure it responds
+// and returns false.
+func setSprintDates(c *gin.Context, sprint *models.Sprint, startDate, endDate string) bool {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date, expected YYYY-MM-DD"})
+		return false
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date, expected YYYY-MM-DD"})
+		return false
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return false
+	}
+	if end.Sub(start) >= sprintMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sprints are limited to a year"})
+		return false
+	}
+
+	sprint.StartDate, sprint.EndDate = start, end
+	return true
+}
+
+// checkSprintSettings checks a sprint's label and points field belong to its
+// board. On failure it responds and returns false.
+func checkSprintSettings(c *gin.Context, sprint *models.Sprint) bool {
+	if sprint.LabelID != nil {
+		var label models.Label
+		if database.DB.Where("id = ? AND board_id = ?", *sprint.LabelID, sprint.BoardID).First(&label).Error != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Label not found on this board"})
+			return false
+		}
+	}
+	if sprint.PointsFieldID != nil {
+		var field models.CustomField
+		if database.DB.Where("id = ? AND board_id = ?", *sprint.PointsFieldID, sprint.BoardID).First(&field).Error != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Custom field not found on this board"})
+			return false
+		}
+		if field.Type != services.FieldNumber {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Story points need a number field"})
+			return false
+		}
+	}
+	return true
+}
+
+// checkSprintCards checks every card is on the board. On failure it responds
+// and returns false.
+func checkSprintCards(c *gin.Context, boardID uint, cardIDs []uint) bool {
+	if len(cardIDs) == 0 {
+		return true
+	}
+
+	var count int64
+	database.DB.Model(&models.Card{}).
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Where("cards.id IN ? AND lists.board_id = ?", cardIDs, boardID).
+		Count(&count)
+	if int(count) != len(uniqueIDs(cardIDs)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cards must be on the sprint's board"})
+		return false
+	}
+	return true
+}
+
+// addSprintCards adds cards to a sprint, skipping those already in it
+func addSprintCards(tx *gorm.DB, sprintID uint, cardIDs []uint) error {
+	cardIDs = uniqueIDs(cardIDs)
+	if len(cardIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]models.SprintCard, len(cardIDs))
+	for i, cardID := range cardIDs {
+		rows[i] = models.SprintCard{SprintID: sprintID, CardID: cardID}
+	}
+	return tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// findSprint loads the sprint in the :id param and checks the user has
+// permission on its board. On failure it responds and returns false.
+func findSprint(c *gin.Context, userID uint, permission string) (*models.Sprint, bool) {
+	var sprint models.Sprint
+	if err := database.DB.First(&sprint, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sprint not found"})
+		return nil, false
+	}
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, sprint.BoardID, permission) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return nil, false
+	}
+	return &sprint, true
+}
+
+func sprintResponse(sprint *models.Sprint) SprintResponse {
+	return SprintResponse{
+		ID:            sprint.ID,
+		BoardID:       sprint.BoardID,
+		Name:          sprint.Name,
+		Goal:          sprint.Goal,
+		StartDate:     sprint.StartDate.Format("2006-01-02"),
+		EndDate:       sprint.EndDate.Format("2006-01-02"),
+		LabelID:       sprint.LabelID,
+		PointsFieldID: sprint.PointsFieldID,
+		CreatedBy:     sprint.CreatedBy,
+		CreatedAt:     sprint.CreatedAt,
+	}
+}
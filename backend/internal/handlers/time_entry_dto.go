@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+)
+
+// StartTimerRequest represents optional input for starting a timer
+type StartTimerRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// CreateTimeEntryRequest represents input for logging time by hand. The end
+// is given either as ended_at or as a length in minutes.
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time  `json:"started_at" binding:"required"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Minutes   int        `json:"minutes" binding:"omitempty,min=1,max=1440"`
+	Note      string     `json:"note" binding:"max=500"`
+}
+
+// UpdateTimeEntryRequest represents input for correcting a time entry.
+// Setting ended_at on a running timer stops it.
+type UpdateTimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      *string    `json:"note" binding:"omitempty,max=500"`
+}
+
+// TimeEntryResponse represents a time entry
+type TimeEntryResponse struct {
+	ID        uint         `json:"id"`
+	CardID    uint         `json:"card_id"`
+	User      UserResponse `json:"user"`
+	StartedAt time.Time    `json:"started_at"`
+	EndedAt   *time.Time   `json:"ended_at"` // Nil while the timer runs
+	Running   bool         `json:"running"`
+	Seconds   int64        `json:"seconds"` // Running timers count up to now
+	Note      string       `json:"note"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// CardTimeResponse is a card's time entries with totals
+type CardTimeResponse struct {
+	CardID          uint                 `json:"card_id"`
+	Entries         []TimeEntryResponse  `json:"entries"`
+	Totals          []services.TimeTotal `json:"totals"` // Per user
+	TotalSeconds    int64                `json:"total_seconds"`
+	EstimateMinutes *int                 `json:"estimate_minutes,omitempty"`
+	RemainingSecs   *int64               `json:"remaining_seconds,omitempty"` // Estimate less tracked time, negative when over
+}
+
+// TimesheetRowResponse is the time one user tracked on one card on one day
+type TimesheetRowResponse struct {
+	Date      string `json:"date"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	CardID    uint   `json:"card_id"`
+	CardTitle string `json:"card_title"`
+	Seconds   int64  `json:"seconds"`
+}
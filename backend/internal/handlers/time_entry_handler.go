@@ -0,0 +1,440 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Timesheet date range limits
+const (
+	timesheetDefaultDays = 7
+	timesheetMaxDays     = 366
+)
+
+// GetCardTime returns a card's time entries, newest first, with totals per
+// user and the time left against the card's estimate
+func GetCardTime(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "view_board")
+	if !ok {
+		return
+	}
+
+	var entries []models.TimeEntry
+	if err := database.DB.Preload("User").Where("card_id = ?", card.ID).Order("started_at DESC, id DESC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		return
+	}
+
+	now := time.Now()
+	timeService := &services.TimeService{}
+	totals, err := timeService.CardTotals(database.DB, card.ID, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		return
+	}
+
+	response := CardTimeResponse{
+		CardID:          card.ID,
+		Entries:         make([]TimeEntryResponse, len(entries)),
+		Totals:          totals,
+		EstimateMinutes: card.EstimateMinutes,
+	}
+	if response.Totals == nil {
+		response.Totals = []services.TimeTotal{}
+	}
+	for i, entry := range entries {
+		response.Entries[i] = timeEntryResponse(&entry, now)
+	}
+	for _, total := range totals {
+		response.TotalSeconds += total.Seconds
+	}
+	if card.EstimateMinutes != nil {
+		remaining := int64(*card.EstimateMinutes)*60 - response.TotalSeconds
+		response.RemainingSecs = &remaining
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// StartCardTimer starts the user's timer on a card. A user can only run one
+// timer; starting another is refused with the running one in the response.
+func StartCardTimer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req StartTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	timeService := &services.TimeService{}
+	entry, err := timeService.StartTimer(database.DB, card.ID, userID, now)
+	if err == services.ErrTimerRunning {
+		response := gin.H{"error": err.Error()}
+		if entry != nil {
+			database.DB.Preload("User").First(entry, entry.ID)
+			response["running"] = timeEntryResponse(entry, now)
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start timer"})
+		return
+	}
+
+	if req.Note != "" {
+		entry.Note = req.Note
+		database.DB.Model(entry).Update("note", req.Note)
+	}
+	database.DB.Preload("User").First(entry, entry.ID)
+
+	response := timeEntryResponse(entry, now)
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(card.List.BoardID, "timer_started", response)
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// StopCardTimer stops the user's timer on a card
+func StopCardTimer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	timeService := &services.TimeService{}
+	entry, err := timeService.StopTimer(database.DB, card.ID, userID, now)
+	if err == services.ErrNoTimer {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop timer"})
+		return
+	}
+	database.DB.Preload("User").First(entry, entry.ID)
+
+	response := timeEntryResponse(entry, now)
+	logTrackedTime(card, entry, userID, now)
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(card.List.BoardID, "timer_stopped", response)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateTimeEntry logs time on a card by hand
+func CreateTimeEntry(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endedAt := req.EndedAt
+	switch {
+	case endedAt != nil && req.Minutes != 0:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Give either ended_at or minutes, not both"})
+		return
+	case req.Minutes != 0:
+		end := req.StartedAt.Add(time.Duration(req.Minutes) * time.Minute)
+		endedAt = &end
+	case endedAt == nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ended_at or minutes is required"})
+		return
+	}
+	if !endedAt.After(req.StartedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidTimeRange.Error()})
+		return
+	}
+
+	card, ok := findPermittedCard(c, c.Param("id"), userID, "edit_card")
+	if !ok {
+		return
+	}
+
+	entry := models.TimeEntry{
+		CardID:    card.ID,
+		UserID:    userID,
+		StartedAt: req.StartedAt,
+		EndedAt:   endedAt,
+		Note:      req.Note,
+	}
+	if err := database.DB.Create(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log time"})
+		return
+	}
+	database.DB.Preload("User").First(&entry, entry.ID)
+
+	now := time.Now()
+	logTrackedTime(card, &entry, userID, now)
+	c.JSON(http.StatusCreated, timeEntryResponse(&entry, now))
+}
+
+// UpdateTimeEntry corrects one of the user's own time entries
+func UpdateTimeEntry(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, ok := findOwnTimeEntry(c, userID)
+	if !ok {
+		return
+	}
+	before := *entry
+
+	if req.StartedAt != nil {
+		entry.StartedAt = *req.StartedAt
+	}
+	if req.EndedAt != nil {
+		entry.EndedAt = req.EndedAt
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+	if entry.EndedAt != nil && !entry.EndedAt.After(entry.StartedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidTimeRange.Error()})
+		return
+	}
+
+	if err := database.DB.Model(entry).Select("started_at", "ended_at", "note").Updates(entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update time entry"})
+		return
+	}
+
+	var changes utils.FieldChanges
+	changes.Add("started_at", before.StartedAt, entry.StartedAt)
+	changes.Add("ended_at", before.EndedAt, entry.EndedAt)
+	changes.Add("note", before.Note, entry.Note)
+	utils.LogChanges("updated_time_entry", "card", entry.CardID, entry.Card.List.BoardID, userID, entry.Card.Title, changes, map[string]interface{}{
+		"time_entry_id": entry.ID,
+	})
+
+	c.JSON(http.StatusOK, timeEntryResponse(entry, time.Now()))
+}
+
+// DeleteTimeEntry deletes one of the user's own time entries
+func DeleteTimeEntry(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	entry, ok := findOwnTimeEntry(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Delete(entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete time entry"})
+		return
+	}
+
+	utils.LogActivity("deleted_time_entry", "card", entry.CardID, entry.Card.List.BoardID, userID, entry.Card.Title, map[string]interface{}{
+		"time_entry_id": entry.ID,
+		"seconds":       timeEntrySeconds(entry, time.Now()),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Time entry deleted successfully",
+		"id":      entry.ID,
+	})
+}
+
+// GetRunningTimer returns the user's running timer, if any
+func GetRunningTimer(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	timeService := &services.TimeService{}
+	entry, err := timeService.RunningTimer(database.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timer"})
+		return
+	}
+	if entry == nil {
+		c.JSON(http.StatusOK, gin.H{"running": nil})
+		return
+	}
+	database.DB.Preload("User").First(entry, entry.ID)
+
+	c.JSON(http.StatusOK, gin.H{"running": timeEntryResponse(entry, time.Now())})
+}
+
+// GetBoardTimesheet reports the time tracked on a board per user, day and
+// card. from and to are inclusive UTC dates (YYYY-MM-DD), defaulting to the
+// last seven days; user_id narrows it to one person and format=csv returns a
+// spreadsheet instead of JSON.
+func GetBoardTimesheet(c *gin.Context) {
+	boardID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board ID"})
+		return
+	}
+
+	dates, ok := parseDateRange(c, timesheetDefaultDays, timesheetMaxDays)
+	if !ok {
+		return
+	}
+
+	var filterUserID uint64
+	if value := c.Query("user_id"); value != "" {
+		if filterUserID, err = strconv.ParseUint(value, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+	}
+
+	timeService := &services.TimeService{}
+	rows, err := timeService.Timesheet(database.DB, uint(boardID), dates.From, dates.End(), uint(filterUserID), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build timesheet"})
+		return
+	}
+
+	response := make([]TimesheetRowResponse, len(rows))
+	for i, row := range rows {
+		response[i] = TimesheetRowResponse{
+			Date:      row.Day.Format("2006-01-02"),
+			UserID:    row.UserID,
+			Username:  row.Username,
+			CardID:    row.CardID,
+			CardTitle: row.CardTitle,
+			Seconds:   row.Seconds,
+		}
+	}
+
+	if c.Query("format") == "csv" {
+		writeTimesheetCSV(c, uint(boardID), dates, response)
+		return
+	}
+
+	// Totals per user, in the rows' order
+	var totals []services.TimeTotal
+	totalIndex := make(map[uint]int)
+	var totalSeconds int64
+	for _, row := range response {
+		i, ok := totalIndex[row.UserID]
+		if !ok {
+			i = len(totals)
+			totalIndex[row.UserID] = i
+			totals = append(totals, services.TimeTotal{UserID: row.UserID, Username: row.Username})
+		}
+		totals[i].Seconds += row.Seconds
+		totalSeconds += row.Seconds
+	}
+	if totals == nil {
+		totals = []services.TimeTotal{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board_id":      boardID,
+		"from":          dates.From.Format("2006-01-02"),
+		"to":            dates.To.Format("2006-01-02"),
+		"rows":          response,
+		"totals":        totals,
+		"total_seconds": totalSeconds,
+	})
+}
+
+// writeTimesheetCSV sends timesheet rows as a CSV download
+func writeTimesheetCSV(c *gin.Context, boardID uint, dates dateRange, rows []TimesheetRowResponse) {
+	filename := fmt.Sprintf("timesheet-board-%d-%s-%s.csv", boardID, dates.From.Format("20060102"), dates.To.Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"date", "user_id", "username", "card_id", "card_title", "hours"})
+	for _, row := range rows {
+		writer.Write([]string{
+			row.Date,
+			strconv.FormatUint(uint64(row.UserID), 10),
+			row.Username,
+			strconv.FormatUint(uint64(row.CardID), 10),
+			row.CardTitle,
+			strconv.FormatFloat(float64(row.Seconds)/3600, 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		c.Error(err)
+	}
+}
+
+// findOwnTimeEntry loads the :id time entry if it belongs to the user and the
+// user may still edit its card. On failure it responds and returns false.
+func findOwnTimeEntry(c *gin.Context, userID uint) (*models.TimeEntry, bool) {
+	var entry models.TimeEntry
+	if err := database.DB.Preload("User").Preload("Card.List").Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time entry not found"})
+		return nil, false
+	}
+
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, entry.Card.List.BoardID, "edit_card") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return nil, false
+	}
+	return &entry, true
+}
+
+// logTrackedTime records a finished time entry in the board's activity log
+func logTrackedTime(card *models.Card, entry *models.TimeEntry, userID uint, now time.Time) {
+	utils.LogActivity("tracked_time", "card", card.ID, card.List.BoardID, userID, card.Title, map[string]interface{}{
+		"time_entry_id": entry.ID,
+		"seconds":       timeEntrySeconds(entry, now),
+	})
+}
+
+// timeEntrySeconds is an entry's length, counting a running timer up to now
+func timeEntrySeconds(entry *models.TimeEntry, now time.Time) int64 {
+	end := now
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	}
+	return int64(end.Sub(entry.StartedAt).Seconds())
+}
+
+func timeEntryResponse(entry *models.TimeEntry, now time.Time) TimeEntryResponse {
+	return TimeEntryResponse{
+		ID:     entry.ID,
+		CardID: entry.CardID,
+		User: UserResponse{
+			ID:        entry.User.ID,
+			Username:  entry.User.Username,
+			Email:     entry.User.Email,
+			AvatarURL: entry.User.AvatarURL,
+		},
+		StartedAt: entry.StartedAt,
+		EndedAt:   entry.EndedAt,
+		Running:   entry.EndedAt == nil,
+		Seconds:   timeEntrySeconds(entry, now),
+		Note:      entry.Note,
+		CreatedAt: entry.CreatedAt,
+	}
+}
@@ -0,0 +1,478 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashRetention is how long trashed items stay restorable (set from config in main)
+var TrashRetention = 30 * 24 * time.Hour
+
+// TrashItemResponse represents a soft-deleted card, list, comment or attachment
+type TrashItemResponse struct {
+	Type      string        `json:"type"` // card, list, comment, attachment
+	ID        uint          `json:"id"`
+	Title     string        `json:"title"`
+	ListID    uint          `json:"list_id,omitempty"`
+	CardID    uint          `json:"card_id,omitempty"`
+	DeletedBy *UserResponse `json:"deleted_by,omitempty"`
+	DeletedAt time.Time     `json:"deleted_at"`
+	PurgeAt   time.Time     `json:"purge_at"`
+}
+
+// moveToTrash soft deletes a record and remembers who deleted it
+func moveToTrash(record interface{}, userID uint) error {
+	trashService := &services.TrashService{}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return trashService.MoveToTrash(tx, record, userID)
+	})
+}
+
+// GetBoardTrash lists everything deleted from a board that can still be restored
+func GetBoardTrash(c *gin.Context) {
+	boardID := c.Param("id")
+
+	var lists []models.List
+	if err := database.DB.Unscoped().
+		Where("board_id = ? AND deleted_at IS NOT NULL", boardID).
+		Find(&lists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	// Cards inside a trashed list are restored with the list, so only
+	// cards deleted from live lists are listed on their own
+	var cards []models.Card
+	if err := database.DB.Unscoped().
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.board_id = ? AND cards.deleted_at IS NOT NULL", boardID).
+		Find(&cards).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	var comments []models.Comment
+	if err := database.DB.Unscoped().
+		Joins("JOIN cards ON cards.id = comments.card_id AND cards.deleted_at IS NULL").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.board_id = ? AND comments.deleted_at IS NOT NULL", boardID).
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	var attachments []models.Attachment
+	if err := database.DB.Unscoped().
+		Joins("JOIN cards ON cards.id = attachments.card_id AND cards.deleted_at IS NULL").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.board_id = ? AND attachments.deleted_at IS NOT NULL", boardID).
+		Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	items := make([]TrashItemResponse, 0, len(lists)+len(cards)+len(comments)+len(attachments))
+	for _, list := range lists {
+		items = append(items, newTrashItem("list", list.ID, list.Title, list.DeletedAt, list.DeletedBy))
+	}
+	for _, card := range cards {
+		item := newTrashItem("card", card.ID, card.Title, card.DeletedAt, card.DeletedBy)
+		item.ListID = card.ListID
+		items = append(items, item)
+	}
+	for _, comment := range comments {
+		item := newTrashItem("comment", comment.ID, truncate(comment.Content, 100), comment.DeletedAt, comment.DeletedBy)
+		item.CardID = comment.CardID
+		items = append(items, item)
+	}
+	for _, attachment := range attachments {
+		item := newTrashItem("attachment", attachment.ID, attachment.Filename, attachment.DeletedAt, attachment.DeletedBy)
+		item.CardID = attachment.CardID
+		items = append(items, item)
+	}
+
+	// Attach the users who deleted each item
+	var deleterIDs []uint
+	for _, item := range items {
+		if item.DeletedBy != nil {
+			deleterIDs = append(deleterIDs, item.DeletedBy.ID)
+		}
+	}
+	if len(deleterIDs) > 0 {
+		var users []models.User
+		database.DB.Where("id IN ?", deleterIDs).Find(&users)
+
+		usersByID := make(map[uint]UserResponse, len(users))
+		for _, user := range users {
+			usersByID[user.ID] = UserResponse{
+				ID:        user.ID,
+				Username:  user.Username,
+				Email:     user.Email,
+				AvatarURL: user.AvatarURL,
+			}
+		}
+		for i := range items {
+			if items[i].DeletedBy != nil {
+				if user, ok := usersByID[items[i].DeletedBy.ID]; ok {
+					items[i].DeletedBy = &user
+				}
+			}
+		}
+	}
+
+	// Most recently deleted first
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":          items,
+		"count":          len(items),
+		"retention_days": int(TrashRetention.Hours() / 24),
+	})
+}
+
+// RestoreTrashItem brings a trashed item back to where it was deleted from
+func RestoreTrashItem(c *gin.Context) {
+	boardID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	itemType := c.Param("type")
+	itemID := c.Param("item_id")
+	userID := c.GetUint("user_id")
+
+	// Restoring needs the same permission as deleting
+	permService := &services.PermissionService{}
+	if !permService.CheckPermission(userID, uint(boardID), restorePermission(itemType)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+		return
+	}
+
+	switch itemType {
+	case "card":
+		restoreCard(c, uint(boardID), itemID, userID)
+	case "list":
+		restoreList(c, uint(boardID), itemID, userID)
+	case "comment":
+		restoreComment(c, uint(boardID), itemID, userID)
+	case "attachment":
+		restoreAttachment(c, uint(boardID), itemID, userID)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown trash item type"})
+	}
+}
+
+// PurgeTrashItem permanently deletes a trashed item before its retention period ends
+func PurgeTrashItem(c *gin.Context) {
+	boardID, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	itemType := c.Param("type")
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	// Only items that are already in this board's trash can be purged
+	var count int64
+	switch itemType {
+	case "card":
+		database.DB.Unscoped().Model(&models.Card{}).
+			Joins("JOIN lists ON lists.id = cards.list_id").
+			Where("cards.id = ? AND lists.board_id = ? AND cards.deleted_at IS NOT NULL", itemID, boardID).
+			Count(&count)
+	case "list":
+		database.DB.Unscoped().Model(&models.List{}).
+			Where("id = ? AND board_id = ? AND deleted_at IS NOT NULL", itemID, boardID).
+			Count(&count)
+	case "comment":
+		database.DB.Unscoped().Model(&models.Comment{}).
+			Joins("JOIN cards ON cards.id = comments.card_id").
+			Joins("JOIN lists ON lists.id = cards.list_id").
+			Where("comments.id = ? AND lists.board_id = ? AND comments.deleted_at IS NOT NULL", itemID, boardID).
+			Count(&count)
+	case "attachment":
+		database.DB.Unscoped().Model(&models.Attachment{}).
+			Joins("JOIN cards ON cards.id = attachments.card_id").
+			Joins("JOIN lists ON lists.id = cards.list_id").
+			Where("attachments.id = ? AND lists.board_id = ? AND attachments.deleted_at IS NOT NULL", itemID, boardID).
+			Count(&count)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown trash item type"})
+		return
+	}
+
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found in trash"})
+		return
+	}
+
+	trashService := &services.TrashService{}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		switch itemType {
+		case "card":
+			return trashService.PurgeCards(tx, []uint{uint(itemID)})
+		case "list":
+			return trashService.PurgeLists(tx, []uint{uint(itemID)})
+		case "comment":
+			return tx.Unscoped().Delete(&models.Comment{}, itemID).Error
+		default:
+			return tx.Unscoped().Delete(&models.Attachment{}, itemID).Error
+		}
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item permanently"})
+		return
+	}
+
+	utils.LogActivity("purged_"+itemType, itemType, uint(itemID), uint(boardID), c.GetUint("user_id"), "", nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Item deleted permanently",
+		"type":    itemType,
+		"id":      itemID,
+	})
+}
+
+// restoreCard puts a card back into its list at its old place
+func restoreCard(c *gin.Context, boardID uint, cardID string, userID uint) {
+	var card models.Card
+	if err := database.DB.Unscoped().Preload("List").
+		Where("deleted_at IS NOT NULL").
+		First(&card, cardID).Error; err != nil || card.List.BoardID != boardID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Card not found in trash"})
+		return
+	}
+
+	if card.List.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "The card's list is in the trash, restore the list first"})
+		return
+	}
+
+	// The card counts towards its list's WIP limit again
+	cardService := &services.CardService{}
+	var wip *services.WIPCheck
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		wip, err = cardService.RestoreCard(tx, &card)
+		return err
+	})
+	if errors.Is(err, services.ErrWIPLimit) {
+		logWIPBreach(wip, &card.List, &card, userID)
+		respondWIPLimit(c, wip)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore card"})
+		return
+	}
+
+	if wip != nil && wip.Over {
+		logWIPBreach(wip, &card.List, &card, userID)
+	}
+
+	utils.LogActivity("restored_card", "card", card.ID, boardID, userID, card.Title, nil)
+
+	response := CardResponse{
+		ID:          card.ID,
+		Title:       card.Title,
+		Description: card.Description,
+		ListID:      card.ListID,
+		Rank:        card.Rank,
+		Version:     card.Version,
+		DueDate:     card.DueDate,
+		DueComplete: card.DueComplete,
+		LaneID:      card.LaneID,
+		CreatedAt:   card.CreatedAt,
+	}
+
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(boardID, "card_restored", response)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Card restored successfully",
+		"card":    response,
+	})
+}
+
+// restoreList puts a list (and the cards that were in it) back at its old place
+func restoreList(c *gin.Context, boardID uint, listID string, userID uint) {
+	var list models.List
+	if err := database.DB.Unscoped().
+		Where("board_id = ? AND deleted_at IS NOT NULL", boardID).
+		First(&list, listID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "List not found in trash"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		tx.Model(&models.List{}).Where("board_id = ? AND rank = ?", boardID, list.Rank).Count(&taken)
+		if taken > 0 {
+			var before int64
+			tx.Model(&models.List{}).Where("board_id = ? AND rank <= ?", boardID, list.Rank).Count(&before)
+
+			ordering := &services.OrderingService{}
+			rank, err := ordering.ListRankAt(tx, boardID, int(before), list.ID)
+			if err != nil {
+				return err
+			}
+			list.Rank = rank
+		}
+
+		return tx.Unscoped().Model(&list).UpdateColumns(map[string]interface{}{
+			"rank":       list.Rank,
+			"deleted_at": nil,
+			"deleted_by": nil,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore list"})
+		return
+	}
+
+	utils.LogActivity("restored_list", "list", list.ID, boardID, userID, list.Title, nil)
+
+	response := ListResponse{
+		ID:       list.ID,
+		Title:    list.Title,
+		BoardID:  list.BoardID,
+		Rank:     list.Rank,
+		Version:  list.Version,
+		IsDone:   list.IsDone,
+		WIPLimit: list.WIPLimit,
+		WIPMode:  list.WIPMode,
+	}
+
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(boardID, "list_restored", response)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "List restored successfully",
+		"list":    response,
+	})
+}
+
+// restoreComment puts a comment back on its card
+func restoreComment(c *gin.Context, boardID uint, commentID string, userID uint) {
+	var comment models.Comment
+	if err := database.DB.Unscoped().Preload("Card.List").
+		Where("deleted_at IS NOT NULL").
+		First(&comment, commentID).Error; err != nil || comment.Card.List.BoardID != boardID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found in trash"})
+		return
+	}
+
+	if comment.Card.DeletedAt.Valid || comment.Card.List.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "The comment's card is in the trash, restore the card first"})
+		return
+	}
+
+	if err := restoreRecord(&comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore comment"})
+		return
+	}
+
+	utils.LogActivity("restored_comment", "comment", comment.ID, boardID, userID, comment.Card.Title, nil)
+
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(boardID, "comment_restored", gin.H{
+			"comment_id": comment.ID,
+			"card_id":    comment.CardID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment restored successfully",
+		"id":      comment.ID,
+	})
+}
+
+// restoreAttachment puts an attachment back on its card
+func restoreAttachment(c *gin.Context, boardID uint, attachmentID string, userID uint) {
+	var attachment models.Attachment
+	if err := database.DB.Unscoped().Preload("Card.List").
+		Where("deleted_at IS NOT NULL").
+		First(&attachment, attachmentID).Error; err != nil || attachment.Card.List.BoardID != boardID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found in trash"})
+		return
+	}
+
+	if attachment.Card.DeletedAt.Valid || attachment.Card.List.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "The attachment's card is in the trash, restore the card first"})
+		return
+	}
+
+	if err := restoreRecord(&attachment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore attachment"})
+		return
+	}
+
+	utils.LogActivity("restored_file", "attachment", attachment.ID, boardID, userID, attachment.Filename, nil)
+
+	if WSHub != nil {
+		WSHub.BroadcastToBoard(boardID, "attachment_restored", gin.H{
+			"attachment_id": attachment.ID,
+			"card_id":       attachment.CardID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Attachment restored successfully",
+		"id":      attachment.ID,
+	})
+}
+
+// restoreRecord clears the soft-delete markers on a record
+func restoreRecord(record interface{}) error {
+	return database.DB.Unscoped().Model(record).UpdateColumns(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+	}).Error
+}
+
+// restorePermission maps a trash item type to the permission needed to restore it
+func restorePermission(itemType string) string {
+	switch itemType {
+	case "list":
+		return "delete_list"
+	case "card":
+		return "delete_card"
+	default:
+		return "edit_card"
+	}
+}
+
+// newTrashItem builds the common part of a trash entry
+func newTrashItem(itemType string, id uint, title string, deletedAt gorm.DeletedAt, deletedBy *uint) TrashItemResponse {
+	item := TrashItemResponse{
+		Type:      itemType,
+		ID:        id,
+		Title:     title,
+		DeletedAt: deletedAt.Time,
+		PurgeAt:   deletedAt.Time.Add(TrashRetention),
+	}
+	if deletedBy != nil {
+		item.DeletedBy = &UserResponse{ID: *deletedBy}
+	}
+	return item
+}
+
+// truncate shortens text for previews
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return fmt.Sprintf("%s...", string(runes[:max]))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+// wsCommand is a mutation clients can send over a WebSocket: the REST
+// handler that runs it and the path parameter it takes from the command's
+// data
+type wsCommand struct {
+	Handler gin.HandlerFunc
+	Param   string // Path parameter of Handler
+	Field   string // Data field holding the parameter's value
+}
+
+// wsCommands maps WebSocket command types to the handlers they run. The
+// command's data is the handler's request body plus the Field ID.
+var wsCommands = map[string]wsCommand{
+	"move_card":   {Handler: MoveCard, Param: "id", Field: "card_id"},
+	"add_comment": {Handler: CreateComment, Param: "card_id", Field: "card_id"},
+}
+
+// WSCommands authorizes WebSocket subscriptions and runs WebSocket commands
+// through the same handlers as the REST API, so they are validated, logged
+// and broadcast the same way. It implements websocket.CommandHandler.
+type WSCommands struct{}
+
+// CanView reports whether the user can view the board
+func (WSCommands) CanView(userID, boardID uint) bool {
+	permService := &services.PermissionService{}
+	return permService.CheckPermission(userID, boardID, "view_board")
+}
+
+// Execute runs a command as a request by the user and returns the handler's
+// response
+func (WSCommands) Execute(userID uint, commandType string, data json.RawMessage) (int, json.RawMessage, error) {
+	command, ok := wsCommands[commandType]
+	if !ok {
+		return 0, nil, ws.ErrUnknownCommand
+	}
+
+	var target map[string]interface{}
+	json.Unmarshal(data, &target)
+	id, ok := target[command.Field].(float64)
+	if !ok || id <= 0 || id != float64(uint(id)) {
+		body, _ := json.Marshal(gin.H{"error": command.Field + " required"})
+		return http.StatusBadRequest, body, nil
+	}
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: command.Param, Value: strconv.FormatUint(uint64(id), 10)}}
+	c.Set("user_id", userID)
+
+	command.Handler(c)
+	return recorder.Code, recorder.Body.Bytes(), nil
+}
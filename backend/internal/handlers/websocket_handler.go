@@ -7,8 +7,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
-	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
 	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -24,19 +22,30 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-// HandleWebSocket handles WebSocket connections
+// HandleWebSocket handles WebSocket connections. The connection is
+// subscribed to board_id if given; clients subscribe to more boards and send
+// commands with the messages in the websocket package's protocol.
 func HandleWebSocket(hub *ws.Hub) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		boardIDStr := c.Query("board_id")
-		if boardIDStr == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "board_id required"})
-			return
+		var boardID uint64
+		if boardIDStr := c.Query("board_id"); boardIDStr != "" {
+			var err error
+			boardID, err = strconv.ParseUint(boardIDStr, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board_id"})
+				return
+			}
 		}
 
-		boardID, err := strconv.ParseUint(boardIDStr, 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid board_id"})
-			return
+		// Last event seen before reconnecting, to replay what was missed
+		var since *uint64
+		if sinceStr := c.Query("since"); sinceStr != "" {
+			seq, err := strconv.ParseUint(sinceStr, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since"})
+				return
+			}
+			since = &seq
 		}
 
 		// Get token from query parameter (for WebSocket)
@@ -84,11 +93,11 @@ func HandleWebSocket(hub *ws.Hub) gin.HandlerFunc {
 			return
 		}
 		userID := uint(userIDFloat)
+		username, _ := claims["username"].(string)
 
 		// Verify user has access to this board
-		var board models.Board
-		if err := database.DB.Where("id = ? AND owner_id = ?", boardID, userID).First(&board).Error; err != nil {
-			log.Printf("Board access denied: User %d -> Board %d, Error: %v", userID, boardID, err)
+		if boardID != 0 && (hub.Commands == nil || !hub.Commands.CanView(userID, uint(boardID))) {
+			log.Printf("Board access denied: User %d -> Board %d", userID, boardID)
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to this board"})
 			return
 		}
@@ -101,10 +110,12 @@ func HandleWebSocket(hub *ws.Hub) gin.HandlerFunc {
 		}
 
 		// Create new client with all fields
-		client := ws.NewClient(hub, conn, uint(boardID), userID)
+		client := ws.NewClient(hub, conn, userID, username)
 
-		// Register client
-		hub.Register(client)
+		// Subscribe after replaying the events it missed
+		if boardID != 0 {
+			hub.Join(client, uint(boardID), since)
+		}
 
 		// Start goroutines
 		go client.WritePump()
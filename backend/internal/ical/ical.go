@@ -0,0 +1,128 @@
+// Package ical writes iCalendar (RFC 5545) feeds of timed events
+package ical
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest a content line may be before it is folded
+const maxLineOctets = 75
+
+// stampFormat is a UTC DATE-TIME value
+const stampFormat = "20060102T150405Z"
+
+// Event is a VEVENT. An event without an End lasts no time, which is how
+// calendars show a deadline.
+type Event struct {
+	UID          string
+	Stamp        time.Time // When the event last changed
+	Start        time.Time
+	End          *time.Time
+	Summary      string
+	Description  string
+	URL          string
+	Categories   []string
+	LastModified time.Time
+}
+
+// Calendar is a VCALENDAR of events
+type Calendar struct {
+	ProductID string // PRODID, e.g. -//FlowBoard//Calendar//EN
+	Name      string // Display name for clients that honour X-WR-CALNAME
+	Events    []Event
+}
+
+// Write encodes the calendar to w with CRLF line endings
+func (cal *Calendar) Write(w io.Writer) error {
+	enc := &encoder{w: w}
+	enc.line("BEGIN", "VCALENDAR")
+	enc.line("VERSION", "2.0")
+	enc.line("PRODID", cal.ProductID)
+	enc.line("CALSCALE", "GREGORIAN")
+	enc.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		enc.line("X-WR-CALNAME", Escape(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		enc.line("BEGIN", "VEVENT")
+		enc.line("UID", event.UID)
+		enc.line("DTSTAMP", event.Stamp.UTC().Format(stampFormat))
+		enc.line("DTSTART", event.Start.UTC().Format(stampFormat))
+		if event.End != nil {
+			enc.line("DTEND", event.End.UTC().Format(stampFormat))
+		}
+		enc.line("SUMMARY", Escape(event.Summary))
+		if event.Description != "" {
+			enc.line("DESCRIPTION", Escape(event.Description))
+		}
+		if event.URL != "" {
+			enc.line("URL", event.URL)
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = Escape(category)
+			}
+			enc.line("CATEGORIES", strings.Join(categories, ","))
+		}
+		if !event.LastModified.IsZero() {
+			enc.line("LAST-MODIFIED", event.LastModified.UTC().Format(stampFormat))
+		}
+		enc.line("END", "VEVENT")
+	}
+
+	enc.line("END", "VCALENDAR")
+	return enc.err
+}
+
+// Escape escapes a TEXT value: backslashes, semicolons, commas and newlines
+func Escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+// Fold splits a content line into lines of at most 75 octets, continuing
+// each with a leading space. Multi-byte characters are never split.
+func Fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // The leading space counts
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// encoder writes content lines, keeping the first error
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (enc *encoder) line(name, value string) {
+	if enc.err != nil {
+		return
+	}
+	_, enc.err = io.WriteString(enc.w, Fold(name+":"+value)+"\r\n")
+}
@@ -0,0 +1,220 @@
+package jobs
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/events"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
+)
+
+// automationQueueSize bounds how many triggers wait for the runner
+const automationQueueSize = 1024
+
+// automationQueueWait is how long a publisher waits for room in a full
+// queue. A trigger still not queued is dropped and logged as a failed run
+// of each rule it fires.
+const automationQueueWait = 2 * time.Second
+
+// automationBatchSize caps how many scheduled rules one pass runs
+const automationBatchSize = 100
+
+// StartAutomationRunner runs automation rules for board events. Events are
+// queued by the publisher and rules run one trigger at a time on a single
+// goroutine, so a rule sees the changes of the rules before it.
+func StartAutomationRunner(hub *ws.Hub) {
+	queue := make(chan services.AutomationTrigger, automationQueueSize)
+
+	events.Subscribe(func(event events.Event) {
+		for _, trigger := range automationTriggers(event) {
+			select {
+			case queue <- trigger:
+				continue
+			default:
+			}
+
+			wait := time.NewTimer(automationQueueWait)
+			select {
+			case queue <- trigger:
+				wait.Stop()
+			case <-wait.C:
+				log.Printf("Automation queue full, dropped %s on board %d", trigger.Event, trigger.BoardID)
+				automationService := &services.AutomationService{}
+				if err := automationService.RecordDropped(database.DB, &trigger, "automation queue full; trigger dropped"); err != nil {
+					log.Printf("Error recording dropped automation trigger: %v", err)
+				}
+			}
+		}
+	})
+
+	go func() {
+		automationService := &services.AutomationService{}
+		for trigger := range queue {
+			results, err := automationService.HandleTrigger(database.DB, &trigger, time.Now())
+			if err != nil {
+				log.Printf("Automation run failed: %v", err)
+			}
+			reportAutomations(hub, results)
+		}
+	}()
+}
+
+// StartAutomationScheduler periodically runs schedule rules whose next
+// occurrence has come. Like recurring cards, progress is kept in the
+// database so restarts never run an occurrence twice.
+func StartAutomationScheduler(hub *ws.Hub, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		automationService := &services.AutomationService{}
+		for {
+			results, err := automationService.RunScheduled(database.DB, time.Now(), automationBatchSize)
+			if err != nil {
+				log.Printf("Scheduled automation run failed: %v", err)
+			}
+			reportAutomations(hub, results)
+			<-ticker.C
+		}
+	}()
+}
+
+// reportAutomations logs and broadcasts the changes of each run. Broadcasts
+// carry the run's rule chain, so rules they trigger count towards the loop limit.
+func reportAutomations(hub *ws.Hub, results []services.AutomationResult) {
+	for _, result := range results {
+		if len(result.Changes) == 0 {
+			continue
+		}
+
+		var cardIDs []uint
+		for _, change := range result.Changes {
+			cardIDs = append(cardIDs, change.CardIDs...)
+		}
+		utils.LogChainedActivity(result.Chain, "ran_automation", "automation", result.Rule.ID, result.Rule.BoardID, result.Rule.CreatedBy, result.Rule.Name, map[string]interface{}{
+			"run_id":   result.Run.ID,
+			"event":    result.Run.Event,
+			"card_ids": cardIDs,
+		})
+
+		if hub == nil {
+			continue
+		}
+		for _, change := range result.Changes {
+			hub.BroadcastEvent(events.Event{
+				BoardID: result.Rule.BoardID,
+				Type:    "cards_bulk_updated",
+				Data:    automationDetails(&result, &change),
+				Chain:   result.Chain,
+			})
+		}
+	}
+}
+
+// automationDetails describes an automation change like a bulk update
+func automationDetails(result *services.AutomationResult, change *services.AutomationChange) map[string]interface{} {
+	details := map[string]interface{}{
+		"operation": change.Op.Type,
+		"card_ids":  change.CardIDs,
+		"rule_id":   result.Rule.ID,
+	}
+	switch change.Op.Type {
+	case services.BulkMove:
+		details["list_id"] = change.Op.ListID
+	case services.BulkAddLabel, services.BulkRemoveLabel:
+		details["label_id"] = change.Op.LabelID
+	case services.BulkAssign, services.BulkUnassign:
+		if change.Op.MemberID != 0 {
+			details["member_id"] = change.Op.MemberID
+		}
+	case services.BulkSetDueDate:
+		details["due_date"] = change.Op.DueDate
+	}
+	return details
+}
+
+// eventPayload holds the payload fields triggers are read from
+type eventPayload struct {
+	ID         uint   `json:"id"`
+	CardID     uint   `json:"card_id"`
+	ListID     uint   `json:"list_id"`
+	OldListID  uint   `json:"old_list_id"`
+	NewListID  uint   `json:"new_list_id"`
+	LabelID    uint   `json:"label_id"`
+	MemberID   uint   `json:"member_id"`
+	Operation  string `json:"operation"`
+	CardIDs    []uint `json:"card_ids"`
+	EntityType string `json:"entity_type"`
+	EntityID   uint   `json:"entity_id"`
+}
+
+// bulkTriggers maps bulk operations to the trigger each changed card fires
+var bulkTriggers = map[string]string{
+	services.BulkMove:        services.TriggerCardMoved,
+	services.BulkAddLabel:    services.TriggerLabelAdded,
+	services.BulkRemoveLabel: services.TriggerLabelRemoved,
+	services.BulkAssign:      services.TriggerMemberAssigned,
+	services.BulkUnassign:    services.TriggerMemberUnassigned,
+}
+
+// automationTriggers turns a broadcast or activity into the triggers it fires
+func automationTriggers(event events.Event) []services.AutomationTrigger {
+	var payload eventPayload
+	raw, err := json.Marshal(event.Data)
+	if err != nil || json.Unmarshal(raw, &payload) != nil {
+		return nil
+	}
+
+	trigger := services.AutomationTrigger{BoardID: event.BoardID, Chain: event.Chain}
+	switch event.Source + ":" + event.Type {
+	case "broadcast:card_created":
+		trigger.Event, trigger.CardID, trigger.ListID = services.TriggerCardCreated, payload.ID, payload.ListID
+
+	case "broadcast:card_moved":
+		// Reordering inside a list is not a move for automation
+		if payload.OldListID == payload.NewListID {
+			return nil
+		}
+		trigger.Event, trigger.CardID = services.TriggerCardMoved, payload.CardID
+		trigger.ListID, trigger.FromListID = payload.NewListID, payload.OldListID
+
+	case "broadcast:card_label_added", "broadcast:card_label_removed":
+		trigger.Event, trigger.CardID, trigger.LabelID = services.TriggerLabelAdded, payload.CardID, payload.LabelID
+		if event.Type == "card_label_removed" {
+			trigger.Event = services.TriggerLabelRemoved
+		}
+
+	case "broadcast:card_member_assigned", "broadcast:card_member_unassigned":
+		trigger.Event, trigger.CardID, trigger.MemberID = services.TriggerMemberAssigned, payload.CardID, payload.MemberID
+		if event.Type == "card_member_unassigned" {
+			trigger.Event = services.TriggerMemberUnassigned
+		}
+
+	case "broadcast:cards_bulk_updated":
+		name, ok := bulkTriggers[payload.Operation]
+		if !ok {
+			return nil
+		}
+		triggers := make([]services.AutomationTrigger, len(payload.CardIDs))
+		for i, cardID := range payload.CardIDs {
+			triggers[i] = trigger
+			triggers[i].Event, triggers[i].CardID = name, cardID
+			triggers[i].ListID, triggers[i].LabelID, triggers[i].MemberID = payload.ListID, payload.LabelID, payload.MemberID
+		}
+		return triggers
+
+	case "activity:linked_card":
+		if payload.EntityType != "card" {
+			return nil
+		}
+		trigger.Event, trigger.CardID = services.TriggerCardLinked, payload.EntityID
+
+	default:
+		return nil
+	}
+	return []services.AutomationTrigger{trigger}
+}
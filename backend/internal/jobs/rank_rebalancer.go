@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"gorm.io/gorm"
+)
+
+// StartRankRebalancer periodically respaces lists and boards whose rank keys
+// have grown long from repeated inserts between the same neighbours
+func StartRankRebalancer(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			rebalanceRanks()
+		}
+	}()
+}
+
+// rebalanceRanks runs a single rebalance pass
+func rebalanceRanks() {
+	ordering := &services.OrderingService{}
+
+	var listIDs []uint
+	if err := database.DB.Model(&models.Card{}).
+		Where("LENGTH(rank) > ?", services.RebalanceRankLength).
+		Distinct("list_id").
+		Pluck("list_id", &listIDs).Error; err != nil {
+		log.Printf("Rank rebalance failed: %v", err)
+		return
+	}
+
+	for _, listID := range listIDs {
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return ordering.RebalanceCards(tx, listID)
+		}); err != nil {
+			log.Printf("Rank rebalance failed for list %d: %v", listID, err)
+		}
+	}
+
+	var boardIDs []uint
+	if err := database.DB.Model(&models.List{}).
+		Where("LENGTH(rank) > ?", services.RebalanceRankLength).
+		Distinct("board_id").
+		Pluck("board_id", &boardIDs).Error; err != nil {
+		log.Printf("Rank rebalance failed: %v", err)
+		return
+	}
+
+	for _, boardID := range boardIDs {
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return ordering.RebalanceLists(tx, boardID)
+		}); err != nil {
+			log.Printf("Rank rebalance failed for board %d: %v", boardID, err)
+		}
+	}
+
+	if len(listIDs) > 0 || len(boardIDs) > 0 {
+		log.Printf("🔢 Rank rebalance: %d lists and %d boards respaced", len(listIDs), len(boardIDs))
+	}
+}
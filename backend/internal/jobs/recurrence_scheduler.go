@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
+)
+
+// recurrenceBatchSize caps how many copies one pass makes, so a backlog is
+// worked through over several ticks
+const recurrenceBatchSize = 100
+
+// StartRecurrenceScheduler periodically copies recurring cards whose next
+// occurrence has come. Progress is kept in the database, so restarts and
+// several running instances never make the same copy twice.
+func StartRecurrenceScheduler(hub *ws.Hub, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runRecurrences(hub)
+			<-ticker.C
+		}
+	}()
+}
+
+// runRecurrences runs a single scheduling pass
+func runRecurrences(hub *ws.Hub) {
+	recurrenceService := &services.RecurrenceService{}
+
+	cards, err := recurrenceService.RunDue(database.DB, time.Now(), recurrenceBatchSize)
+	if err != nil {
+		log.Printf("Recurring card run failed: %v", err)
+	}
+
+	for _, card := range cards {
+		var list models.List
+		var schedule models.CardRecurrence
+		if database.DB.First(&list, card.ListID).Error != nil || database.DB.First(&schedule, *card.RecurrenceID).Error != nil {
+			continue
+		}
+
+		utils.LogActivity("created_recurring_card", "card", card.ID, list.BoardID, schedule.CreatedBy, card.Title, map[string]interface{}{
+			"template_card_id": schedule.CardID,
+			"occurrence_at":    card.OccurrenceAt,
+		})
+
+		if hub != nil {
+			hub.BroadcastToBoard(list.BoardID, "card_created", map[string]interface{}{
+				"id":            card.ID,
+				"title":         card.Title,
+				"description":   card.Description,
+				"list_id":       card.ListID,
+				"rank":          card.Rank,
+				"version":       card.Version,
+				"due_date":      card.DueDate,
+				"recurrence_id": card.RecurrenceID,
+				"created_at":    card.CreatedAt,
+			})
+		}
+	}
+
+	if len(cards) > 0 {
+		log.Printf("🔁 Recurring cards: %d copies created", len(cards))
+	}
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+)
+
+// UploadsDir is where attachment files are stored
+const UploadsDir = "./uploads"
+
+// orphanGracePeriod protects files whose attachment row is still being written
+const orphanGracePeriod = time.Hour
+
+// StartTrashPurger periodically hard-deletes trash older than retention
+// and removes upload files that no attachment references anymore
+func StartTrashPurger(retention, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purgeTrash(retention)
+			<-ticker.C
+		}
+	}()
+}
+
+// purgeTrash runs a single purge pass
+func purgeTrash(retention time.Duration) {
+	trashService := &services.TrashService{}
+
+	purged, err := trashService.PurgeExpired(retention)
+	if err != nil {
+		log.Printf("Trash purge failed: %v", err)
+		return
+	}
+
+	removed, err := trashService.RemoveOrphanedUploads(UploadsDir, orphanGracePeriod)
+	if err != nil {
+		log.Printf("Orphaned upload cleanup failed: %v", err)
+	}
+
+	if purged > 0 || removed > 0 {
+		log.Printf("🗑️ Trash purge: %d records deleted, %d orphaned files removed", purged, removed)
+	}
+}
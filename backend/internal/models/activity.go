@@ -8,18 +8,31 @@ import (
 
 // Activity represents an action taken on the board
 type Activity struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Action      string         `gorm:"not null" json:"action"`
-	EntityType  string         `gorm:"not null" json:"entity_type"`
-	EntityID    uint           `gorm:"not null" json:"entity_id"`
-	EntityTitle string         `json:"entity_title"`
-	BoardID     uint           `gorm:"not null" json:"board_id"`
-	UserID      uint           `gorm:"not null" json:"user_id"`
-	Metadata    string         `gorm:"type:text" json:"metadata"` // JSON string for extra data
-	CreatedAt   time.Time      `json:"created_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Action      string           `gorm:"not null;index" json:"action"`
+	EntityType  string           `gorm:"not null;index:idx_activities_entity" json:"entity_type"`
+	EntityID    uint             `gorm:"not null;index:idx_activities_entity" json:"entity_id"`
+	EntityTitle string           `json:"entity_title"`
+	BoardID     uint             `gorm:"not null;index:idx_activities_board_created" json:"board_id"`
+	UserID      uint             `gorm:"not null;index" json:"user_id"`
+	Metadata    ActivityMetadata `gorm:"type:jsonb;serializer:json" json:"metadata"`
+	CreatedAt   time.Time        `gorm:"index:idx_activities_board_created" json:"created_at"`
+	DeletedAt   gorm.DeletedAt   `gorm:"index" json:"-"`
 
 	// Relationships
 	Board Board `gorm:"foreignKey:BoardID" json:"-"`
 	User  User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
+
+// ActivityMetadata is what an activity changed and the context it happened in
+type ActivityMetadata struct {
+	Changes []FieldChange          `json:"changes,omitempty"` // Edited fields, in the order they were compared
+	Details map[string]interface{} `json:"details,omitempty"` // Action specific context, such as the list a card moved to
+}
+
+// FieldChange is one field's value before and after an edit
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
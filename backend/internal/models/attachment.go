@@ -17,6 +17,7 @@ type Attachment struct {
 	UploadedBy uint           `gorm:"not null" json:"uploaded_by"`
 	CreatedAt  time.Time      `json:"created_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy  *uint          `json:"-"` // User who moved it to the trash
 
 	// Relationships
 	Card     Card `gorm:"foreignKey:CardID" json:"card,omitempty"`
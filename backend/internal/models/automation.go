@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// AutomationRule runs actions on cards when a trigger fires on its board
+type AutomationRule struct {
+	ID        uint               `gorm:"primaryKey" json:"id"`
+	BoardID   uint               `gorm:"not null;index" json:"board_id"`
+	Name      string             `gorm:"not null" json:"name"`
+	Trigger   string             `gorm:"not null;index" json:"trigger"` // card_created, card_moved, label_added, ..., schedule
+	Filter    AutomationFilter   `gorm:"type:jsonb;serializer:json" json:"filter"`
+	Actions   []AutomationAction `gorm:"type:jsonb;serializer:json" json:"actions"`
+	Cron      string             `json:"cron,omitempty"` // Schedule: five-field cron expression
+	Timezone  string             `gorm:"not null;default:'UTC'" json:"timezone"`
+	NextRunAt *time.Time         `gorm:"index" json:"next_run_at,omitempty"` // Schedule: next time it fires
+	LastRunAt *time.Time         `json:"last_run_at,omitempty"`
+	Enabled   bool               `gorm:"not null;default:true" json:"enabled"`
+	CreatedBy uint               `gorm:"not null" json:"created_by"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+
+	// Relationships
+	Board Board `gorm:"foreignKey:BoardID" json:"-"`
+}
+
+// AutomationFilter narrows which events fire a rule. Zero fields match anything.
+type AutomationFilter struct {
+	ListID     uint `json:"list_id,omitempty"`      // card_created, card_moved: list the card is now in; schedule: cards to act on
+	FromListID uint `json:"from_list_id,omitempty"` // card_moved: list the card left
+	LabelID    uint `json:"label_id,omitempty"`     // label_added, label_removed
+	MemberID   uint `json:"member_id,omitempty"`    // member_assigned, member_unassigned
+}
+
+// AutomationAction is one change a rule makes to a card
+type AutomationAction struct {
+	Type      string `json:"type"`                  // move, add_label, remove_label, assign, unassign, set_due_date, complete_due, archive, unarchive, delete
+	ListID    uint   `json:"list_id,omitempty"`     // move
+	Position  *int   `json:"position,omitempty"`    // move: 0 is the top, end of list if omitted
+	LabelID   uint   `json:"label_id,omitempty"`    // add_label, remove_label
+	MemberID  uint   `json:"member_id,omitempty"`   // assign, unassign: unassign without a member removes everyone
+	DueInDays *int   `json:"due_in_days,omitempty"` // set_due_date: days from the run, omitted clears the date
+}
+
+// AutomationRun records one time a rule fired, including runs skipped by
+// loop protection
+type AutomationRun struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	RuleID    uint      `gorm:"not null;index" json:"rule_id"`
+	BoardID   uint      `gorm:"not null;index" json:"board_id"`
+	CardID    *uint     `json:"card_id,omitempty"` // Nil for scheduled runs, which act on a whole list
+	Event     string    `gorm:"not null" json:"event"`
+	Depth     int       `gorm:"not null;default:0" json:"depth"` // Rules that ran before this one in the same chain
+	Status    string    `gorm:"not null" json:"status"`          // succeeded, failed, skipped
+	Error     string    `json:"error,omitempty"`
+	Changed   int       `gorm:"not null;default:0" json:"changed"` // Cards the actions changed
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BoardEvent is a broadcast kept for WebSocket clients that reconnect. Seq
+// counts a board's broadcasts from 1 without gaps; only the latest are kept.
+type BoardEvent struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	BoardID   uint            `gorm:"not null;uniqueIndex:idx_board_events_board_seq,priority:1" json:"board_id"`
+	Seq       uint64          `gorm:"not null;uniqueIndex:idx_board_events_board_seq,priority:2" json:"seq"`
+	Type      string          `gorm:"not null" json:"type"`
+	Data      json.RawMessage `gorm:"type:jsonb;serializer:json" json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
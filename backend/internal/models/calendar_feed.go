@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// CalendarFeed is a secret iCalendar URL for a user's due dates. A feed with
+// a board covers that board; one without covers the cards assigned to the
+// user on every board they belong to. Each user has at most one feed per
+// board and one personal feed.
+type CalendarFeed struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_calendar_feeds_user_board;uniqueIndex:idx_calendar_feeds_user_personal,where:board_id IS NULL" json:"user_id"`
+	BoardID   *uint     `gorm:"uniqueIndex:idx_calendar_feeds_user_board" json:"board_id"`
+	Token     string    `gorm:"not null;uniqueIndex" json:"-"` // Secret in the feed URL
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	User  User   `gorm:"foreignKey:UserID" json:"-"`
+	Board *Board `gorm:"foreignKey:BoardID" json:"-"`
+}
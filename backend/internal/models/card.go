@@ -12,16 +12,36 @@ type Card struct {
 	Title       string         `gorm:"not null" json:"title"`
 	Description string         `gorm:"type:text" json:"description"`
 	ListID      uint           `gorm:"not null" json:"list_id"`
-	Position    int            `gorm:"not null;default:0" json:"position"` // Order within list
-	DueDate     *time.Time     `json:"due_date,omitempty"`                 // Pointer = can be null
+	Rank        string         `gorm:"type:text COLLATE \"C\";not null;default:'';index" json:"rank"` // Sort key within list
+	DueDate     *time.Time     `json:"due_date,omitempty"`                                            // Pointer = can be null
+	Version     int            `gorm:"not null;default:1" json:"version"`                             // Bumped on every change (optimistic locking)
+	ArchivedAt  *time.Time     `gorm:"index" json:"archived_at,omitempty"`                            // Hidden from the board but kept out of the trash
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy   *uint          `json:"-"` // User who moved it to the trash
+
+	// Set once the work due by DueDate is done
+	DueComplete bool `gorm:"not null;default:false" json:"due_complete"`
+
+	// When work on the card is planned to begin; shown with DueDate on the timeline
+	StartDate *time.Time `json:"start_date,omitempty"`
+
+	// Expected work in minutes, compared with tracked time
+	EstimateMinutes *int `json:"estimate_minutes,omitempty"`
+
+	// Swimlane the card is in; nil is the board's default lane
+	LaneID *uint `gorm:"index" json:"lane_id,omitempty"`
+
+	// Set on copies made by a recurrence, with the scheduled time they were made for
+	RecurrenceID *uint      `gorm:"uniqueIndex:idx_cards_recurrence_occurrence" json:"recurrence_id,omitempty"`
+	OccurrenceAt *time.Time `gorm:"uniqueIndex:idx_cards_recurrence_occurrence" json:"occurrence_at,omitempty"`
 
 	// Relationships
-	List        List         `gorm:"foreignKey:ListID" json:"list,omitempty"`
-	Members     []User       `gorm:"many2many:card_members" json:"members,omitempty"`
-	Labels      []Label      `gorm:"many2many:card_labels" json:"labels,omitempty"`
-	Comments    []Comment    `gorm:"foreignKey:CardID;constraint:OnDelete:CASCADE" json:"comments,omitempty"`
-	Attachments []Attachment `gorm:"foreignKey:CardID;constraint:OnDelete:CASCADE" json:"attachments,omitempty"`
+	List        List             `gorm:"foreignKey:ListID" json:"list,omitempty"`
+	Members     []User           `gorm:"many2many:card_members" json:"members,omitempty"`
+	Labels      []Label          `gorm:"many2many:card_labels" json:"labels,omitempty"`
+	Comments    []Comment        `gorm:"foreignKey:CardID;constraint:OnDelete:CASCADE" json:"comments,omitempty"`
+	Attachments []Attachment     `gorm:"foreignKey:CardID;constraint:OnDelete:CASCADE" json:"attachments,omitempty"`
+	FieldValues []CardFieldValue `gorm:"foreignKey:CardID;constraint:OnDelete:CASCADE" json:"field_values,omitempty"`
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // CardLabel represents the many-to-many relationship between cards and labels
 type CardLabel struct {
 	CardID  uint `gorm:"primaryKey" json:"card_id"`
@@ -9,3 +11,14 @@ type CardLabel struct {
 	Card  Card  `gorm:"foreignKey:CardID" json:"-"`
 	Label Label `gorm:"foreignKey:LabelID" json:"-"`
 }
+
+// CardLabelSpan is a period during which a card had a label, so label-driven
+// sprints know their scope on past days. Spans are written by a trigger on
+// card_labels, which sees every way a label is added or removed.
+type CardLabelSpan struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	CardID    uint       `gorm:"not null;index" json:"card_id"`
+	LabelID   uint       `gorm:"not null;index" json:"label_id"`
+	AddedAt   time.Time  `gorm:"not null" json:"added_at"`
+	RemovedAt *time.Time `json:"removed_at,omitempty"` // Nil while the card has the label
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// CardRecurrence recreates a card on a schedule. The card it belongs to is the
+// template; each occurrence adds a copy of it to TargetListID.
+type CardRecurrence struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	CardID       uint       `gorm:"not null;uniqueIndex" json:"card_id"`
+	TargetListID uint       `gorm:"not null" json:"target_list_id"`
+	Frequency    string     `gorm:"not null" json:"frequency"` // daily, weekly, monthly, cron
+	Interval     int        `gorm:"not null;default:1" json:"interval"`
+	Weekdays     []int      `gorm:"type:jsonb;serializer:json" json:"weekdays"` // Weekly: 0 (Sunday) to 6
+	DayOfMonth   int        `gorm:"not null;default:0" json:"day_of_month"`     // Monthly: 0 means the start date's day
+	TimeOfDay    string     `gorm:"not null;default:'09:00'" json:"time_of_day"`
+	Cron         string     `json:"cron,omitempty"`
+	Timezone     string     `gorm:"not null;default:'UTC'" json:"timezone"`
+	StartsAt     time.Time  `gorm:"not null" json:"starts_at"` // Intervals count from here; copies' due dates shift by the time since
+	NextRunAt    time.Time  `gorm:"not null;index" json:"next_run_at"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	Paused       bool       `gorm:"not null;default:false" json:"paused"`
+	CreatedBy    uint       `gorm:"not null" json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relationships
+	Card       Card `gorm:"foreignKey:CardID" json:"-"`
+	TargetList List `gorm:"foreignKey:TargetListID" json:"-"`
+}
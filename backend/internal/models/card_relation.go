@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// CardRelation links two cards, possibly on different boards. For blocks,
+// the source card blocks the target card; relates_to has no direction.
+type CardRelation struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SourceCardID uint      `gorm:"not null;uniqueIndex:idx_card_relations_pair" json:"source_card_id"`
+	TargetCardID uint      `gorm:"not null;uniqueIndex:idx_card_relations_pair;index" json:"target_card_id"`
+	Type         string    `gorm:"not null;uniqueIndex:idx_card_relations_pair" json:"type"` // blocks, relates_to
+	CreatedBy    uint      `gorm:"not null" json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relationships
+	SourceCard Card `gorm:"foreignKey:SourceCardID" json:"-"`
+	TargetCard Card `gorm:"foreignKey:TargetCardID" json:"-"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CardRevision records one change to a tracked field of a card. Values are
+// JSON: a string for title and description, a timestamp or null for the due
+// date, a list ID, and the sorted IDs of the card's labels or members.
+type CardRevision struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	CardID    uint            `gorm:"not null;index:idx_card_revisions_card_field,priority:1" json:"card_id"`
+	Field     string          `gorm:"not null;index:idx_card_revisions_card_field,priority:2" json:"field"`
+	OldValue  json.RawMessage `gorm:"type:jsonb;serializer:json" json:"old_value"`
+	NewValue  json.RawMessage `gorm:"type:jsonb;serializer:json" json:"new_value"`
+	UserID    *uint           `json:"user_id"` // Nil for changes made by the system
+	CreatedAt time.Time       `gorm:"index" json:"created_at"`
+
+	// Relationships
+	Card Card  `gorm:"foreignKey:CardID" json:"-"`
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// CardTransition records a card entering a list: on creation (no from-list)
+// or when moved. Flow analytics are computed from these rows.
+type CardTransition struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CardID     uint      `gorm:"not null;index:idx_card_transitions_card,priority:1" json:"card_id"`
+	BoardID    uint      `gorm:"not null;index" json:"board_id"` // Board of the destination list
+	FromListID *uint     `json:"from_list_id"`
+	ToListID   uint      `gorm:"not null;index" json:"to_list_id"`
+	UserID     *uint     `json:"user_id"` // Nil for moves made by the system
+	MovedAt    time.Time `gorm:"not null;index:idx_card_transitions_card,priority:2" json:"moved_at"`
+
+	// Relationships
+	Card Card `gorm:"foreignKey:CardID" json:"-"`
+}
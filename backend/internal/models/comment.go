@@ -15,6 +15,7 @@ type Comment struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy *uint          `json:"-"` // User who moved it to the trash
 
 	// Relationships
 	Card Card `gorm:"foreignKey:CardID" json:"card,omitempty"`
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// CustomField is a board-level field definition, such as story points or priority
+type CustomField struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	BoardID   uint      `gorm:"not null;uniqueIndex:idx_custom_fields_board_name" json:"board_id"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_custom_fields_board_name" json:"name"`
+	Type      string    `gorm:"not null" json:"type"`                      // text, number, date, checkbox, select, multi_select
+	Options   []string  `gorm:"type:jsonb;serializer:json" json:"options"` // Choices for select and multi_select
+	Position  int       `gorm:"not null;default:0" json:"position"`        // Display order on the card
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Board Board `gorm:"foreignKey:BoardID" json:"-"`
+}
+
+// CardFieldValue is a card's value for a custom field. Only the column
+// matching the field's type is set.
+type CardFieldValue struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	CardID      uint       `gorm:"not null;uniqueIndex:idx_card_field_values_card_field" json:"card_id"`
+	FieldID     uint       `gorm:"not null;uniqueIndex:idx_card_field_values_card_field;index" json:"field_id"`
+	TextValue   *string    `gorm:"type:text" json:"text_value,omitempty"`
+	NumberValue *float64   `json:"number_value,omitempty"`
+	DateValue   *time.Time `json:"date_value,omitempty"`
+	Checked     *bool      `json:"checked,omitempty"`
+	Options     []string   `gorm:"type:jsonb;serializer:json" json:"options,omitempty"` // Selected choices
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationships
+	Card  Card        `gorm:"foreignKey:CardID" json:"-"`
+	Field CustomField `gorm:"foreignKey:FieldID" json:"-"`
+}
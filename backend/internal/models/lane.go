@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Lane is a horizontal swimlane across a board's lists. Cards without a lane
+// belong to the board's default lane.
+type Lane struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	BoardID   uint      `gorm:"not null;index" json:"board_id"`
+	Title     string    `gorm:"not null" json:"title"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Board Board `gorm:"foreignKey:BoardID" json:"-"`
+}
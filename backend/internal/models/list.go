@@ -11,10 +11,17 @@ type List struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	Title     string         `gorm:"not null" json:"title"`
 	BoardID   uint           `gorm:"not null" json:"board_id"`
-	Position  int            `gorm:"not null;default:0" json:"position"` // Order of lists (0, 1, 2...)
+	Rank      string         `gorm:"type:text COLLATE \"C\";not null;default:'';index" json:"rank"` // Sort key within board
+	Version   int            `gorm:"not null;default:1" json:"version"`                             // Bumped on every change (optimistic locking)
+	IsDone    bool           `gorm:"not null;default:false" json:"is_done"`                         // Cards here count as finished
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy *uint          `json:"-"` // User who moved it to the trash
+
+	// Work-in-progress limit on unarchived cards; nil means no limit
+	WIPLimit *int   `json:"wip_limit,omitempty"`
+	WIPMode  string `gorm:"not null;default:'soft'" json:"wip_mode"` // soft warns, hard refuses cards over the limit
 
 	// Relationships
 	Board Board  `gorm:"foreignKey:BoardID" json:"board,omitempty"`
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SavedFilter is a named card search query saved by a user
+type SavedFilter struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	UserID          uint      `gorm:"not null;uniqueIndex:idx_saved_filters_user_name" json:"user_id"`
+	Name            string    `gorm:"not null;uniqueIndex:idx_saved_filters_user_name" json:"name"`
+	Query           string    `gorm:"type:text;not null" json:"query"` // Search query grammar, e.g. label:bug member:me
+	ShowOnDashboard bool      `gorm:"not null;default:false" json:"show_on_dashboard"`
+	FeedToken       *string   `gorm:"uniqueIndex" json:"-"` // Secret in the RSS feed URL; nil when the feed is off
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
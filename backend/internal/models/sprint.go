@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Sprint is a date range of work on a board, such as a two-week sprint or a
+// milestone. Its cards are those added explicitly plus, when LabelID is set,
+// every card with that label.
+type Sprint struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	BoardID       uint      `gorm:"not null;index" json:"board_id"`
+	Name          string    `gorm:"not null" json:"name"`
+	Goal          string    `gorm:"type:text" json:"goal"`
+	StartDate     time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate       time.Time `gorm:"type:date;not null" json:"end_date"` // Last day, inclusive
+	LabelID       *uint     `json:"label_id,omitempty"`
+	PointsFieldID *uint     `json:"points_field_id,omitempty"` // Number custom field holding story points
+	CreatedBy     uint      `gorm:"not null" json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relationships
+	Board Board `gorm:"foreignKey:BoardID" json:"-"`
+}
+
+// SprintCard adds a card to a sprint explicitly
+type SprintCard struct {
+	SprintID  uint      `gorm:"primaryKey" json:"sprint_id"`
+	CardID    uint      `gorm:"primaryKey;index" json:"card_id"`
+	CreatedAt time.Time `json:"created_at"` // When the card joined the sprint
+
+	// Relationships
+	Sprint Sprint `gorm:"foreignKey:SprintID" json:"-"`
+	Card   Card   `gorm:"foreignKey:CardID" json:"-"`
+}
+
+// SprintCardSpan is a period during which a card was added to a sprint
+// explicitly. Spans are written by a trigger on sprint_cards.
+type SprintCardSpan struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	SprintID  uint       `gorm:"not null;index" json:"sprint_id"`
+	CardID    uint       `gorm:"not null;index" json:"card_id"`
+	AddedAt   time.Time  `gorm:"not null" json:"added_at"`
+	RemovedAt *time.Time `json:"removed_at,omitempty"` // Nil while the card is in the sprint
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TimeEntry is time a user spent on a card. An entry without an end is a
+// running timer; each user has at most one.
+type TimeEntry struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	CardID    uint       `gorm:"not null;index" json:"card_id"`
+	UserID    uint       `gorm:"not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL" json:"user_id"`
+	StartedAt time.Time  `gorm:"not null;index" json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relationships
+	Card Card `gorm:"foreignKey:CardID" json:"-"`
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
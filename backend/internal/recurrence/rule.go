@@ -0,0 +1,166 @@
+// Package recurrence computes when recurring cards are due to be recreated
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	_ "time/tzdata" // Time zones work on hosts without a zoneinfo database
+)
+
+// Frequencies
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Cron    = "cron"
+)
+
+// searchDays bounds how far ahead Next looks for a calendar occurrence
+const searchDays = 5 * 366
+
+// Rule describes when a card recurs. Daily, weekly and monthly rules fire at
+// TimeOfDay every Interval days, weeks or months counted from the start date.
+type Rule struct {
+	Frequency  string
+	Interval   int            // Every n days, weeks or months; 1 if zero
+	Weekdays   []time.Weekday // Weekly: days to fire on, the start's weekday if empty
+	DayOfMonth int            // Monthly: day to fire on, the start's day if zero; clamped to short months
+	TimeOfDay  string         // HH:MM in Location; ignored for cron
+	Cron       string         // Cron: five-field expression
+	Location   *time.Location // UTC if nil
+}
+
+// Validate checks the rule can produce occurrences
+func (r *Rule) Validate() error {
+	switch r.Frequency {
+	case Daily, Weekly, Monthly:
+		if _, _, err := r.clock(); err != nil {
+			return err
+		}
+	case Cron:
+		if _, err := parseCron(r.Cron); err != nil {
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown frequency %q", r.Frequency)
+	}
+
+	if r.Interval < 0 {
+		return errors.New("interval must be positive")
+	}
+	for _, day := range r.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid weekday %d", day)
+		}
+	}
+	if r.DayOfMonth < 0 || r.DayOfMonth > 31 {
+		return errors.New("day of month must be between 1 and 31")
+	}
+	return nil
+}
+
+// Next returns the first occurrence strictly after after, counting intervals
+// from start. It returns the zero time if the rule is invalid or has no
+// occurrence in the next five years.
+func (r *Rule) Next(start, after time.Time) time.Time {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	start, after = start.In(loc), after.In(loc)
+	if after.Before(start) {
+		after = start.Add(-time.Nanosecond)
+	}
+
+	if r.Frequency == Cron {
+		schedule, err := parseCron(r.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return schedule.next(after)
+	}
+
+	hour, minute, err := r.clock()
+	if err != nil {
+		return time.Time{}
+	}
+
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+	for i := 0; i < searchDays; i, day = i+1, day.AddDate(0, 0, 1) {
+		if !r.matches(start, day) {
+			continue
+		}
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+		if occurrence.After(after) {
+			return occurrence
+		}
+	}
+	return time.Time{}
+}
+
+// matches reports whether the rule fires on day
+func (r *Rule) matches(start, day time.Time) bool {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Frequency {
+	case Daily:
+		return civilDays(start, day)%interval == 0
+
+	case Weekly:
+		weekdays := r.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+		found := false
+		for _, weekday := range weekdays {
+			found = found || weekday == day.Weekday()
+		}
+		// Weeks start on Sunday, matching time.Weekday
+		weeks := (civilDays(start, day) + int(start.Weekday())) / 7
+		return found && weeks%interval == 0
+
+	case Monthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+		if months%interval != 0 {
+			return false
+		}
+		target := r.DayOfMonth
+		if target == 0 {
+			target = start.Day()
+		}
+		if last := daysIn(day.Year(), day.Month()); target > last {
+			target = last
+		}
+		return day.Day() == target
+	}
+	return false
+}
+
+// clock parses TimeOfDay, defaulting to midnight
+func (r *Rule) clock() (int, int, error) {
+	if r.TimeOfDay == "" {
+		return 0, 0, nil
+	}
+	t, err := time.Parse("15:04", r.TimeOfDay)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time of day %q, use HH:MM", r.TimeOfDay)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// civilDays counts calendar days from a to b, ignoring time of day and DST
+func civilDays(a, b time.Time) int {
+	from := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
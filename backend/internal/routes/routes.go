@@ -23,6 +23,10 @@ func SetupRoutes(router *gin.Engine, hub *ws.Hub) {
 
 		api.GET("/ws", handlers.HandleWebSocket(hub))
 
+		// Public feeds, authenticated by the secret token in the URL
+		api.GET("/feeds/filters/:token", handlers.GetSavedFilterFeed)
+		api.GET("/feeds/calendar/:token", handlers.GetCalendarFeedEvents)
+
 		// Protected routes
 		protected := api.Group("")
 		protected.Use(middleware.AuthRequired())
@@ -56,6 +60,47 @@ func SetupRoutes(router *gin.Engine, hub *ws.Hub) {
 				boards.POST("/:id/members", middleware.RequirePermission("invite_member"), handlers.InviteMember)
 				boards.DELETE("/:id/members/:member_id", middleware.RequirePermission("manage_members"), handlers.RemoveMember)
 				boards.PUT("/:id/members/:user_id/role", middleware.RequirePermission("manage_members"), handlers.UpdateMemberRole)
+
+				// Board trash routes
+				boards.GET("/:id/trash", middleware.RequireBoardAccess(), handlers.GetBoardTrash)
+				boards.POST("/:id/trash/:type/:item_id/restore", middleware.RequireBoardAccess(), handlers.RestoreTrashItem)
+				boards.DELETE("/:id/trash/:type/:item_id", middleware.RequireAdmin(), handlers.PurgeTrashItem)
+
+				// Board custom field routes
+				boards.GET("/:id/fields", middleware.RequireBoardAccess(), handlers.GetCustomFields)
+				boards.POST("/:id/fields", middleware.RequirePermission("edit_board"), handlers.CreateCustomField)
+
+				// Board swimlane routes
+				boards.GET("/:id/lanes", middleware.RequireBoardAccess(), handlers.GetLanes)
+				boards.POST("/:id/lanes", middleware.RequirePermission("edit_board"), handlers.CreateLane)
+
+				// Board automation routes
+				boards.GET("/:id/automations", middleware.RequireBoardAccess(), handlers.GetAutomationRules)
+				boards.POST("/:id/automations", middleware.RequirePermission("edit_board"), handlers.CreateAutomationRule)
+				boards.GET("/:id/automations/runs", middleware.RequireBoardAccess(), handlers.GetAutomationRuns)
+
+				// Board timesheet routes
+				boards.GET("/:id/timesheet", middleware.RequireBoardAccess(), handlers.GetBoardTimesheet)
+
+				// Board calendar feed routes
+				boards.POST("/:id/calendar-feed", middleware.RequireBoardAccess(), handlers.EnableBoardCalendarFeed)
+				boards.DELETE("/:id/calendar-feed", middleware.RequireBoardAccess(), handlers.DisableBoardCalendarFeed)
+
+				// Board calendar and timeline routes
+				boards.GET("/:id/calendar", middleware.RequireBoardAccess(), handlers.GetBoardCalendar)
+				boards.GET("/:id/timeline", middleware.RequireBoardAccess(), handlers.GetBoardTimeline)
+
+				// Board sprint routes
+				boards.GET("/:id/sprints", middleware.RequireBoardAccess(), handlers.GetSprints)
+				boards.POST("/:id/sprints", middleware.RequirePermission("edit_board"), handlers.CreateSprint)
+
+				// Board presence routes
+				boards.GET("/:id/presence", middleware.RequireBoardAccess(), handlers.GetBoardPresence)
+
+				// Board analytics routes
+				boards.GET("/:id/analytics/cycle-time", middleware.RequireBoardAccess(), handlers.GetCycleTimes)
+				boards.GET("/:id/analytics/throughput", middleware.RequireBoardAccess(), handlers.GetThroughput)
+				boards.GET("/:id/analytics/cfd", middleware.RequireBoardAccess(), handlers.GetCumulativeFlow)
 			}
 
 			// List routes
@@ -67,6 +112,7 @@ func SetupRoutes(router *gin.Engine, hub *ws.Hub) {
 				lists.PUT("/:id", handlers.UpdateList)
 				lists.PATCH("/:id", handlers.UpdateList)
 				lists.POST("/:id/move", handlers.MoveList)
+				lists.POST("/:id/repair", handlers.RepairListOrder)
 				lists.DELETE("/:id", handlers.DeleteList)
 			}
 
@@ -79,6 +125,25 @@ func SetupRoutes(router *gin.Engine, hub *ws.Hub) {
 				cards.PUT("/:id", handlers.UpdateCard)
 				cards.PATCH("/:id", handlers.UpdateCard)
 				cards.POST("/:id/move", handlers.MoveCard)
+				cards.POST("/bulk", handlers.BulkUpdateCards)
+				cards.PUT("/:id/fields/:field_id", handlers.SetCardFieldValue)
+				cards.GET("/:id/relations", handlers.GetCardRelations)
+				cards.POST("/:id/relations", handlers.CreateCardRelation)
+				cards.DELETE("/:id/relations/:relation_id", handlers.DeleteCardRelation)
+				cards.GET("/:id/recurrence", handlers.GetCardRecurrence)
+				cards.PUT("/:id/recurrence", handlers.SetCardRecurrence)
+				cards.POST("/:id/recurrence/pause", handlers.PauseCardRecurrence)
+				cards.POST("/:id/recurrence/resume", handlers.ResumeCardRecurrence)
+				cards.DELETE("/:id/recurrence", handlers.DeleteCardRecurrence)
+				cards.GET("/:id/time", handlers.GetCardTime)
+				cards.POST("/:id/time", handlers.CreateTimeEntry)
+				cards.POST("/:id/time/start", handlers.StartCardTimer)
+				cards.POST("/:id/time/stop", handlers.StopCardTimer)
+				cards.GET("/:id/transitions", handlers.GetCardTransitions)
+				cards.GET("/:id/history", handlers.GetCardHistory)
+				cards.GET("/:id/history/:revision_id/diff", handlers.GetCardRevisionDiff)
+				cards.POST("/:id/history/:revision_id/revert", handlers.RevertCardRevision)
+				cards.PATCH("/:id/schedule", handlers.RescheduleCard)
 				cards.DELETE("/:id", handlers.DeleteCard)
 			}
 
@@ -104,6 +169,51 @@ func SetupRoutes(router *gin.Engine, hub *ws.Hub) {
 				labels.DELETE("/card/:card_id/:label_id", handlers.RemoveLabelFromCard)
 			}
 
+			// Custom field routes
+			customFields := protected.Group("/fields")
+			{
+				customFields.PUT("/:id", handlers.UpdateCustomField)
+				customFields.PATCH("/:id", handlers.UpdateCustomField)
+				customFields.DELETE("/:id", handlers.DeleteCustomField)
+			}
+
+			// Swimlane routes
+			lanes := protected.Group("/lanes")
+			{
+				lanes.PUT("/:id", handlers.UpdateLane)
+				lanes.PATCH("/:id", handlers.UpdateLane)
+				lanes.DELETE("/:id", handlers.DeleteLane)
+			}
+
+			// Automation rule routes
+			automations := protected.Group("/automations")
+			{
+				automations.PUT("/:id", handlers.UpdateAutomationRule)
+				automations.DELETE("/:id", handlers.DeleteAutomationRule)
+			}
+
+			// Sprint routes
+			sprints := protected.Group("/sprints")
+			{
+				sprints.GET("/:id", handlers.GetSprint)
+				sprints.PUT("/:id", handlers.UpdateSprint)
+				sprints.PATCH("/:id", handlers.UpdateSprint)
+				sprints.DELETE("/:id", handlers.DeleteSprint)
+				sprints.POST("/:id/cards", handlers.AddSprintCards)
+				sprints.DELETE("/:id/cards/:card_id", handlers.RemoveSprintCard)
+				sprints.GET("/:id/burndown", handlers.GetSprintBurndown)
+				sprints.GET("/:id/burnup", handlers.GetSprintBurnup)
+			}
+
+			// Time entry routes
+			timeEntries := protected.Group("/time-entries")
+			{
+				timeEntries.GET("/running", handlers.GetRunningTimer)
+				timeEntries.PUT("/:id", handlers.UpdateTimeEntry)
+				timeEntries.PATCH("/:id", handlers.UpdateTimeEntry)
+				timeEntries.DELETE("/:id", handlers.DeleteTimeEntry)
+			}
+
 			// Card Member routes
 			cardMembers := protected.Group("/card-members")
 			{
@@ -120,6 +230,25 @@ func SetupRoutes(router *gin.Engine, hub *ws.Hub) {
 				search.GET("/upcoming", handlers.GetUpcomingCards)
 			}
 
+			// Saved filter routes
+			filters := protected.Group("/filters")
+			{
+				filters.GET("", handlers.GetSavedFilters)
+				filters.POST("", handlers.CreateSavedFilter)
+				filters.PUT("/:id", handlers.UpdateSavedFilter)
+				filters.DELETE("/:id", handlers.DeleteSavedFilter)
+				filters.GET("/:id/cards", handlers.GetSavedFilterCards)
+				filters.POST("/:id/feed", handlers.EnableSavedFilterFeed)
+				filters.DELETE("/:id/feed", handlers.DisableSavedFilterFeed)
+			}
+			protected.GET("/dashboard", handlers.GetDashboard)
+			protected.GET("/me/work", handlers.GetMyWork)
+
+			// Calendar feed routes
+			protected.GET("/me/calendar-feeds", handlers.GetCalendarFeeds)
+			protected.POST("/me/calendar-feed", handlers.EnableCalendarFeed)
+			protected.DELETE("/me/calendar-feed", handlers.DisableCalendarFeed)
+
 			// Activity routes
 			activities := protected.Group("/activities")
 			{
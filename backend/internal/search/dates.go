@@ -0,0 +1,62 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateRange resolves a date value to the half-open interval [start, end).
+// Values cover a whole day: 2024-05-01, today, or the day an offset such as
+// 7d or -2w lands on. Hour offsets (12h) cover the hour they land in.
+func dateRange(value string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch strings.ToLower(value) {
+	case "today":
+		return today, today.AddDate(0, 0, 1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, nil
+	}
+
+	if day, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return day, day.AddDate(0, 0, 1), nil
+	}
+
+	offset, err := parseOffset(value)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if strings.HasSuffix(value, "h") {
+		hour := now.Add(offset).Truncate(time.Hour)
+		return hour, hour.Add(time.Hour), nil
+	}
+	day := today.AddDate(0, 0, int(offset/(24*time.Hour)))
+	return day, day.AddDate(0, 0, 1), nil
+}
+
+// parseOffset reads a signed duration in hours, days or weeks, such as -3d
+func parseOffset(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid date %q, use YYYY-MM-DD, today or an offset like 7d", value)
+	if len(value) < 2 {
+		return 0, invalid
+	}
+
+	unit := map[byte]time.Duration{
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}[value[len(value)-1]]
+	if unit == 0 {
+		return 0, invalid
+	}
+
+	amount, err := strconv.Atoi(strings.TrimPrefix(value[:len(value)-1], "+"))
+	if err != nil {
+		return 0, invalid
+	}
+	return time.Duration(amount) * unit, nil
+}
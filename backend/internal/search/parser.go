@@ -0,0 +1,313 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError reports where a query could not be parsed
+type SyntaxError struct {
+	Pos int // Byte offset in the query
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s (at position %d)", e.Msg, e.Pos+1)
+}
+
+// Parse reads a search query. The grammar is:
+//
+//	query := or
+//	or    := and { "OR" and }
+//	and   := unary { ["AND"] unary }      terms next to each other are ANDed
+//	unary := ("NOT" | "-") unary | "(" or ")" | term
+//	term  := field op value | word | "quoted phrase"
+//	op    := ":" | "=" | "<" | "<=" | ">" | ">="
+//
+// Fields are label, member, list, board, due, created, updated and has.
+// Dates accept YYYY-MM-DD, today, tomorrow, yesterday and offsets from now
+// such as 7d, -2w or 12h. Custom fields are matched by name after "field.",
+// and field.<name>:none matches cards without a value. For example:
+//
+//	label:bug AND member:me AND due<7d NOT list:Done
+//	field."story points">=5 field.priority:high
+func Parse(input string) (*Query, error) {
+	p := &parser{tokens: tokenize(input), input: input}
+	if len(p.tokens) == 0 {
+		return &Query{}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	return &Query{Root: root, Text: strings.Join(positiveText(root, false), " ")}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize splits a query into words, quoted phrases and parentheses.
+// A quote inside a word (label:"needs review") is kept as part of the word.
+func tokenize(input string) []token {
+	var tokens []token
+	runes := []rune(input)
+	offset := func(i int) int { return len(string(runes[:i])) }
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: offset(i)})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: offset(i)})
+			i++
+		case r == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			text := string(runes[start+1 : min(i, len(runes))])
+			tokens = append(tokens, token{kind: tokenPhrase, text: text, pos: offset(start)})
+			i++ // Skip the closing quote
+		default:
+			start := i
+			quoted := false
+			for i < len(runes) {
+				if runes[i] == '"' {
+					quoted = !quoted
+				} else if !quoted && (unicode.IsSpace(runes[i]) || runes[i] == '(' || runes[i] == ')') {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: offset(start)})
+		}
+	}
+	return tokens
+}
+
+type parser struct {
+	tokens []token
+	input  string
+	next   int
+}
+
+func (p *parser) peek() *token {
+	if p.next >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.next]
+}
+
+// keyword reports whether the next token is the given operator keyword
+func (p *parser) keyword(word string) bool {
+	tok := p.peek()
+	return tok != nil && tok.kind == tokenWord && tok.text == word
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		p.next++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind == tokenClose || p.keyword("OR") {
+			return left, nil
+		}
+		if p.keyword("AND") {
+			p.next++
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	if tok == nil {
+		return nil, &SyntaxError{Pos: len(p.input), Msg: "query ends unexpectedly"}
+	}
+
+	switch {
+	case p.keyword("NOT"):
+		p.next++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: operand}, nil
+
+	case tok.kind == tokenWord && (tok.text == "AND" || tok.text == "OR"):
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("%s needs a term on each side", tok.text)}
+
+	case tok.kind == tokenOpen:
+		p.next++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != tokenClose {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "missing closing parenthesis"}
+		}
+		p.next++
+		return inner, nil
+
+	case tok.kind == tokenClose:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unexpected closing parenthesis"}
+
+	case tok.kind == tokenPhrase:
+		p.next++
+		return &Text{Value: tok.text, Phrase: true}, nil
+	}
+
+	// A leading "-" negates a word or field: -label:wontfix
+	p.next++
+	text := tok.text
+	if strings.HasPrefix(text, "-") && len(text) > 1 {
+		term, err := parseTerm(text[1:], tok.pos+1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: term}, nil
+	}
+	return parseTerm(text, tok.pos)
+}
+
+// parseTerm reads a single field filter or free-text word
+func parseTerm(text string, pos int) (Node, error) {
+	name, key, op, value, ok := splitField(text)
+	if !ok {
+		return &Text{Value: text}, nil
+	}
+
+	value = strings.Trim(value, `"`)
+	if value == "" {
+		if key != "" {
+			name = customFieldPrefix + key
+		}
+		return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("%s needs a value", name)}
+	}
+
+	field := &Field{Name: name, Key: key, Op: op, Value: value}
+	if err := field.validate(); err != nil {
+		return nil, &SyntaxError{Pos: pos, Msg: err.Error()}
+	}
+	return field, nil
+}
+
+// splitField splits "due<=7d" into due, <=, 7d. Words whose prefix is not a
+// known field (for example "http://host") are left as free text. Custom
+// fields are named after a "field." prefix, quoted if they contain spaces.
+func splitField(text string) (name, key, op, value string, ok bool) {
+	if strings.HasPrefix(strings.ToLower(text), customFieldPrefix) {
+		return splitCustomField(text[len(customFieldPrefix):])
+	}
+
+	i := strings.IndexAny(text, ":=<>")
+	if i <= 0 {
+		return "", "", "", "", false
+	}
+
+	// A custom field is only reached through its "field." prefix
+	name = strings.ToLower(text[:i])
+	if _, known := fields[name]; !known || name == customFieldName {
+		return "", "", "", "", false
+	}
+
+	op, value = splitOp(text[i:])
+	return name, "", op, value, true
+}
+
+// splitCustomField splits `"story points">=5` into the custom field name,
+// >= and 5
+func splitCustomField(text string) (name, key, op, value string, ok bool) {
+	var rest string
+	if strings.HasPrefix(text, `"`) {
+		end := strings.Index(text[1:], `"`)
+		if end < 0 {
+			return "", "", "", "", false
+		}
+		key, rest = text[1:end+1], text[end+2:]
+	} else {
+		i := strings.IndexAny(text, ":=<>")
+		if i < 0 {
+			return "", "", "", "", false
+		}
+		key, rest = text[:i], text[i:]
+	}
+
+	if key == "" || rest == "" || !strings.ContainsAny(rest[:1], ":=<>") {
+		return "", "", "", "", false
+	}
+	op, value = splitOp(rest)
+	return customFieldName, key, op, value, true
+}
+
+// splitOp splits an operator from the value that follows it
+func splitOp(text string) (op, value string) {
+	op = text[:1]
+	if (op == "<" || op == ">") && strings.HasPrefix(text[1:], "=") {
+		op += "="
+	}
+	return op, text[len(op):]
+}
+
+// positiveText collects the free-text terms that are not negated, used to
+// rank and highlight results
+func positiveText(node Node, negated bool) []string {
+	switch n := node.(type) {
+	case *And:
+		return append(positiveText(n.Left, negated), positiveText(n.Right, negated)...)
+	case *Or:
+		return append(positiveText(n.Left, negated), positiveText(n.Right, negated)...)
+	case *Not:
+		return positiveText(n.Operand, !negated)
+	case *Text:
+		if !negated {
+			return []string{n.Value}
+		}
+	}
+	return nil
+}
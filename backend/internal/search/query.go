@@ -0,0 +1,280 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query is a parsed search query
+type Query struct {
+	Root Node   // nil when the query is empty
+	Text string // Free-text terms that are not negated, for ranking
+}
+
+// Context holds what a query needs to be compiled for one user at one moment
+type Context struct {
+	UserID uint      // Resolves member:me
+	Now    time.Time // Resolves relative dates
+}
+
+// Condition compiles the query into a SQL condition over the cards table
+// joined with lists. It returns an empty string for an empty query.
+func (q *Query) Condition(ctx Context) (string, []interface{}) {
+	if q == nil || q.Root == nil {
+		return "", nil
+	}
+	return q.Root.sql(ctx)
+}
+
+// Node is one element of a parsed query
+type Node interface {
+	sql(ctx Context) (string, []interface{})
+}
+
+// And matches cards matching both sides
+type And struct{ Left, Right Node }
+
+// Or matches cards matching either side
+type Or struct{ Left, Right Node }
+
+// Not matches cards not matching its operand
+type Not struct{ Operand Node }
+
+// Text matches a word or phrase in a card's title, description, comments or attachment names
+type Text struct {
+	Value  string
+	Phrase bool
+}
+
+// Field matches a card property, such as label:bug or due<7d
+type Field struct {
+	Name  string
+	Key   string // Custom field name, for field.<name> terms
+	Op    string
+	Value string
+}
+
+func (n *And) sql(ctx Context) (string, []interface{}) {
+	return join(ctx, "AND", n.Left, n.Right)
+}
+
+func (n *Or) sql(ctx Context) (string, []interface{}) {
+	return join(ctx, "OR", n.Left, n.Right)
+}
+
+// A condition on a NULL column, such as due<7d on a card without a due date,
+// is unknown rather than false. The negation treats it as not matching, so
+// -due<7d includes cards with no due date.
+func (n *Not) sql(ctx Context) (string, []interface{}) {
+	condition, args := n.Operand.sql(ctx)
+	return "NOT COALESCE((" + condition + "), FALSE)", args
+}
+
+func join(ctx Context, operator string, left, right Node) (string, []interface{}) {
+	leftSQL, leftArgs := left.sql(ctx)
+	rightSQL, rightArgs := right.sql(ctx)
+	return "(" + leftSQL + " " + operator + " " + rightSQL + ")", append(leftArgs, rightArgs...)
+}
+
+func (n *Text) sql(ctx Context) (string, []interface{}) {
+	tsquery := "plainto_tsquery('english', ?)"
+	if n.Phrase {
+		tsquery = "phraseto_tsquery('english', ?)"
+	}
+
+	condition := "(cards.search_vector @@ " + tsquery +
+		" OR EXISTS (SELECT 1 FROM comments WHERE comments.card_id = cards.id AND comments.deleted_at IS NULL AND comments.search_vector @@ " + tsquery + ")" +
+		" OR EXISTS (SELECT 1 FROM attachments WHERE attachments.card_id = cards.id AND attachments.deleted_at IS NULL AND attachments.search_vector @@ " + tsquery + "))"
+	return condition, []interface{}{n.Value, n.Value, n.Value}
+}
+
+// fields lists the supported field names and the operators each accepts
+var fields = map[string][]string{
+	"label":   {":", "="},
+	"member":  {":", "="},
+	"list":    {":", "="},
+	"board":   {":", "="},
+	"has":     {":"},
+	"due":     {":", "=", "<", "<=", ">", ">="},
+	"created": {":", "=", "<", "<=", ">", ">="},
+	"updated": {":", "=", "<", "<=", ">", ">="},
+
+	customFieldName: {":", "=", "<", "<=", ">", ">="},
+}
+
+// Custom fields are written field.<name>, and parsed into a Field named
+// customFieldName with the custom field's name as Key
+const (
+	customFieldPrefix = "field."
+	customFieldName   = "field"
+)
+
+// hasValues lists what has: can test for
+var hasValues = map[string]string{
+	"due":         "cards.due_date IS NOT NULL",
+	"description": "cards.description <> ''",
+	"label":       "EXISTS (SELECT 1 FROM card_labels WHERE card_labels.card_id = cards.id)",
+	"member":      "EXISTS (SELECT 1 FROM card_members WHERE card_members.card_id = cards.id)",
+	"comment":     "EXISTS (SELECT 1 FROM comments WHERE comments.card_id = cards.id AND comments.deleted_at IS NULL)",
+	"attachment":  "EXISTS (SELECT 1 FROM attachments WHERE attachments.card_id = cards.id AND attachments.deleted_at IS NULL)",
+}
+
+// dateColumns maps date fields to their card columns
+var dateColumns = map[string]string{
+	"due":     "cards.due_date",
+	"created": "cards.created_at",
+	"updated": "cards.updated_at",
+}
+
+// validate checks the operator and value so that compiling cannot fail
+func (f *Field) validate() error {
+	allowed := false
+	for _, op := range fields[f.Name] {
+		allowed = allowed || op == f.Op
+	}
+	if !allowed {
+		return fmt.Errorf("%s does not support %q", f.Name, f.Op)
+	}
+
+	switch {
+	case f.Name == customFieldName:
+		if strings.EqualFold(f.Value, "none") {
+			if !f.isEqual() {
+				return fmt.Errorf("%s%s:none does not support %q", customFieldPrefix, f.Key, f.Op)
+			}
+			return nil
+		}
+		if !f.isEqual() && !isNumber(f.Value) {
+			if _, _, err := dateRange(f.Value, time.Now()); err != nil {
+				return fmt.Errorf("%s%s %s needs a number or a date", customFieldPrefix, f.Key, f.Op)
+			}
+		}
+	case f.Name == "has":
+		if _, ok := hasValues[strings.ToLower(f.Value)]; !ok {
+			return fmt.Errorf("unknown value for has: %q", f.Value)
+		}
+	case f.Name == "due" && f.isEqual() && (strings.EqualFold(f.Value, "none") || strings.EqualFold(f.Value, "overdue")):
+		return nil
+	case dateColumns[f.Name] != "":
+		if _, _, err := dateRange(f.Value, time.Now()); err != nil {
+			return fmt.Errorf("%s: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func (f *Field) isEqual() bool {
+	return f.Op == ":" || f.Op == "="
+}
+
+func (f *Field) sql(ctx Context) (string, []interface{}) {
+	switch f.Name {
+	case "label":
+		return "EXISTS (SELECT 1 FROM card_labels JOIN labels ON labels.id = card_labels.label_id " +
+			"WHERE card_labels.card_id = cards.id AND labels.deleted_at IS NULL AND LOWER(labels.name) = LOWER(?))", []interface{}{f.Value}
+
+	case "member":
+		if strings.EqualFold(f.Value, "me") {
+			return "EXISTS (SELECT 1 FROM card_members WHERE card_members.card_id = cards.id AND card_members.user_id = ?)", []interface{}{ctx.UserID}
+		}
+		return "EXISTS (SELECT 1 FROM card_members JOIN users ON users.id = card_members.user_id " +
+			"WHERE card_members.card_id = cards.id AND LOWER(users.username) = LOWER(?))", []interface{}{f.Value}
+
+	case "list":
+		return "LOWER(lists.title) = LOWER(?)", []interface{}{f.Value}
+
+	case "board":
+		return "lists.board_id IN (SELECT boards.id FROM boards WHERE boards.deleted_at IS NULL AND LOWER(boards.title) = LOWER(?))", []interface{}{f.Value}
+
+	case "has":
+		return hasValues[strings.ToLower(f.Value)], nil
+
+	case customFieldName:
+		return f.customFieldSQL(ctx)
+	}
+
+	column := dateColumns[f.Name]
+	if f.Name == "due" && f.isEqual() {
+		switch strings.ToLower(f.Value) {
+		case "none":
+			return "cards.due_date IS NULL", nil
+		case "overdue":
+			return "cards.due_date < ?", []interface{}{ctx.Now}
+		}
+	}
+
+	// Validated by Parse, so the error cannot occur here
+	start, end, _ := dateRange(f.Value, ctx.Now)
+	return dateCondition(column, f.Op, start, end)
+}
+
+// dateCondition compares a date column with the range [start, end)
+func dateCondition(column, op string, start, end time.Time) (string, []interface{}) {
+	switch op {
+	case "<":
+		return column + " < ?", []interface{}{start}
+	case "<=":
+		return column + " < ?", []interface{}{end}
+	case ">":
+		return column + " >= ?", []interface{}{end}
+	case ">=":
+		return column + " >= ?", []interface{}{start}
+	default:
+		return "(" + column + " >= ? AND " + column + " < ?)", []interface{}{start, end}
+	}
+}
+
+// customFieldValue matches a card's value for the custom field named by the
+// first argument, in the board the card is on
+const customFieldValue = "EXISTS (SELECT 1 FROM card_field_values JOIN custom_fields ON custom_fields.id = card_field_values.field_id " +
+	"WHERE card_field_values.card_id = cards.id AND custom_fields.board_id = lists.board_id AND LOWER(custom_fields.name) = LOWER(?)"
+
+// customFieldSQL matches a custom field by value. Fields of any type may
+// share a name across boards, so the value is compared with every type it
+// can be read as.
+func (f *Field) customFieldSQL(ctx Context) (string, []interface{}) {
+	if strings.EqualFold(f.Value, "none") {
+		return "NOT " + customFieldValue + ")", []interface{}{f.Key}
+	}
+
+	var conditions []string
+	args := []interface{}{f.Key}
+
+	if f.isEqual() {
+		conditions = append(conditions,
+			"(custom_fields.type = 'text' AND LOWER(card_field_values.text_value) = LOWER(?))",
+			"(custom_fields.type IN ('select', 'multi_select') AND EXISTS "+
+				"(SELECT 1 FROM jsonb_array_elements_text(card_field_values.options) AS opt(value) WHERE LOWER(opt.value) = LOWER(?)))",
+		)
+		args = append(args, f.Value, f.Value)
+
+		if checked, err := strconv.ParseBool(f.Value); err == nil {
+			conditions = append(conditions, "(custom_fields.type = 'checkbox' AND card_field_values.checked = ?)")
+			args = append(args, checked)
+		}
+	}
+
+	if number, err := strconv.ParseFloat(f.Value, 64); err == nil {
+		operator := f.Op
+		if f.isEqual() {
+			operator = "="
+		}
+		conditions = append(conditions, "(custom_fields.type = 'number' AND card_field_values.number_value "+operator+" ?)")
+		args = append(args, number)
+	}
+
+	if start, end, err := dateRange(f.Value, ctx.Now); err == nil {
+		condition, dateArgs := dateCondition("card_field_values.date_value", f.Op, start, end)
+		conditions = append(conditions, "(custom_fields.type = 'date' AND "+condition+")")
+		args = append(args, dateArgs...)
+	}
+
+	return customFieldValue + " AND (" + strings.Join(conditions, " OR ") + "))", args
+}
+
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
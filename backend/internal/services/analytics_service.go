@@ -0,0 +1,164 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// finishedCardsSQL selects a board's cards that sit in a done-list, with
+// when they were created, first moved and last entered a done-list from a
+// list that is not done. Lead time runs from creation to done and cycle time
+// from the first move to done. Archived cards count; trashed ones do not.
+// Bind: board ID.
+const finishedCardsSQL = `
+	SELECT f.*,
+		EXTRACT(EPOCH FROM f.done_at - f.created_at)::bigint AS lead_seconds,
+		EXTRACT(EPOCH FROM f.done_at - LEAST(COALESCE(f.started_at, f.created_at), f.done_at))::bigint AS cycle_seconds
+	FROM (
+		SELECT c.id AS card_id, c.title, c.list_id, c.created_at, started.at AS started_at, finished.at AS done_at
+		FROM cards c
+		JOIN lists cl ON cl.id = c.list_id AND cl.is_done AND cl.deleted_at IS NULL
+		JOIN LATERAL (
+			SELECT MAX(t.moved_at) AS at
+			FROM card_transitions t
+			JOIN lists tl ON tl.id = t.to_list_id
+			LEFT JOIN lists fl ON fl.id = t.from_list_id
+			WHERE t.card_id = c.id AND tl.is_done AND (fl.id IS NULL OR NOT fl.is_done)
+		) finished ON finished.at IS NOT NULL
+		LEFT JOIN LATERAL (
+			SELECT MIN(t.moved_at) AS at
+			FROM card_transitions t
+			WHERE t.card_id = c.id AND t.from_list_id IS NOT NULL
+		) started ON true
+		WHERE cl.board_id = ? AND c.deleted_at IS NULL
+	) f`
+
+// flowStatsSQL aggregates lead and cycle times over finished cards aliased f
+const flowStatsSQL = `COUNT(*) AS cards,
+	COALESCE(AVG(f.lead_seconds), 0)::bigint AS avg_lead_seconds,
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY f.lead_seconds), 0)::bigint AS p50_lead_seconds,
+	COALESCE(percentile_cont(0.85) WITHIN GROUP (ORDER BY f.lead_seconds), 0)::bigint AS p85_lead_seconds,
+	COALESCE(AVG(f.cycle_seconds), 0)::bigint AS avg_cycle_seconds,
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY f.cycle_seconds), 0)::bigint AS p50_cycle_seconds,
+	COALESCE(percentile_cont(0.85) WITHIN GROUP (ORDER BY f.cycle_seconds), 0)::bigint AS p85_cycle_seconds`
+
+// CardFlowTime is the lead and cycle time of one finished card
+type CardFlowTime struct {
+	CardID       uint       `json:"card_id"`
+	Title        string     `json:"title"`
+	ListID       uint       `json:"list_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at"` // First move; nil if it never left its first list
+	DoneAt       time.Time  `json:"done_at"`
+	LeadSeconds  int64      `json:"lead_seconds"`
+	CycleSeconds int64      `json:"cycle_seconds"`
+}
+
+// FlowStats summarises the lead and cycle times of a set of finished cards
+type FlowStats struct {
+	Cards           int   `json:"cards"`
+	AvgLeadSeconds  int64 `json:"avg_lead_seconds"`
+	P50LeadSeconds  int64 `json:"p50_lead_seconds"`
+	P85LeadSeconds  int64 `json:"p85_lead_seconds"`
+	AvgCycleSeconds int64 `json:"avg_cycle_seconds"`
+	P50CycleSeconds int64 `json:"p50_cycle_seconds"`
+	P85CycleSeconds int64 `json:"p85_cycle_seconds"`
+}
+
+// LabelFlowStats is FlowStats for the finished cards with one label
+type LabelFlowStats struct {
+	LabelID uint   `json:"label_id"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	FlowStats
+}
+
+// ThroughputWeek is how many cards were finished in a week starting Monday
+type ThroughputWeek struct {
+	WeekStart time.Time
+	Cards     int
+}
+
+// FlowCount is how many cards were in a list at the end of a day
+type FlowCount struct {
+	Day    time.Time
+	ListID uint
+	Cards  int
+}
+
+// AnalyticsService computes flow metrics from card transitions. Date ranges
+// are [from, to) in UTC.
+type AnalyticsService struct{}
+
+// CardFlowTimes returns the lead and cycle time of each card finished in the
+// range, most recently finished first
+func (as *AnalyticsService) CardFlowTimes(db *gorm.DB, boardID uint, from, to time.Time) ([]CardFlowTime, error) {
+	var rows []CardFlowTime
+	err := db.Raw(finishedCardsSQL+`
+		WHERE f.done_at >= ? AND f.done_at < ?
+		ORDER BY f.done_at DESC, f.card_id DESC`, boardID, from, to).Scan(&rows).Error
+	return rows, err
+}
+
+// FlowSummary summarises the cards finished in the range
+func (as *AnalyticsService) FlowSummary(db *gorm.DB, boardID uint, from, to time.Time) (*FlowStats, error) {
+	var stats FlowStats
+	err := db.Raw("SELECT "+flowStatsSQL+" FROM ("+finishedCardsSQL+`) f
+		WHERE f.done_at >= ? AND f.done_at < ?`, boardID, from, to).Scan(&stats).Error
+	return &stats, err
+}
+
+// LabelFlowStats summarises the cards finished in the range per label. A card
+// with several labels counts towards each.
+func (as *AnalyticsService) LabelFlowStats(db *gorm.DB, boardID uint, from, to time.Time) ([]LabelFlowStats, error) {
+	var rows []LabelFlowStats
+	err := db.Raw("SELECT l.id AS label_id, l.name, l.color, "+flowStatsSQL+" FROM ("+finishedCardsSQL+`) f
+		JOIN card_labels ON card_labels.card_id = f.card_id
+		JOIN labels l ON l.id = card_labels.label_id AND l.deleted_at IS NULL
+		WHERE f.done_at >= ? AND f.done_at < ?
+		GROUP BY l.id, l.name, l.color
+		ORDER BY l.name ASC, l.id ASC`, boardID, from, to).Scan(&rows).Error
+	return rows, err
+}
+
+// Throughput counts the cards finished in each week that overlaps the range,
+// including weeks with none
+func (as *AnalyticsService) Throughput(db *gorm.DB, boardID uint, from, to time.Time) ([]ThroughputWeek, error) {
+	var rows []ThroughputWeek
+	err := db.Raw(`SELECT weeks.week AS week_start, COUNT(f.card_id) AS cards
+		FROM generate_series(date_trunc('week', CAST(? AS date)::timestamp), CAST(? AS date)::timestamp - interval '1 day', interval '1 week') AS weeks(week)
+		LEFT JOIN (`+finishedCardsSQL+`) f
+			ON f.done_at >= weeks.week AT TIME ZONE 'UTC' AND f.done_at < (weeks.week + interval '1 week') AT TIME ZONE 'UTC'
+		GROUP BY weeks.week
+		ORDER BY weeks.week ASC`, dateParam(from), dateParam(to), boardID).Scan(&rows).Error
+	return rows, err
+}
+
+// CumulativeFlow counts, for each day of the range, the cards in each of the
+// board's lists at the end of that day. Cards count from their creation
+// until they leave the board or go to the trash; archived cards still count.
+// Lists with no cards on a day have no row for it.
+func (as *AnalyticsService) CumulativeFlow(db *gorm.DB, boardID uint, from, to time.Time) ([]FlowCount, error) {
+	var rows []FlowCount
+	err := db.Raw(`SELECT days.day, latest.to_list_id AS list_id, COUNT(*) AS cards
+		FROM generate_series(CAST(? AS date)::timestamp, CAST(? AS date)::timestamp - interval '1 day', interval '1 day') AS days(day)
+		JOIN LATERAL (
+			SELECT DISTINCT ON (t.card_id) t.card_id, t.to_list_id
+			FROM card_transitions t
+			WHERE t.card_id IN (SELECT card_id FROM card_transitions WHERE board_id = ?)
+				AND t.moved_at < (days.day + interval '1 day') AT TIME ZONE 'UTC'
+			ORDER BY t.card_id, t.moved_at DESC, t.id DESC
+		) latest ON true
+		JOIN lists l ON l.id = latest.to_list_id AND l.board_id = ?
+		JOIN cards c ON c.id = latest.card_id
+			AND (c.deleted_at IS NULL OR c.deleted_at >= (days.day + interval '1 day') AT TIME ZONE 'UTC')
+		GROUP BY days.day, latest.to_list_id
+		ORDER BY days.day ASC`, dateParam(from), dateParam(to), boardID, boardID).Scan(&rows).Error
+	return rows, err
+}
+
+// dateParam formats t as a UTC date for SQL that works in calendar days
+func dateParam(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/recurrence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Triggers an automation rule can listen for
+const (
+	TriggerCardCreated      = "card_created"
+	TriggerCardMoved        = "card_moved"
+	TriggerLabelAdded       = "label_added"
+	TriggerLabelRemoved     = "label_removed"
+	TriggerMemberAssigned   = "member_assigned"
+	TriggerMemberUnassigned = "member_unassigned"
+	TriggerCardLinked       = "card_linked"
+	TriggerSchedule         = "schedule"
+)
+
+// Statuses of an automation run
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	RunSkipped   = "skipped"
+)
+
+// MaxAutomationDepth is how many rules may run one after another, each
+// triggered by the last one's changes, before further runs are skipped
+const MaxAutomationDepth = 5
+
+// ErrInvalidAction is returned for an action that cannot run on a board
+var ErrInvalidAction = errors.New("invalid automation action")
+
+// AutomationTrigger is a board event as seen by automation rules. Zero IDs
+// are unknown.
+type AutomationTrigger struct {
+	Event      string // One of the Trigger constants
+	BoardID    uint
+	CardID     uint
+	ListID     uint // List the card is in after the event
+	FromListID uint // card_moved: list the card left
+	LabelID    uint
+	MemberID   uint
+	Chain      []uint // Rules whose actions caused the event, first rule first
+}
+
+// AutomationChange is one action that changed cards during a run
+type AutomationChange struct {
+	Op      BulkOperation
+	CardIDs []uint
+}
+
+// AutomationResult is one logged run of a rule
+type AutomationResult struct {
+	Rule    models.AutomationRule
+	Run     models.AutomationRun
+	Changes []AutomationChange
+	Chain   []uint // Chain to attach to events caused by the changes
+}
+
+// AutomationService matches board events to automation rules and runs their
+// actions through the same card operations as bulk updates
+type AutomationService struct{}
+
+// Matches reports whether trigger fires rule
+func (as *AutomationService) Matches(rule *models.AutomationRule, trigger *AutomationTrigger) bool {
+	if !rule.Enabled || rule.Trigger != trigger.Event || rule.BoardID != trigger.BoardID {
+		return false
+	}
+	filter := rule.Filter
+	return matchID(filter.ListID, trigger.ListID) &&
+		matchID(filter.FromListID, trigger.FromListID) &&
+		matchID(filter.LabelID, trigger.LabelID) &&
+		matchID(filter.MemberID, trigger.MemberID)
+}
+
+func matchID(want, got uint) bool {
+	return want == 0 || want == got
+}
+
+// SkipReason returns why loop protection stops rule from running for an event
+// caused by chain, or "" if it may run. A rule runs at most once per chain
+// and chains stop after MaxAutomationDepth rules.
+func (as *AutomationService) SkipReason(rule *models.AutomationRule, chain []uint) string {
+	if len(chain) >= MaxAutomationDepth {
+		return fmt.Sprintf("loop protection: %d rules already ran in this chain", len(chain))
+	}
+	for _, id := range chain {
+		if id == rule.ID {
+			return "loop protection: rule already ran in this chain"
+		}
+	}
+	return ""
+}
+
+// ValidateAction checks that action can run on cards of boardID
+func (as *AutomationService) ValidateAction(db *gorm.DB, boardID uint, action *models.AutomationAction) error {
+	switch action.Type {
+	case BulkMove:
+		var list models.List
+		if action.ListID == 0 || db.Where("id = ? AND board_id = ?", action.ListID, boardID).First(&list).Error != nil {
+			return fmt.Errorf("%w: list not found on this board", ErrInvalidAction)
+		}
+	case BulkAddLabel, BulkRemoveLabel:
+		var label models.Label
+		if action.LabelID == 0 || db.Where("id = ? AND board_id = ?", action.LabelID, boardID).First(&label).Error != nil {
+			return fmt.Errorf("%w: label not found on this board", ErrInvalidAction)
+		}
+	case BulkAssign:
+		permService := &PermissionService{}
+		if action.MemberID == 0 || !permService.HasBoardAccess(action.MemberID, boardID) {
+			return fmt.Errorf("%w: user is not a member of this board", ErrInvalidAction)
+		}
+	case BulkUnassign, BulkSetDueDate, BulkCompleteDue, BulkArchive, BulkUnarchive, BulkDelete:
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidAction, action.Type)
+	}
+	return nil
+}
+
+// ScheduleNext sets a schedule rule's NextRunAt to its first occurrence after from
+func (as *AutomationService) ScheduleNext(rule *models.AutomationRule, from time.Time) error {
+	location, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		return fmt.Errorf("unknown timezone %q", rule.Timezone)
+	}
+	schedule := &recurrence.Rule{Frequency: recurrence.Cron, Cron: rule.Cron, Location: location}
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	next := schedule.Next(from, from)
+	if next.IsZero() {
+		return ErrNoOccurrence
+	}
+	next = next.UTC()
+	rule.NextRunAt = &next
+	return nil
+}
+
+// HandleTrigger runs every rule the trigger fires, each in its own
+// transaction, and logs a run for each. Runs stopped by loop protection are
+// logged as skipped.
+func (as *AutomationService) HandleTrigger(db *gorm.DB, trigger *AutomationTrigger, now time.Time) ([]AutomationResult, error) {
+	rules, err := as.matchingRules(db, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []AutomationResult
+	for _, rule := range rules {
+		result := AutomationResult{
+			Rule:  rule,
+			Chain: append(append([]uint{}, trigger.Chain...), rule.ID),
+			Run: models.AutomationRun{
+				RuleID:  rule.ID,
+				BoardID: rule.BoardID,
+				CardID:  &trigger.CardID,
+				Event:   trigger.Event,
+				Depth:   len(trigger.Chain),
+			},
+		}
+
+		if reason := as.SkipReason(&rule, trigger.Chain); reason != "" {
+			result.Run.Status, result.Run.Error = RunSkipped, reason
+		} else {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				var err error
+				result.Changes, err = as.apply(tx, &rule, []uint{trigger.CardID}, now)
+				return err
+			})
+			as.finishRun(&result, err)
+		}
+
+		if err := db.Create(&result.Run).Error; err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// RecordDropped logs a failed run, with reason as its error, for every rule
+// a trigger that could not be handled would have fired, so the board's
+// admins see it in the run log
+func (as *AutomationService) RecordDropped(db *gorm.DB, trigger *AutomationTrigger, reason string) error {
+	rules, err := as.matchingRules(db, trigger)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		run := models.AutomationRun{
+			RuleID:  rule.ID,
+			BoardID: rule.BoardID,
+			CardID:  &trigger.CardID,
+			Event:   trigger.Event,
+			Depth:   len(trigger.Chain),
+			Status:  RunFailed,
+			Error:   reason,
+		}
+		if err := db.Create(&run).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchingRules returns the rules trigger fires, oldest first
+func (as *AutomationService) matchingRules(db *gorm.DB, trigger *AutomationTrigger) ([]models.AutomationRule, error) {
+	var rules []models.AutomationRule
+	if err := db.Where("board_id = ? AND trigger = ? AND enabled = ?", trigger.BoardID, trigger.Event, true).
+		Order("id ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	matching := rules[:0]
+	for _, rule := range rules {
+		if as.Matches(&rule, trigger) {
+			matching = append(matching, rule)
+		}
+	}
+	return matching, nil
+}
+
+// RunScheduled runs the schedule rules due at now, up to limit, on the
+// unarchived cards of their list. Each rule is claimed with FOR UPDATE SKIP
+// LOCKED and moved to its next occurrence in the same transaction, so missed
+// occurrences collapse into one run and no occurrence runs twice.
+func (as *AutomationService) RunScheduled(db *gorm.DB, now time.Time, limit int) ([]AutomationResult, error) {
+	var results []AutomationResult
+	for len(results) < limit {
+		var result *AutomationResult
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var rule models.AutomationRule
+			found := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("trigger = ? AND enabled = ? AND next_run_at <= ?", TriggerSchedule, true, now).
+				Order("next_run_at ASC").
+				Limit(1).
+				Find(&rule)
+			if found.Error != nil || found.RowsAffected == 0 {
+				return found.Error
+			}
+
+			var err error
+			result, err = as.runSchedule(tx, &rule, now)
+			return err
+		})
+		if err != nil {
+			return results, err
+		}
+		if result == nil {
+			break
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// runSchedule runs a claimed schedule rule. Failed actions are rolled back
+// to a savepoint so the rule still moves on to its next occurrence. A rule
+// with no further occurrence is left without a next run.
+func (as *AutomationService) runSchedule(tx *gorm.DB, rule *models.AutomationRule, now time.Time) (*AutomationResult, error) {
+	updates := map[string]interface{}{"last_run_at": now, "next_run_at": nil}
+	if err := as.ScheduleNext(rule, now); err == nil {
+		updates["next_run_at"] = rule.NextRunAt
+	}
+	if err := tx.Model(rule).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	result := &AutomationResult{
+		Rule:  *rule,
+		Chain: []uint{rule.ID},
+		Run: models.AutomationRun{
+			RuleID:  rule.ID,
+			BoardID: rule.BoardID,
+			Event:   TriggerSchedule,
+		},
+	}
+
+	err := tx.Transaction(func(inner *gorm.DB) error {
+		var cardIDs []uint
+		if err := inner.Model(&models.Card{}).
+			Where("list_id = ? AND archived_at IS NULL", rule.Filter.ListID).
+			Pluck("id", &cardIDs).Error; err != nil {
+			return err
+		}
+
+		var err error
+		result.Changes, err = as.apply(inner, rule, cardIDs, now)
+		return err
+	})
+	as.finishRun(result, err)
+
+	return result, tx.Create(&result.Run).Error
+}
+
+// finishRun sets a run's outcome from its changes and error
+func (as *AutomationService) finishRun(result *AutomationResult, err error) {
+	if err != nil {
+		result.Run.Status, result.Run.Error = RunFailed, err.Error()
+		result.Changes = nil
+		return
+	}
+
+	changed := make(map[uint]bool)
+	for _, change := range result.Changes {
+		for _, id := range change.CardIDs {
+			changed[id] = true
+		}
+	}
+	result.Run.Status, result.Run.Changed = RunSucceeded, len(changed)
+}
+
+// apply runs rule's actions in order on the cards of cardIDs still on the
+// rule's board, in board order, and returns what changed
+func (as *AutomationService) apply(tx *gorm.DB, rule *models.AutomationRule, cardIDs []uint, now time.Time) ([]AutomationChange, error) {
+	if len(cardIDs) == 0 {
+		return nil, nil
+	}
+
+	var cards []models.Card
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "cards"}}).
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.board_id = ? AND lists.deleted_at IS NULL", rule.BoardID).
+		Where("cards.id IN ?", cardIDs).
+		Order("cards.rank ASC, cards.id ASC").
+		Find(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	cardService := &CardService{}
+	var changes []AutomationChange
+	for _, action := range rule.Actions {
+		if err := as.ValidateAction(tx, rule.BoardID, &action); err != nil {
+			return nil, err
+		}
+
+		op := BulkOperation{
+			Type:     action.Type,
+			ListID:   action.ListID,
+			Position: action.Position,
+			LabelID:  action.LabelID,
+			MemberID: action.MemberID,
+			UserID:   rule.CreatedBy,
+		}
+		if action.DueInDays != nil {
+			due := now.AddDate(0, 0, *action.DueInDays)
+			op.DueDate = &due
+		}
+
+		change := AutomationChange{Op: op}
+		for i := range cards {
+			card := &cards[i]
+			if card.DeletedAt.Valid {
+				continue
+			}
+
+			updated, err := as.applyAction(tx, cardService, card, op, len(change.CardIDs))
+			if err != nil {
+				return nil, err
+			}
+			if updated {
+				change.CardIDs = append(change.CardIDs, card.ID)
+			}
+			if op.Type == BulkDelete {
+				card.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+			}
+		}
+		if len(change.CardIDs) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// applyAction applies one operation to a card. Unassigning without a member
+// removes every member of the card.
+func (as *AutomationService) applyAction(tx *gorm.DB, cardService *CardService, card *models.Card, op BulkOperation, index int) (bool, error) {
+	if op.Type != BulkUnassign || op.MemberID != 0 {
+		return cardService.ApplyBulk(tx, card, op, index)
+	}
+
+	var memberIDs []uint
+	if err := tx.Model(&models.CardMember{}).Where("card_id = ?", card.ID).Pluck("user_id", &memberIDs).Error; err != nil {
+		return false, err
+	}
+	for _, memberID := range memberIDs {
+		op.MemberID = memberID
+		if _, err := cardService.ApplyBulk(tx, card, op, index); err != nil {
+			return false, err
+		}
+	}
+	return len(memberIDs) > 0, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// calendarHistory is how far back calendar feeds list past due dates
+const calendarHistory = 90 * 24 * time.Hour
+
+// calendarEventLimit bounds the events in one feed
+const calendarEventLimit = 1000
+
+// Calendar periods cards can be grouped by
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// CalendarService selects the cards shown in calendar feeds and a board's
+// calendar and timeline views. Date ranges are [from, to) in UTC.
+type CalendarService struct{}
+
+// FeedCards returns the cards with a due date in a calendar feed, soonest
+// first. A board feed lists the board's cards as long as the feed's owner
+// may still view it; a personal feed lists the cards assigned to its owner
+// on boards they are still an active member of. Archived and trashed cards
+// and due dates older than the history window are left out.
+func (cs *CalendarService) FeedCards(db *gorm.DB, feed *models.CalendarFeed, now time.Time) ([]models.Card, error) {
+	query := db.
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Joins("JOIN boards ON boards.id = lists.board_id AND boards.deleted_at IS NULL")
+
+	if feed.BoardID != nil {
+		permissionService := &PermissionService{}
+		if !permissionService.CheckPermission(feed.UserID, *feed.BoardID, "view_board") {
+			return nil, nil
+		}
+		query = query.Where("boards.id = ?", *feed.BoardID)
+	} else {
+		query = MemberBoards(query.
+			Joins("JOIN card_members ON card_members.card_id = cards.id AND card_members.user_id = ?", feed.UserID), feed.UserID)
+	}
+
+	var cards []models.Card
+	err := query.
+		Where("cards.due_date >= ? AND cards.archived_at IS NULL", now.Add(-calendarHistory)).
+		Preload("List.Board").
+		Preload("Labels").
+		Order("cards.due_date ASC, cards.id ASC").
+		Limit(calendarEventLimit).
+		Find(&cards).Error
+	return cards, err
+}
+
+// DueCards returns a board's unarchived cards due in the range, soonest first
+func (cs *CalendarService) DueCards(db *gorm.DB, boardID uint, from, to time.Time) ([]models.Card, error) {
+	var cards []models.Card
+	err := db.Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.board_id = ? AND cards.archived_at IS NULL", boardID).
+		Where("cards.due_date >= ? AND cards.due_date < ?", from, to).
+		Order("cards.due_date ASC, cards.id ASC").
+		Find(&cards).Error
+	return cards, err
+}
+
+// TimelineCards returns a board's unarchived cards whose span overlaps the
+// range, earliest first. A card spans from its start date to its due date;
+// with only one of them it spans that instant.
+func (cs *CalendarService) TimelineCards(db *gorm.DB, boardID uint, from, to time.Time) ([]models.Card, error) {
+	var cards []models.Card
+	err := db.Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.board_id = ? AND cards.archived_at IS NULL", boardID).
+		Where("COALESCE(cards.start_date, cards.due_date) < ? AND COALESCE(cards.due_date, cards.start_date) >= ?", to, from).
+		Order("COALESCE(cards.start_date, cards.due_date) ASC, cards.id ASC").
+		Find(&cards).Error
+	return cards, err
+}
+
+// PeriodStart returns the first day of the day, week (Monday to Sunday) or
+// month containing day, at midnight UTC
+func (cs *CalendarService) PeriodStart(day time.Time, period string) time.Time {
+	day = day.UTC().Truncate(24 * time.Hour)
+	switch period {
+	case PeriodWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case PeriodMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// NextPeriod returns the start of the period after the one starting at start
+func (cs *CalendarService) NextPeriod(start time.Time, period string) time.Time {
+	switch period {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
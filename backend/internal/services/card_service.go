@@ -0,0 +1,360 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleCard is returned when a client acts on an outdated copy of a card
+var ErrStaleCard = errors.New("card has changed since it was loaded")
+
+// MoveCardInput describes where a card goes and, optionally, where the client
+// believes it currently is. Any mismatch is treated as a stale move.
+type MoveCardInput struct {
+	ListID       uint
+	Position     int
+	Version      *int
+	FromListID   *uint
+	FromPosition *int
+	LaneID       *uint // nil keeps the card's lane, 0 moves it to the default lane
+	UserID       uint  // Acting user, recorded on the transition; 0 for the system
+}
+
+// CardService holds card operations that must run atomically
+type CardService struct{}
+
+// LockCard loads a card with a row lock. Writes that lock both a card and
+// lists lock the card first, so an edit and a move of the same card cannot
+// deadlock.
+func (cs *CardService) LockCard(tx *gorm.DB, cardID uint) (*models.Card, error) {
+	var card models.Card
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&card, cardID).Error; err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// CreateCard adds card to the end of its list inside tx and records it
+// entering the list. The list is locked so concurrent creates do not get the
+// same rank or slip past its WIP limit together. It returns the list's WIP
+// check, with ErrWIPLimit if a hard limit refuses the card. A card that
+// conflicts with an existing one, such as an occurrence that was already
+// copied, is not created and keeps a zero ID.
+func (cs *CardService) CreateCard(tx *gorm.DB, card *models.Card, userID uint) (*WIPCheck, error) {
+	if err := LockLists(tx, card.ListID); err != nil {
+		return nil, err
+	}
+
+	ordering := &OrderingService{}
+	rank, err := ordering.NextCardRank(tx, card.ListID)
+	if err != nil {
+		return nil, err
+	}
+	card.Rank = rank
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(card)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		card.ID = 0
+		return nil, nil
+	}
+	if err := RecordTransition(tx, card.ID, nil, card.ListID, userID); err != nil {
+		return nil, err
+	}
+	return cs.checkWIP(tx, card.ListID)
+}
+
+// MoveCard moves a card inside tx. The card and the lists involved are locked
+// so concurrent moves into the same list are serialised, and only the moved
+// row is written. A card entering another list returns that list's WIP
+// check, with ErrWIPLimit if a hard limit refuses it.
+func (cs *CardService) MoveCard(tx *gorm.DB, cardID uint, input MoveCardInput) (*models.Card, *WIPCheck, error) {
+	locked, err := cs.LockCard(tx, cardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	card := *locked
+
+	if input.Version != nil && *input.Version != card.Version {
+		return &card, nil, ErrStaleCard
+	}
+
+	// Lock source and destination lists in id order to avoid deadlocks
+	if err := LockLists(tx, card.ListID, input.ListID); err != nil {
+		return nil, nil, err
+	}
+
+	ordering := &OrderingService{}
+	if input.FromListID != nil && *input.FromListID != card.ListID {
+		return &card, nil, ErrStaleCard
+	}
+	if input.FromPosition != nil {
+		index, err := ordering.CardIndex(tx, &card)
+		if err != nil {
+			return nil, nil, err
+		}
+		if index != *input.FromPosition {
+			return &card, nil, ErrStaleCard
+		}
+	}
+
+	rank, err := ordering.CardRankAt(tx, input.ListID, input.Position, card.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := map[string]interface{}{
+		"list_id": input.ListID,
+		"rank":    rank,
+		"version": gorm.Expr("version + 1"),
+	}
+	if input.LaneID != nil {
+		card.LaneID = nil
+		if *input.LaneID != 0 {
+			card.LaneID = input.LaneID
+		}
+		columns["lane_id"] = card.LaneID
+	}
+	if err := tx.Model(&card).UpdateColumns(columns).Error; err != nil {
+		return nil, nil, err
+	}
+	var wip *WIPCheck
+	if input.ListID != card.ListID {
+		fromListID := card.ListID
+		if err := RecordTransition(tx, card.ID, &fromListID, input.ListID, input.UserID); err != nil {
+			return nil, nil, err
+		}
+		revisionService := &RevisionService{}
+		if err := revisionService.Record(tx, card.ID, input.UserID, []models.FieldChange{
+			{Field: RevisionList, From: fromListID, To: input.ListID},
+		}); err != nil {
+			return nil, nil, err
+		}
+		if wip, err = cs.checkWIP(tx, input.ListID); err != nil {
+			return nil, wip, err
+		}
+	}
+
+	card.ListID = input.ListID
+	card.Rank = rank
+	card.Version++
+	return &card, wip, nil
+}
+
+// RestoreCard takes a card out of the trash inside tx, at its old place in
+// its list. Siblings keep their keys, so the old rank still sorts between the
+// same neighbours unless a newer card was given the same key. It returns the
+// list's WIP check, with ErrWIPLimit if a hard limit refuses the card.
+func (cs *CardService) RestoreCard(tx *gorm.DB, card *models.Card) (*WIPCheck, error) {
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Card{}, card.ID).Error; err != nil {
+		return nil, err
+	}
+	if err := LockLists(tx, card.ListID); err != nil {
+		return nil, err
+	}
+
+	var taken int64
+	if err := tx.Model(&models.Card{}).Where("list_id = ? AND rank = ?", card.ListID, card.Rank).Count(&taken).Error; err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		var before int64
+		if err := tx.Model(&models.Card{}).Where("list_id = ? AND rank <= ?", card.ListID, card.Rank).Count(&before).Error; err != nil {
+			return nil, err
+		}
+
+		ordering := &OrderingService{}
+		rank, err := ordering.CardRankAt(tx, card.ListID, int(before), card.ID)
+		if err != nil {
+			return nil, err
+		}
+		card.Rank = rank
+	}
+
+	if err := tx.Unscoped().Model(card).UpdateColumns(map[string]interface{}{
+		"rank":       card.Rank,
+		"deleted_at": nil,
+		"deleted_by": nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return cs.checkWIP(tx, card.ListID)
+}
+
+// checkWIP measures a list against its WIP limit after a card entered it.
+// The list must already be locked.
+func (cs *CardService) checkWIP(tx *gorm.DB, listID uint) (*WIPCheck, error) {
+	var list models.List
+	if err := tx.First(&list, listID).Error; err != nil {
+		return nil, err
+	}
+	listService := &ListService{}
+	return listService.CheckWIP(tx, &list)
+}
+
+// RecordTransition records a card entering toListID now. fromListID is nil
+// when the card was just created.
+func RecordTransition(tx *gorm.DB, cardID uint, fromListID *uint, toListID, userID uint) error {
+	var user *uint
+	if userID != 0 {
+		user = &userID
+	}
+	return tx.Exec("INSERT INTO card_transitions (card_id, board_id, from_list_id, to_list_id, user_id, moved_at) "+
+		"SELECT ?, board_id, ?, id, ?, NOW() FROM lists WHERE id = ?", cardID, fromListID, user, toListID).Error
+}
+
+// LockLists takes row locks on the given lists, always in the same order
+func LockLists(tx *gorm.DB, listIDs ...uint) error {
+	var lists []models.List
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", listIDs).
+		Order("id ASC").
+		Find(&lists).Error
+}
+
+// Operations accepted by ApplyBulk
+const (
+	BulkMove        = "move"
+	BulkAddLabel    = "add_label"
+	BulkRemoveLabel = "remove_label"
+	BulkAssign      = "assign"
+	BulkUnassign    = "unassign"
+	BulkSetDueDate  = "set_due_date"
+	BulkCompleteDue = "complete_due"
+	BulkArchive     = "archive"
+	BulkUnarchive   = "unarchive"
+	BulkDelete      = "delete"
+)
+
+// BulkOperation is one change applied to every card of a bulk request.
+// Only the fields used by Type need to be set.
+type BulkOperation struct {
+	Type     string
+	ListID   uint
+	Position *int // nil appends moved cards to the end of the list
+	LabelID  uint
+	MemberID uint
+	DueDate  *time.Time
+	UserID   uint // Acting user, recorded on deleted cards
+}
+
+// ApplyBulk applies op to a single card inside tx and reports whether the card
+// changed. index is the card's place in the request so that moved cards keep
+// the order they were given in. Moving or unarchiving a card into a list at
+// its hard WIP limit returns ErrWIPLimit.
+func (cs *CardService) ApplyBulk(tx *gorm.DB, card *models.Card, op BulkOperation, index int) (bool, error) {
+	switch op.Type {
+	case BulkMove:
+		position := -1
+		if op.Position != nil {
+			position = *op.Position + index
+		}
+		moved, _, err := cs.MoveCard(tx, card.ID, MoveCardInput{ListID: op.ListID, Position: position, UserID: op.UserID})
+		if err != nil {
+			return false, err
+		}
+		card.ListID = moved.ListID
+		card.Rank = moved.Rank
+		card.Version = moved.Version
+		return true, nil
+
+	case BulkAddLabel:
+		return cs.changeSet(tx, card.ID, op.UserID, RevisionLabels, func() *gorm.DB {
+			return tx.Omit(clause.Associations).
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.CardLabel{CardID: card.ID, LabelID: op.LabelID})
+		})
+
+	case BulkRemoveLabel:
+		return cs.changeSet(tx, card.ID, op.UserID, RevisionLabels, func() *gorm.DB {
+			return tx.Where("card_id = ? AND label_id = ?", card.ID, op.LabelID).Delete(&models.CardLabel{})
+		})
+
+	case BulkAssign:
+		return cs.changeSet(tx, card.ID, op.UserID, RevisionMembers, func() *gorm.DB {
+			return tx.Omit(clause.Associations).
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.CardMember{CardID: card.ID, UserID: op.MemberID})
+		})
+
+	case BulkUnassign:
+		return cs.changeSet(tx, card.ID, op.UserID, RevisionMembers, func() *gorm.DB {
+			return tx.Where("card_id = ? AND user_id = ?", card.ID, op.MemberID).Delete(&models.CardMember{})
+		})
+
+	case BulkSetDueDate:
+		change := models.FieldChange{Field: RevisionDueDate, From: card.DueDate, To: op.DueDate}
+		card.DueDate = op.DueDate
+		if err := cs.updateColumns(tx, card, map[string]interface{}{"due_date": op.DueDate}); err != nil {
+			return false, err
+		}
+		revisionService := &RevisionService{}
+		return true, revisionService.Record(tx, card.ID, op.UserID, []models.FieldChange{change})
+
+	case BulkCompleteDue:
+		if card.DueComplete {
+			return false, nil
+		}
+		card.DueComplete = true
+		return true, cs.updateColumns(tx, card, map[string]interface{}{"due_complete": true})
+
+	case BulkArchive:
+		if card.ArchivedAt != nil {
+			return false, nil
+		}
+		now := time.Now()
+		card.ArchivedAt = &now
+		return true, cs.updateColumns(tx, card, map[string]interface{}{"archived_at": now})
+
+	case BulkUnarchive:
+		if card.ArchivedAt == nil {
+			return false, nil
+		}
+		// The card counts towards its list's WIP limit again
+		card.ArchivedAt = nil
+		if err := cs.updateColumns(tx, card, map[string]interface{}{"archived_at": nil}); err != nil {
+			return false, err
+		}
+		if err := LockLists(tx, card.ListID); err != nil {
+			return false, err
+		}
+		_, err := cs.checkWIP(tx, card.ListID)
+		return true, err
+
+	case BulkDelete:
+		trashService := &TrashService{}
+		return true, trashService.MoveToTrash(tx, card, op.UserID)
+	}
+
+	return false, fmt.Errorf("unknown bulk operation %q", op.Type)
+}
+
+// changeSet runs a statement adding or removing one of a card's labels or
+// members, recording the revision if it changed anything
+func (cs *CardService) changeSet(tx *gorm.DB, cardID, userID uint, field string, statement func() *gorm.DB) (bool, error) {
+	changed := false
+	revisionService := &RevisionService{}
+	err := revisionService.TrackSet(tx, cardID, userID, field, func() error {
+		result := statement()
+		changed = result.RowsAffected > 0
+		return result.Error
+	})
+	return changed, err
+}
+
+// updateColumns writes the given columns and bumps the card's version
+func (cs *CardService) updateColumns(tx *gorm.DB, card *models.Card, columns map[string]interface{}) error {
+	columns["version"] = gorm.Expr("version + 1")
+	if err := tx.Model(card).UpdateColumns(columns).Error; err != nil {
+		return err
+	}
+	card.Version++
+	return nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Custom field types
+const (
+	FieldText        = "text"
+	FieldNumber      = "number"
+	FieldDate        = "date"
+	FieldCheckbox    = "checkbox"
+	FieldSelect      = "select"
+	FieldMultiSelect = "multi_select"
+)
+
+// ErrInvalidFieldValue is returned when a value does not match the field's type
+var ErrInvalidFieldValue = errors.New("invalid field value")
+
+// CustomFieldService manages custom field values on cards
+type CustomFieldService struct{}
+
+// HasOptions reports whether a field type takes its values from a list of options
+func HasOptions(fieldType string) bool {
+	return fieldType == FieldSelect || fieldType == FieldMultiSelect
+}
+
+// SetValue stores a card's value for a field, replacing any previous value.
+// A JSON null clears the value, in which case nil is returned.
+func (s *CustomFieldService) SetValue(tx *gorm.DB, field *models.CustomField, cardID uint, raw json.RawMessage) (*models.CardFieldValue, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, tx.Where("card_id = ? AND field_id = ?", cardID, field.ID).Delete(&models.CardFieldValue{}).Error
+	}
+
+	value, err := s.ParseValue(field, raw)
+	if err != nil {
+		return nil, err
+	}
+	value.CardID = cardID
+	value.FieldID = field.ID
+
+	// Upsert on the card/field pair, clearing the columns of other types
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "card_id"}, {Name: "field_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text_value", "number_value", "date_value", "checked", "options", "updated_at"}),
+	}).Create(value).Error; err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// ParseValue converts a JSON value into a CardFieldValue for the field's type
+func (s *CustomFieldService) ParseValue(field *models.CustomField, raw json.RawMessage) (*models.CardFieldValue, error) {
+	invalid := func(expected string) error {
+		return fmt.Errorf("%w: %s expects %s", ErrInvalidFieldValue, field.Name, expected)
+	}
+	value := &models.CardFieldValue{}
+
+	switch field.Type {
+	case FieldText:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, invalid("a string")
+		}
+		if len(text) > 2000 {
+			return nil, invalid("at most 2000 characters")
+		}
+		value.TextValue = &text
+
+	case FieldNumber:
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return nil, invalid("a number")
+		}
+		value.NumberValue = &number
+
+	case FieldDate:
+		var date time.Time
+		if err := json.Unmarshal(raw, &date); err != nil {
+			return nil, invalid("an RFC 3339 date")
+		}
+		value.DateValue = &date
+
+	case FieldCheckbox:
+		var checked bool
+		if err := json.Unmarshal(raw, &checked); err != nil {
+			return nil, invalid("true or false")
+		}
+		value.Checked = &checked
+
+	case FieldSelect:
+		var option string
+		if err := json.Unmarshal(raw, &option); err != nil || !hasOption(field, option) {
+			return nil, invalid("one of its options")
+		}
+		value.Options = []string{option}
+
+	case FieldMultiSelect:
+		var options []string
+		if err := json.Unmarshal(raw, &options); err != nil {
+			return nil, invalid("a list of its options")
+		}
+		seen := make(map[string]bool, len(options))
+		for _, option := range options {
+			if !hasOption(field, option) {
+				return nil, invalid("a list of its options")
+			}
+			if !seen[option] {
+				seen[option] = true
+				value.Options = append(value.Options, option)
+			}
+		}
+		if len(value.Options) == 0 {
+			return nil, invalid("at least one option, or null to clear it")
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: unknown field type %q", ErrInvalidFieldValue, field.Type)
+	}
+
+	return value, nil
+}
+
+// Value returns a stored value in the JSON form SetValue accepts
+func (s *CustomFieldService) Value(field *models.CustomField, value *models.CardFieldValue) interface{} {
+	switch field.Type {
+	case FieldText:
+		return value.TextValue
+	case FieldNumber:
+		return value.NumberValue
+	case FieldDate:
+		return value.DateValue
+	case FieldCheckbox:
+		return value.Checked
+	case FieldSelect:
+		if len(value.Options) > 0 {
+			return value.Options[0]
+		}
+		return nil
+	default:
+		return value.Options
+	}
+}
+
+// PruneOptions drops options that no longer exist on the field from card
+// values, removing values left with no option.
+func (s *CustomFieldService) PruneOptions(tx *gorm.DB, field *models.CustomField) error {
+	var values []models.CardFieldValue
+	if err := tx.Where("field_id = ?", field.ID).Find(&values).Error; err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		kept := make([]string, 0, len(value.Options))
+		for _, option := range value.Options {
+			if hasOption(field, option) {
+				kept = append(kept, option)
+			}
+		}
+		if len(kept) == len(value.Options) {
+			continue
+		}
+
+		if len(kept) == 0 {
+			if err := tx.Delete(&value).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		value.Options = kept
+		if err := tx.Model(&value).Select("options").Updates(&value).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasOption(field *models.CustomField, option string) bool {
+	for _, candidate := range field.Options {
+		if candidate == option {
+			return true
+		}
+	}
+	return false
+}
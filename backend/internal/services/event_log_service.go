@@ -0,0 +1,119 @@
+package services
+
+import (
+	"encoding/json"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
+	"gorm.io/gorm"
+)
+
+// eventLogLockKey is the advisory lock, with the board ID, held while a
+// board's next sequence number is taken
+const eventLogLockKey = 0x65766e74
+
+// Event log retention per board. Older events are pruned every
+// eventLogPruneEvery appends, so a board keeps at most their sum.
+const (
+	EventLogRetain     = 1000
+	eventLogPruneEvery = 100
+)
+
+// EventLogService stores each board's broadcasts in sequence so WebSocket
+// clients can replay what they missed while disconnected. It implements
+// websocket.EventLog.
+type EventLogService struct {
+	DB *gorm.DB
+}
+
+// Append stores a broadcast as the board's next event and returns its
+// sequence number
+func (es *EventLogService) Append(boardID uint, messageType string, data interface{}) (uint64, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, err
+	}
+
+	event := models.BoardEvent{BoardID: boardID, Type: messageType, Data: payload}
+	err = es.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", eventLogLockKey, boardID).Error; err != nil {
+			return err
+		}
+		latest, err := es.latest(tx, boardID)
+		if err != nil {
+			return err
+		}
+		event.Seq = latest + 1
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+
+		if event.Seq%eventLogPruneEvery == 0 && event.Seq > EventLogRetain {
+			return tx.Where("board_id = ? AND seq <= ?", boardID, event.Seq-EventLogRetain).
+				Delete(&models.BoardEvent{}).Error
+		}
+		return nil
+	})
+	return event.Seq, err
+}
+
+// Latest returns the sequence number of the board's last event, 0 if none
+func (es *EventLogService) Latest(boardID uint) (uint64, error) {
+	return es.latest(es.DB, boardID)
+}
+
+// Since returns up to limit of the board's events after seq, oldest first,
+// with the latest sequence number. complete is false when the events after
+// seq are not all kept or number more than limit; the client must then
+// reload the board instead.
+func (es *EventLogService) Since(boardID uint, seq uint64, limit int) ([]ws.Message, uint64, bool, error) {
+	latest, err := es.latest(es.DB, boardID)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if seq >= latest {
+		return nil, latest, seq == latest, nil
+	}
+	if latest-seq > uint64(limit) {
+		return nil, latest, false, nil
+	}
+
+	var events []models.BoardEvent
+	if err := es.DB.Where("board_id = ? AND seq > ?", boardID, seq).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, 0, false, err
+	}
+	if uint64(len(events)) != latest-seq {
+		return nil, latest, false, nil
+	}
+
+	messages := make([]ws.Message, len(events))
+	for i, event := range events {
+		messages[i] = ws.Message{
+			Type:    event.Type,
+			BoardID: event.BoardID,
+			Seq:     event.Seq,
+			Data:    event.Data,
+		}
+	}
+	return messages, latest, true, nil
+}
+
+// Event returns one of the board's events as the message it was broadcast as
+func (es *EventLogService) Event(boardID uint, seq uint64) (*ws.Message, error) {
+	var event models.BoardEvent
+	if err := es.DB.Where("board_id = ? AND seq = ?", boardID, seq).First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &ws.Message{Type: event.Type, BoardID: event.BoardID, Seq: event.Seq, Data: event.Data}, nil
+}
+
+func (es *EventLogService) latest(db *gorm.DB, boardID uint) (uint64, error) {
+	var latest uint64
+	err := db.Model(&models.BoardEvent{}).Where("board_id = ?", boardID).
+		Select("COALESCE(MAX(seq), 0)").
+		Scan(&latest).Error
+	return latest, err
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LaneService keeps a board's lane positions numbered 0 to n-1. Each write
+// locks the board row, so concurrent lane changes on one board are serialised.
+type LaneService struct{}
+
+// CreateLane adds lane to the bottom of its board inside tx
+func (ls *LaneService) CreateLane(tx *gorm.DB, lane *models.Lane) error {
+	lanes, err := ls.lockLanes(tx, lane.BoardID)
+	if err != nil {
+		return err
+	}
+	if err := ls.renumber(tx, lanes); err != nil {
+		return err
+	}
+
+	lane.Position = len(lanes)
+	return tx.Create(lane).Error
+}
+
+// MoveLane moves lane to position inside tx, shifting the lanes between its
+// old and new place. Positions past the end put the lane last.
+func (ls *LaneService) MoveLane(tx *gorm.DB, lane *models.Lane, position int) error {
+	lanes, err := ls.lockLanes(tx, lane.BoardID)
+	if err != nil {
+		return err
+	}
+
+	var moved *models.Lane
+	others := make([]models.Lane, 0, len(lanes))
+	for i := range lanes {
+		if lanes[i].ID == lane.ID {
+			moved = &lanes[i]
+		} else {
+			others = append(others, lanes[i])
+		}
+	}
+	if moved == nil {
+		return gorm.ErrRecordNotFound
+	}
+	if position > len(others) {
+		position = len(others)
+	}
+
+	ordered := append(others[:position:position], *moved)
+	ordered = append(ordered, others[position:]...)
+	if err := ls.renumber(tx, ordered); err != nil {
+		return err
+	}
+	lane.Position = position
+	return nil
+}
+
+// DeleteLane deletes lane inside tx, moving its cards to the default lane and
+// closing the gap it leaves
+func (ls *LaneService) DeleteLane(tx *gorm.DB, lane *models.Lane) error {
+	lanes, err := ls.lockLanes(tx, lane.BoardID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.Card{}).Where("lane_id = ?", lane.ID).Updates(map[string]interface{}{
+		"lane_id": nil,
+		"version": gorm.Expr("version + 1"),
+	}).Error; err != nil {
+		return err
+	}
+	if err := tx.Delete(lane).Error; err != nil {
+		return err
+	}
+
+	remaining := make([]models.Lane, 0, len(lanes))
+	for i, other := range lanes {
+		if other.ID == lane.ID {
+			lane.Position = i
+		} else {
+			remaining = append(remaining, other)
+		}
+	}
+	return ls.renumber(tx, remaining)
+}
+
+// lockLanes locks the board and returns its lanes in display order
+func (ls *LaneService) lockLanes(tx *gorm.DB, boardID uint) ([]models.Lane, error) {
+	var board models.Board
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&board, boardID).Error; err != nil {
+		return nil, err
+	}
+
+	var lanes []models.Lane
+	err := tx.Where("board_id = ?", boardID).Order("position ASC, id ASC").Find(&lanes).Error
+	return lanes, err
+}
+
+// renumber gives lanes the positions of their order, writing only the rows
+// that change. Duplicate positions left by older versions are repaired too.
+func (ls *LaneService) renumber(tx *gorm.DB, lanes []models.Lane) error {
+	for i, lane := range lanes {
+		if lane.Position == i {
+			continue
+		}
+		if err := tx.Model(&models.Lane{}).Where("id = ?", lane.ID).UpdateColumn("position", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleList is returned when a client acts on an outdated copy of a list
+var ErrStaleList = errors.New("list has changed since it was loaded")
+
+// ErrWIPLimit is returned when a list's hard WIP limit refuses another card
+var ErrWIPLimit = errors.New("list is at its work-in-progress limit")
+
+// WIP limit modes
+const (
+	WIPSoft = "soft" // Cards over the limit are allowed with a warning
+	WIPHard = "hard" // Cards over the limit are refused
+)
+
+// WIPCheck is a list's card count measured against its WIP limit
+type WIPCheck struct {
+	ListID uint
+	Count  int
+	Limit  int
+	Mode   string
+	Over   bool
+}
+
+// ListService holds list operations that must run atomically
+type ListService struct{}
+
+// MoveList moves a list inside tx. The board row is locked so concurrent list
+// moves on one board are serialised, and only the moved row is written.
+func (ls *ListService) MoveList(tx *gorm.DB, listID uint, position int, version *int) (*models.List, error) {
+	var list models.List
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&list, listID).Error; err != nil {
+		return nil, err
+	}
+
+	if version != nil && *version != list.Version {
+		return &list, ErrStaleList
+	}
+
+	var board models.Board
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&board, list.BoardID).Error; err != nil {
+		return nil, err
+	}
+
+	ordering := &OrderingService{}
+	rank, err := ordering.ListRankAt(tx, list.BoardID, position, list.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(&list).UpdateColumns(map[string]interface{}{
+		"rank":    rank,
+		"version": gorm.Expr("version + 1"),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	list.Rank = rank
+	list.Version++
+	return &list, nil
+}
+
+// RepairCardOrder normalises a list: duplicate or overgrown rank keys are
+// replaced with evenly spaced ones, keeping the current display order
+func (ls *ListService) RepairCardOrder(tx *gorm.DB, listID uint) ([]models.Card, error) {
+	// Cards before their list, as in CardService.MoveCard
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("list_id = ?", listID).
+		Order("id ASC").
+		Find(&[]models.Card{}).Error; err != nil {
+		return nil, err
+	}
+	if err := LockLists(tx, listID); err != nil {
+		return nil, err
+	}
+
+	ordering := &OrderingService{}
+	if err := ordering.RebalanceCards(tx, listID); err != nil {
+		return nil, err
+	}
+
+	var cards []models.Card
+	if err := tx.Where("list_id = ?", listID).Order("rank ASC, id ASC").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// CardCounts returns the number of unarchived cards in each of the lists.
+// Lists without cards are left out.
+func (ls *ListService) CardCounts(db *gorm.DB, listIDs []uint) (map[uint]int, error) {
+	var rows []struct {
+		ListID uint
+		Count  int
+	}
+	if err := db.Model(&models.Card{}).
+		Select("list_id, COUNT(*) AS count").
+		Where("list_id IN ? AND archived_at IS NULL", listIDs).
+		Group("list_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.ListID] = row.Count
+	}
+	return counts, nil
+}
+
+// CheckWIP counts the cards in list after a card was added to it inside tx,
+// with the list locked. It returns nil for lists without a limit, and
+// ErrWIPLimit along with the check when a hard limit is exceeded.
+func (ls *ListService) CheckWIP(tx *gorm.DB, list *models.List) (*WIPCheck, error) {
+	if list.WIPLimit == nil {
+		return nil, nil
+	}
+
+	counts, err := ls.CardCounts(tx, []uint{list.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	check := &WIPCheck{
+		ListID: list.ID,
+		Count:  counts[list.ID],
+		Limit:  *list.WIPLimit,
+		Mode:   list.WIPMode,
+	}
+	check.Over = check.Count > check.Limit
+	if check.Over && check.Mode == WIPHard {
+		return check, ErrWIPLimit
+	}
+	return check, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"log"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// RebalanceRankLength is the key length at which the background job respaces a list
+const RebalanceRankLength = 24
+
+// maxRankLength forces an immediate respace instead of writing an ever longer key
+const maxRankLength = 128
+
+// OrderingService hands out rank keys so that a move only writes the moved row
+type OrderingService struct{}
+
+// CardRankAt returns a rank that places a card at index within a list.
+// excludeID is the card being moved, so it does not count as its own neighbour.
+// Archived cards are hidden from clients, so index counts only active ones.
+func (ords *OrderingService) CardRankAt(tx *gorm.DB, listID uint, index int, excludeID uint) (string, error) {
+	siblings := func() *gorm.DB {
+		return tx.Model(&models.Card{}).Where("list_id = ? AND id <> ? AND archived_at IS NULL", listID, excludeID)
+	}
+
+	rank, err := rankAt(siblings, index)
+	if err == utils.ErrRankOrder || len(rank) > maxRankLength {
+		// Duplicate or exhausted keys: respace the list and try again
+		if err := ords.RebalanceCards(tx, listID); err != nil {
+			return "", err
+		}
+		return rankAt(siblings, index)
+	}
+	return rank, err
+}
+
+// ListRankAt returns a rank that places a list at index within a board
+func (ords *OrderingService) ListRankAt(tx *gorm.DB, boardID uint, index int, excludeID uint) (string, error) {
+	siblings := func() *gorm.DB {
+		return tx.Model(&models.List{}).Where("board_id = ? AND id <> ?", boardID, excludeID)
+	}
+
+	rank, err := rankAt(siblings, index)
+	if err == utils.ErrRankOrder || len(rank) > maxRankLength {
+		if err := ords.RebalanceLists(tx, boardID); err != nil {
+			return "", err
+		}
+		return rankAt(siblings, index)
+	}
+	return rank, err
+}
+
+// NextCardRank returns a rank that places a new card at the end of a list
+func (ords *OrderingService) NextCardRank(tx *gorm.DB, listID uint) (string, error) {
+	return ords.CardRankAt(tx, listID, -1, 0)
+}
+
+// NextListRank returns a rank that places a new list at the end of a board
+func (ords *OrderingService) NextListRank(tx *gorm.DB, boardID uint) (string, error) {
+	return ords.ListRankAt(tx, boardID, -1, 0)
+}
+
+// CardIndex returns the zero-based display position of a card in its list,
+// among the active cards clients are shown
+func (ords *OrderingService) CardIndex(tx *gorm.DB, card *models.Card) (int, error) {
+	var count int64
+	err := tx.Model(&models.Card{}).
+		Where("list_id = ? AND archived_at IS NULL AND (rank < ? OR (rank = ? AND id < ?))", card.ListID, card.Rank, card.Rank, card.ID).
+		Count(&count).Error
+	return int(count), err
+}
+
+// RebalanceCards rewrites the ranks of every card in a list with evenly spaced keys
+func (ords *OrderingService) RebalanceCards(tx *gorm.DB, listID uint) error {
+	var ids []uint
+	if err := tx.Model(&models.Card{}).
+		Where("list_id = ?", listID).
+		Order("rank ASC, id ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	return respace(tx.Model(&models.Card{}), ids)
+}
+
+// RebalanceLists rewrites the ranks of every list in a board with evenly spaced keys
+func (ords *OrderingService) RebalanceLists(tx *gorm.DB, boardID uint) error {
+	var ids []uint
+	if err := tx.Model(&models.List{}).
+		Where("board_id = ?", boardID).
+		Order("rank ASC, id ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	return respace(tx.Model(&models.List{}), ids)
+}
+
+// rankAt computes a key between the siblings at index-1 and index.
+// A negative or out of range index means "at the end".
+func rankAt(siblings func() *gorm.DB, index int) (string, error) {
+	var prev, next string
+
+	if index < 0 {
+		var last []string
+		if err := siblings().Order("rank DESC, id DESC").Limit(1).Pluck("rank", &last).Error; err != nil {
+			return "", err
+		}
+		if len(last) > 0 {
+			prev = last[0]
+		}
+		return utils.RankBetween(prev, "")
+	}
+
+	offset := index - 1
+	limit := 2
+	if offset < 0 {
+		offset = 0
+		limit = 1
+	}
+
+	var neighbours []string
+	if err := siblings().Order("rank ASC, id ASC").
+		Offset(offset).Limit(limit).
+		Pluck("rank", &neighbours).Error; err != nil {
+		return "", err
+	}
+
+	switch {
+	case index == 0 && len(neighbours) > 0:
+		next = neighbours[0]
+	case index > 0 && len(neighbours) == 1:
+		prev = neighbours[0]
+	case index > 0 && len(neighbours) == 2:
+		prev, next = neighbours[0], neighbours[1]
+	case index > 0:
+		// Past the end of the list
+		return rankAt(siblings, -1)
+	}
+
+	return utils.RankBetween(prev, next)
+}
+
+// respace assigns evenly spaced ranks to the given rows in order
+func respace(table *gorm.DB, ids []uint) error {
+	ranks := utils.RankSequence(len(ids))
+	for i, id := range ids {
+		if err := table.Session(&gorm.Session{}).Where("id = ?", id).UpdateColumn("rank", ranks[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConvertLegacyPositions replaces the old integer position columns with rank
+// keys, keeping the existing order. It is a no-op once the columns are gone.
+func (ords *OrderingService) ConvertLegacyPositions(db *gorm.DB) error {
+	if err := convertPositions(db, "lists", "board_id"); err != nil {
+		return err
+	}
+	return convertPositions(db, "cards", "list_id")
+}
+
+// convertPositions ranks the rows of one table by (position, id) per parent
+func convertPositions(db *gorm.DB, table, parentColumn string) error {
+	if !db.Migrator().HasColumn(table, "position") {
+		return nil
+	}
+
+	log.Printf("🔄 Converting %s positions to rank keys...", table)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var parentIDs []uint
+		if err := tx.Table(table).Distinct(parentColumn).Pluck(parentColumn, &parentIDs).Error; err != nil {
+			return err
+		}
+
+		// Trashed rows are ranked too so they restore into the right place
+		for _, parentID := range parentIDs {
+			var ids []uint
+			if err := tx.Table(table).
+				Where(parentColumn+" = ?", parentID).
+				Order("position ASC, id ASC").
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if err := respace(tx.Table(table), ids); err != nil {
+				return err
+			}
+		}
+
+		return tx.Migrator().DropColumn(table, "position")
+	})
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// PostgreSQL channel board messages are published on
+const pubSubChannel = "flowboard_board_messages"
+
+// pubSubPayloadLimit keeps notifications under PostgreSQL's 8000 byte
+// payload limit. Larger messages are sent by sequence number and read back
+// from the event log.
+const pubSubPayloadLimit = 7900
+
+// pubSubRetry is how long the listener waits before reconnecting
+const pubSubRetry = 2 * time.Second
+
+// ErrMessageTooLarge is returned when a message is too large to notify and
+// is not in the event log
+var ErrMessageTooLarge = errors.New("message is too large to publish")
+
+// PubSubService carries board messages between API instances sharing a
+// database with LISTEN/NOTIFY. Messages reach this instance's hub directly
+// and other instances through a notification. Notifications from different
+// instances can arrive out of order; the hub reorders numbered messages.
+// Messages published while an instance is reconnecting its listener are not
+// received by it; its clients catch up when they reconnect. It implements
+// websocket.PubSub.
+type PubSubService struct {
+	DB     *gorm.DB
+	Events *EventLogService // Reads back messages too large to notify
+
+	once        sync.Once
+	instance    string
+	mu          sync.RWMutex
+	subscribers []func(*ws.Message)
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+// pubSubNotification is the payload of a notification: the message, or for
+// a large one, its board and sequence number
+type pubSubNotification struct {
+	Instance string      `json:"instance"`
+	Message  *ws.Message `json:"message,omitempty"`
+	BoardID  uint        `json:"board_id,omitempty"`
+	Seq      uint64      `json:"seq,omitempty"`
+}
+
+// Publish delivers the message to this instance's subscribers and notifies
+// the other instances
+func (ps *PubSubService) Publish(message *ws.Message) error {
+	ps.init()
+	ps.deliver(message)
+
+	payload, err := json.Marshal(pubSubNotification{Instance: ps.instance, Message: message})
+	if err != nil {
+		return err
+	}
+	if len(payload) > pubSubPayloadLimit {
+		if message.Seq == 0 || ps.Events == nil {
+			return ErrMessageTooLarge
+		}
+		payload, _ = json.Marshal(pubSubNotification{Instance: ps.instance, BoardID: message.BoardID, Seq: message.Seq})
+	}
+
+	return ps.DB.Exec("SELECT pg_notify(?, ?)", pubSubChannel, string(payload)).Error
+}
+
+// Subscribe adds a subscriber. The first one starts listening for other
+// instances' messages.
+func (ps *PubSubService) Subscribe(deliver func(*ws.Message)) {
+	ps.init()
+
+	ps.mu.Lock()
+	ps.subscribers = append(ps.subscribers, deliver)
+	first := len(ps.subscribers) == 1
+	ps.mu.Unlock()
+
+	if first {
+		go ps.listen()
+	}
+}
+
+// Ready is closed once the listener first receives other instances'
+// messages
+func (ps *PubSubService) Ready() <-chan struct{} {
+	ps.init()
+	return ps.ready
+}
+
+// Close stops the listener. Messages are still published.
+func (ps *PubSubService) Close() {
+	ps.init()
+	ps.cancel()
+}
+
+// init picks the ID that tells this instance's notifications apart
+func (ps *PubSubService) init() {
+	ps.once.Do(func() {
+		instance, err := utils.GenerateToken()
+		if err != nil {
+			instance = time.Now().String()
+		}
+		ps.instance = instance
+		ps.ctx, ps.cancel = context.WithCancel(context.Background())
+		ps.ready = make(chan struct{})
+	})
+}
+
+// deliver hands a message to every subscriber
+func (ps *PubSubService) deliver(message *ws.Message) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	for _, deliver := range ps.subscribers {
+		deliver(message)
+	}
+}
+
+// listen receives notifications on a dedicated connection, reconnecting
+// after failures, until Close is called
+func (ps *PubSubService) listen() {
+	for {
+		if err := ps.listenOnce(ps.ctx); err != nil && ps.ctx.Err() == nil {
+			log.Printf("Board message listener stopped: %v", err)
+		}
+		select {
+		case <-ps.ctx.Done():
+			return
+		case <-time.After(pubSubRetry):
+		}
+	}
+}
+
+// listenOnce listens until the connection fails
+func (ps *PubSubService) listenOnce(ctx context.Context) error {
+	sqlDB, err := ps.DB.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "LISTEN "+pubSubChannel); err != nil {
+		return err
+	}
+	ps.readyOnce.Do(func() { close(ps.ready) })
+
+	return conn.Raw(func(driverConn any) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("LISTEN needs the pgx driver")
+		}
+		for {
+			notification, err := pgxConn.Conn().WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			ps.receive(notification.Payload)
+		}
+	})
+}
+
+// receive delivers another instance's message
+func (ps *PubSubService) receive(payload string) {
+	var notification pubSubNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		log.Printf("Invalid board message notification: %v", err)
+		return
+	}
+	if notification.Instance == ps.instance {
+		return
+	}
+
+	message := notification.Message
+	if message == nil {
+		if ps.Events == nil {
+			return
+		}
+		var err error
+		if message, err = ps.Events.Event(notification.BoardID, notification.Seq); err != nil {
+			log.Printf("Error loading event %d of board %d: %v", notification.Seq, notification.BoardID, err)
+			return
+		}
+	}
+	ps.deliver(message)
+}
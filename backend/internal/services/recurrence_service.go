@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/recurrence"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoOccurrence is returned when a recurrence rule never fires again
+var ErrNoOccurrence = errors.New("recurrence has no upcoming occurrence")
+
+// RecurrenceService creates the copies of recurring cards
+type RecurrenceService struct{}
+
+// Rule builds the schedule's recurrence rule
+func (rs *RecurrenceService) Rule(schedule *models.CardRecurrence) (*recurrence.Rule, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", schedule.Timezone)
+	}
+
+	weekdays := make([]time.Weekday, len(schedule.Weekdays))
+	for i, day := range schedule.Weekdays {
+		weekdays[i] = time.Weekday(day)
+	}
+
+	rule := &recurrence.Rule{
+		Frequency:  schedule.Frequency,
+		Interval:   schedule.Interval,
+		Weekdays:   weekdays,
+		DayOfMonth: schedule.DayOfMonth,
+		TimeOfDay:  schedule.TimeOfDay,
+		Cron:       schedule.Cron,
+		Location:   location,
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ScheduleNext sets NextRunAt to the first occurrence after from
+func (rs *RecurrenceService) ScheduleNext(schedule *models.CardRecurrence, from time.Time) error {
+	rule, err := rs.Rule(schedule)
+	if err != nil {
+		return err
+	}
+
+	next := rule.Next(schedule.StartsAt, from)
+	if next.IsZero() {
+		return ErrNoOccurrence
+	}
+	schedule.NextRunAt = next.UTC()
+	return nil
+}
+
+// RunDue makes the copies due at now, up to limit, and returns them. Each
+// schedule is claimed with FOR UPDATE SKIP LOCKED and advanced in the same
+// transaction as its copy, so concurrent or restarted schedulers never copy
+// an occurrence twice. Occurrences missed while the scheduler was down are
+// collapsed into one copy for the latest of them.
+func (rs *RecurrenceService) RunDue(db *gorm.DB, now time.Time, limit int) ([]models.Card, error) {
+	var created []models.Card
+	for len(created) < limit {
+		var card *models.Card
+		found := false
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var schedule models.CardRecurrence
+			result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("paused = ? AND next_run_at <= ?", false, now).
+				Order("next_run_at ASC").
+				Limit(1).
+				Find(&schedule)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			found = true
+
+			var err error
+			card, err = rs.runSchedule(tx, &schedule, now)
+			return err
+		})
+		if err != nil {
+			return created, err
+		}
+		if !found {
+			break
+		}
+		if card != nil {
+			created = append(created, *card)
+		}
+	}
+	return created, nil
+}
+
+// runSchedule copies the template for the latest due occurrence and moves
+// the schedule on. A template or target list in the trash skips the run.
+func (rs *RecurrenceService) runSchedule(tx *gorm.DB, schedule *models.CardRecurrence, now time.Time) (*models.Card, error) {
+	rule, err := rs.Rule(schedule)
+	if err != nil {
+		// The rule was valid when saved; pause rather than retry it every tick
+		return nil, tx.Model(schedule).Update("paused", true).Error
+	}
+
+	occurrence := schedule.NextRunAt
+	for {
+		next := rule.Next(schedule.StartsAt, occurrence)
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		occurrence = next
+	}
+
+	var card *models.Card
+	var template models.Card
+	var targetList models.List
+	if tx.First(&template, schedule.CardID).Error == nil && tx.First(&targetList, schedule.TargetListID).Error == nil {
+		if card, err = rs.copyTemplate(tx, schedule, &template, occurrence); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := map[string]interface{}{"last_run_at": now}
+	if next := rule.Next(schedule.StartsAt, occurrence); next.IsZero() {
+		updates["paused"] = true
+	} else {
+		updates["next_run_at"] = next.UTC()
+	}
+	if err := tx.Model(schedule).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// copyTemplate adds a copy of template to the end of the target list, with
+// its labels, members and custom field values. The due date is shifted by
+// the time from the schedule's start to the occurrence. Returns nil if this
+// occurrence was already copied, or if the target list's hard WIP limit
+// refuses it; a refused occurrence is skipped rather than retried.
+func (rs *RecurrenceService) copyTemplate(tx *gorm.DB, schedule *models.CardRecurrence, template *models.Card, occurrence time.Time) (*models.Card, error) {
+	card := &models.Card{
+		Title:        template.Title,
+		Description:  template.Description,
+		ListID:       schedule.TargetListID,
+		RecurrenceID: &schedule.ID,
+		OccurrenceAt: &occurrence,
+	}
+	if template.DueDate != nil {
+		due := template.DueDate.Add(occurrence.Sub(schedule.StartsAt))
+		card.DueDate = &due
+	}
+
+	// A savepoint, so a refused copy is undone without losing the schedule
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		cardService := &CardService{}
+		if _, err := cardService.CreateCard(tx, card, 0); err != nil || card.ID == 0 {
+			return err
+		}
+		return rs.copyDetails(tx, card.ID, template.ID)
+	})
+	if errors.Is(err, ErrWIPLimit) {
+		return nil, nil
+	}
+	if err != nil || card.ID == 0 {
+		return nil, err
+	}
+	return card, nil
+}
+
+// copyDetails copies a template's labels, members and custom field values
+// to a new card
+func (rs *RecurrenceService) copyDetails(tx *gorm.DB, cardID, templateID uint) error {
+	copies := []string{
+		"INSERT INTO card_labels (card_id, label_id) SELECT ?, label_id FROM card_labels WHERE card_id = ?",
+		"INSERT INTO card_members (card_id, user_id, assigned_at) SELECT ?, user_id, NOW() FROM card_members WHERE card_id = ?",
+		"INSERT INTO card_field_values (card_id, field_id, text_value, number_value, date_value, checked, options, created_at, updated_at) " +
+			"SELECT ?, field_id, text_value, number_value, date_value, checked, options, NOW(), NOW() FROM card_field_values WHERE card_id = ?",
+	}
+	for _, statement := range copies {
+		if err := tx.Exec(statement, cardID, templateID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Card relation types
+const (
+	RelationBlocks    = "blocks"
+	RelationRelatesTo = "relates_to"
+)
+
+var (
+	// ErrSelfRelation is returned when a card is linked to itself
+	ErrSelfRelation = errors.New("a card cannot be related to itself")
+	// ErrRelationExists is returned when the two cards are already linked this way
+	ErrRelationExists = errors.New("cards are already related")
+	// ErrRelationCycle is returned when a blocking relation would make a card block itself
+	ErrRelationCycle = errors.New("relation would create a blocking cycle")
+)
+
+// relationLockKey is the advisory lock taken while linking cards, so that two
+// concurrent links cannot together form a blocking cycle
+const relationLockKey = 0x72656c73
+
+// RelationService manages links between cards
+type RelationService struct{}
+
+// Link relates two cards inside tx. For blocks, source blocks target and the
+// link is refused if target already blocks source, directly or through other
+// cards.
+func (rs *RelationService) Link(tx *gorm.DB, sourceID, targetID uint, relationType string, userID uint) (*models.CardRelation, error) {
+	if sourceID == targetID {
+		return nil, ErrSelfRelation
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", relationLockKey).Error; err != nil {
+		return nil, err
+	}
+
+	// relates_to has no direction, so either order counts as a duplicate
+	var existing int64
+	query := tx.Model(&models.CardRelation{}).Where("type = ?", relationType)
+	if relationType == RelationRelatesTo {
+		query = query.Where("(source_card_id = ? AND target_card_id = ?) OR (source_card_id = ? AND target_card_id = ?)",
+			sourceID, targetID, targetID, sourceID)
+	} else {
+		query = query.Where("source_card_id = ? AND target_card_id = ?", sourceID, targetID)
+	}
+	if err := query.Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrRelationExists
+	}
+
+	if relationType == RelationBlocks {
+		cycle, err := rs.blocks(tx, targetID, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, ErrRelationCycle
+		}
+	}
+
+	relation := &models.CardRelation{
+		SourceCardID: sourceID,
+		TargetCardID: targetID,
+		Type:         relationType,
+		CreatedBy:    userID,
+	}
+	if err := tx.Create(relation).Error; err != nil {
+		return nil, err
+	}
+	return relation, nil
+}
+
+// blocks reports whether card from blocks card to, directly or transitively
+func (rs *RelationService) blocks(tx *gorm.DB, from, to uint) (bool, error) {
+	var found bool
+	err := tx.Raw(`
+		WITH RECURSIVE reachable(id) AS (
+			SELECT target_card_id FROM card_relations WHERE source_card_id = ? AND type = ?
+			UNION
+			SELECT card_relations.target_card_id FROM card_relations
+			JOIN reachable ON card_relations.source_card_id = reachable.id
+			WHERE card_relations.type = ?
+		)
+		SELECT EXISTS (SELECT 1 FROM reachable WHERE id = ?)`,
+		from, RelationBlocks, RelationBlocks, to).Scan(&found).Error
+	return found, err
+}
+
+// Blockers returns, for each of cardIDs that is blocked, the IDs of the cards
+// blocking it. A blocker stops counting once it is in a done-list, archived
+// or deleted.
+func (rs *RelationService) Blockers(db *gorm.DB, cardIDs []uint) (map[uint][]uint, error) {
+	blockers := make(map[uint][]uint)
+	if len(cardIDs) == 0 {
+		return blockers, nil
+	}
+
+	var rows []struct {
+		TargetCardID uint
+		SourceCardID uint
+	}
+	if err := db.Table("card_relations").
+		Select("card_relations.target_card_id, card_relations.source_card_id").
+		Joins("JOIN cards ON cards.id = card_relations.source_card_id AND cards.deleted_at IS NULL AND cards.archived_at IS NULL").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("card_relations.type = ? AND card_relations.target_card_id IN ? AND NOT lists.is_done", RelationBlocks, cardIDs).
+		Order("card_relations.source_card_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		blockers[row.TargetCardID] = append(blockers[row.TargetCardID], row.SourceCardID)
+	}
+	return blockers, nil
+}
+
+// Dependencies returns the blocking relations between cards that are both in
+// cardIDs
+func (rs *RelationService) Dependencies(db *gorm.DB, cardIDs []uint) ([]models.CardRelation, error) {
+	var relations []models.CardRelation
+	if len(cardIDs) == 0 {
+		return relations, nil
+	}
+	err := db.Where("type = ? AND source_card_id IN ? AND target_card_id IN ?", RelationBlocks, cardIDs, cardIDs).
+		Order("id ASC").
+		Find(&relations).Error
+	return relations, err
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Card fields that keep a revision history
+const (
+	RevisionTitle       = "title"
+	RevisionDescription = "description"
+	RevisionDueDate     = "due_date"
+	RevisionList        = "list_id"
+	RevisionLabels      = "labels"
+	RevisionMembers     = "members"
+)
+
+// revisionFields is the set of fields Record keeps
+var revisionFields = map[string]bool{
+	RevisionTitle:       true,
+	RevisionDescription: true,
+	RevisionDueDate:     true,
+	RevisionList:        true,
+	RevisionLabels:      true,
+	RevisionMembers:     true,
+}
+
+// ErrRevisionList is returned when reverting to a list that was deleted or
+// is no longer on the card's board
+var ErrRevisionList = errors.New("the list of this revision no longer exists on the board")
+
+// RevisionService keeps the history of a card's tracked fields
+type RevisionService struct{}
+
+// IsRevisionField reports whether field keeps a revision history
+func IsRevisionField(field string) bool {
+	return revisionFields[field]
+}
+
+// Record stores a revision for each tracked field in changes whose value
+// changed. Other fields are ignored. userID is the acting user, 0 for the
+// system.
+func (rs *RevisionService) Record(db *gorm.DB, cardID, userID uint, changes []models.FieldChange) error {
+	var user *uint
+	if userID != 0 {
+		user = &userID
+	}
+
+	var revisions []models.CardRevision
+	for _, change := range changes {
+		if !revisionFields[change.Field] {
+			continue
+		}
+		oldValue, err := json.Marshal(change.From)
+		if err != nil {
+			return err
+		}
+		newValue, err := json.Marshal(change.To)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		revisions = append(revisions, models.CardRevision{
+			CardID:   cardID,
+			Field:    change.Field,
+			OldValue: oldValue,
+			NewValue: newValue,
+			UserID:   user,
+		})
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	return db.Create(&revisions).Error
+}
+
+// TrackSet runs change, which adds or removes labels or members of a card
+// (field RevisionLabels or RevisionMembers) through tx, and records a
+// revision with the card's set before and after if it differs. The card row
+// is locked first so concurrent changes to its sets are recorded in turn.
+func (rs *RevisionService) TrackSet(tx *gorm.DB, cardID, userID uint, field string, change func() error) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Card{}, cardID).Error; err != nil {
+		return err
+	}
+
+	before, err := rs.SetIDs(tx, cardID, field)
+	if err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	after, err := rs.SetIDs(tx, cardID, field)
+	if err != nil {
+		return err
+	}
+	return rs.Record(tx, cardID, userID, []models.FieldChange{{Field: field, From: before, To: after}})
+}
+
+// SetIDs returns the sorted IDs of a card's labels (RevisionLabels) or
+// members (RevisionMembers)
+func (rs *RevisionService) SetIDs(db *gorm.DB, cardID uint, field string) ([]uint, error) {
+	ids := []uint{}
+	var err error
+	if field == RevisionLabels {
+		err = db.Model(&models.CardLabel{}).Where("card_id = ?", cardID).Order("label_id").Pluck("label_id", &ids).Error
+	} else {
+		err = db.Model(&models.CardMember{}).Where("card_id = ?", cardID).Order("user_id").Pluck("user_id", &ids).Error
+	}
+	return ids, err
+}
+
+// Revert sets a card's field back to value, the old or new value of one of
+// its revisions, inside tx and records the change as a new revision. A list
+// revert moves the card to the bottom of that list and also returns the
+// list's WIP check. Labels deleted since and users who left the board are
+// left out. The change is nil when the field already has the value.
+func (rs *RevisionService) Revert(tx *gorm.DB, card *models.Card, field string, value json.RawMessage, userID uint) (*models.FieldChange, *WIPCheck, error) {
+	change, err := rs.revertChange(tx, card, field, value)
+	if err != nil || change == nil {
+		return nil, nil, err
+	}
+
+	cardService := &CardService{}
+	switch change.Field {
+	case RevisionTitle:
+		card.Title = change.To.(string)
+		err = cardService.updateColumns(tx, card, map[string]interface{}{"title": card.Title})
+
+	case RevisionDescription:
+		card.Description = change.To.(string)
+		err = cardService.updateColumns(tx, card, map[string]interface{}{"description": card.Description})
+
+	case RevisionDueDate:
+		card.DueDate = change.To.(*time.Time)
+		err = cardService.updateColumns(tx, card, map[string]interface{}{"due_date": card.DueDate})
+
+	case RevisionList:
+		// MoveCard records the list revision itself
+		moved, wip, err := cardService.MoveCard(tx, card.ID, MoveCardInput{ListID: change.To.(uint), Position: -1, UserID: userID})
+		if err != nil {
+			return nil, wip, err
+		}
+		card.ListID, card.Rank, card.Version = moved.ListID, moved.Rank, moved.Version
+		return change, wip, nil
+
+	default:
+		err = rs.replaceSet(tx, card.ID, change.Field, change.To.([]uint))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return change, nil, rs.Record(tx, card.ID, userID, []models.FieldChange{*change})
+}
+
+// revertChange decodes a revision value of field and pairs it with the
+// card's current value. It returns nil when the two are the same.
+func (rs *RevisionService) revertChange(tx *gorm.DB, card *models.Card, field string, value json.RawMessage) (*models.FieldChange, error) {
+	change := &models.FieldChange{Field: field}
+	var err error
+	switch field {
+	case RevisionTitle, RevisionDescription:
+		var text string
+		err = json.Unmarshal(value, &text)
+		change.From, change.To = card.Title, text
+		if field == RevisionDescription {
+			change.From = card.Description
+		}
+
+	case RevisionDueDate:
+		var dueDate *time.Time
+		err = json.Unmarshal(value, &dueDate)
+		change.From, change.To = card.DueDate, dueDate
+
+	case RevisionList:
+		var listID uint
+		if err := json.Unmarshal(value, &listID); err != nil {
+			return nil, err
+		}
+		var count int64
+		if err := tx.Model(&models.List{}).Where("id = ? AND board_id = ?", listID, card.List.BoardID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrRevisionList
+		}
+		change.From, change.To = card.ListID, listID
+
+	case RevisionLabels, RevisionMembers:
+		var ids []uint
+		if err := json.Unmarshal(value, &ids); err != nil {
+			return nil, err
+		}
+		if ids, err = rs.availableIDs(tx, card.List.BoardID, field, ids); err != nil {
+			return nil, err
+		}
+		var current []uint
+		if current, err = rs.SetIDs(tx, card.ID, field); err != nil {
+			return nil, err
+		}
+		change.From, change.To = current, ids
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	from, _ := json.Marshal(change.From)
+	to, _ := json.Marshal(change.To)
+	if bytes.Equal(from, to) {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// availableIDs keeps the labels still on the board, or the users who can
+// still view it, of ids
+func (rs *RevisionService) availableIDs(tx *gorm.DB, boardID uint, field string, ids []uint) ([]uint, error) {
+	available := []uint{}
+	if len(ids) == 0 {
+		return available, nil
+	}
+
+	if field == RevisionLabels {
+		err := tx.Model(&models.Label{}).Where("id IN ? AND board_id = ?", ids, boardID).Order("id").Pluck("id", &available).Error
+		return available, err
+	}
+
+	permService := &PermissionService{}
+	for _, id := range ids {
+		if permService.CheckPermission(id, boardID, "view_board") {
+			available = append(available, id)
+		}
+	}
+	sort.Slice(available, func(i, j int) bool { return available[i] < available[j] })
+	return available, nil
+}
+
+// replaceSet makes ids the card's labels or members
+func (rs *RevisionService) replaceSet(tx *gorm.DB, cardID uint, field string, ids []uint) error {
+	if field == RevisionLabels {
+		query := tx.Where("card_id = ?", cardID)
+		if len(ids) > 0 {
+			query = query.Where("label_id NOT IN ?", ids)
+		}
+		if err := query.Delete(&models.CardLabel{}).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.CardLabel{CardID: cardID, LabelID: id}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	query := tx.Where("card_id = ?", cardID)
+	if len(ids) > 0 {
+		query = query.Where("user_id NOT IN ?", ids)
+	}
+	if err := query.Delete(&models.CardMember{}).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.CardMember{CardID: cardID, UserID: id}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/search"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Markers passed to ts_headline. Control characters do not occur in normal
+// text, and they are swapped for <mark> tags only after the text is escaped.
+const (
+	highlightStart = "\x01"
+	highlightStop  = "\x02"
+)
+
+const (
+	titleHeadline   = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", HighlightAll=true`
+	excerptHeadline = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "`
+)
+
+// SearchFilter narrows a card search. Zero values mean no filter.
+type SearchFilter struct {
+	Query      *search.Query
+	BoardID    uint
+	ListID     uint
+	LabelID    uint
+	MemberID   uint
+	HasDueDate *bool
+}
+
+// SearchCursor is the position of the last result on a page. The next page
+// starts with the results ranked below it.
+type SearchCursor struct {
+	Score float32
+	ID    uint
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (sc SearchCursor) Encode() string {
+	raw := strconv.FormatFloat(float64(sc.Score), 'g', -1, 32) + ":" + strconv.FormatUint(uint64(sc.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSearchCursor parses a cursor produced by SearchCursor.Encode
+func DecodeSearchCursor(encoded string) (*SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	score, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	parsedScore, err := strconv.ParseFloat(score, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parsedID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &SearchCursor{Score: float32(parsedScore), ID: uint(parsedID)}, nil
+}
+
+// SearchHit is one matching card with the snippets that matched, keyed by
+// field: title, description, comment and attachment
+type SearchHit struct {
+	CardID     uint
+	BoardID    uint
+	Score      float32
+	Highlights map[string]string
+}
+
+// SearchService runs full-text card searches over the generated search_vector columns
+type SearchService struct{}
+
+// SearchCards returns up to limit cards the user can see on any board they are
+// an active member of, best match first. Comments and attachment file names
+// count towards a card's score. The returned cursor is nil on the last page.
+func (ss *SearchService) SearchCards(userID uint, filter SearchFilter, after *SearchCursor, limit int) ([]SearchHit, *SearchCursor, error) {
+	query := searchText(filter)
+
+	ranked := ss.rankedCards(userID, filter, query)
+
+	page := database.DB.Table("(?) AS ranked", ranked)
+	if after != nil {
+		page = page.Where("score < CAST(? AS real) OR (score = CAST(? AS real) AND id < ?)", after.Score, after.Score, after.ID)
+	}
+
+	var rows []struct {
+		ID      uint
+		BoardID uint
+		Score   float32
+	}
+	if err := page.Select("id, board_id, score").
+		Order("score DESC, id DESC").
+		Limit(limit + 1).
+		Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	hits := make([]SearchHit, len(rows))
+	for i, row := range rows {
+		hits[i] = SearchHit{CardID: row.ID, BoardID: row.BoardID, Score: row.Score}
+	}
+
+	var next *SearchCursor
+	if len(hits) > limit {
+		hits = hits[:limit]
+		last := hits[len(hits)-1]
+		next = &SearchCursor{Score: last.Score, ID: last.CardID}
+	}
+
+	if query != "" && len(hits) > 0 {
+		if err := ss.highlight(hits, query); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return hits, next, nil
+}
+
+// RecentCards returns the IDs of up to limit matching cards, most recently
+// updated first. Used by feeds, where recency matters more than relevance.
+func (ss *SearchService) RecentCards(userID uint, filter SearchFilter, limit int) ([]uint, error) {
+	var ids []uint
+	err := database.DB.Table("(?) AS ranked", ss.rankedCards(userID, filter, "")).
+		Order("updated_at DESC, id DESC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CountCards returns how many cards match
+func (ss *SearchService) CountCards(userID uint, filter SearchFilter) (int64, error) {
+	var count int64
+	err := database.DB.Table("(?) AS ranked", ss.rankedCards(userID, filter, "")).Count(&count).Error
+	return count, err
+}
+
+// searchText returns the free text used to rank and highlight results
+func searchText(filter SearchFilter) string {
+	if filter.Query == nil {
+		return ""
+	}
+	return strings.TrimSpace(filter.Query.Text)
+}
+
+// rankedCards builds the subquery of visible, matching cards and their scores.
+// Matching is decided by the parsed query; query only feeds the score.
+func (ss *SearchService) rankedCards(userID uint, filter SearchFilter, query string) *gorm.DB {
+	db := database.DB.Table("cards").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Joins("JOIN board_members ON board_members.board_id = lists.board_id AND board_members.user_id = ? AND board_members.status = ?", userID, "active").
+		Where("cards.deleted_at IS NULL AND cards.archived_at IS NULL")
+
+	if query == "" {
+		db = db.Select("cards.id, lists.board_id, cards.updated_at, CAST(0 AS real) AS score")
+	} else {
+		db = db.Select("cards.id, lists.board_id, cards.updated_at, CAST(ts_rank(cards.search_vector, query) + "+
+			"COALESCE(comment_match.rank, 0) + COALESCE(attachment_match.rank, 0) AS real) AS score").
+			Joins("CROSS JOIN plainto_tsquery('english', ?) AS query", query).
+			Joins("LEFT JOIN LATERAL (SELECT max(ts_rank(comments.search_vector, query)) AS rank FROM comments " +
+				"WHERE comments.card_id = cards.id AND comments.deleted_at IS NULL AND comments.search_vector @@ query) AS comment_match ON true").
+			Joins("LEFT JOIN LATERAL (SELECT max(ts_rank(attachments.search_vector, query)) AS rank FROM attachments " +
+				"WHERE attachments.card_id = cards.id AND attachments.deleted_at IS NULL AND attachments.search_vector @@ query) AS attachment_match ON true")
+	}
+
+	if condition, args := filter.Query.Condition(search.Context{UserID: userID, Now: time.Now()}); condition != "" {
+		db = db.Where(condition, args...)
+	}
+
+	if filter.BoardID != 0 {
+		db = db.Where("lists.board_id = ?", filter.BoardID)
+	}
+	if filter.ListID != 0 {
+		db = db.Where("cards.list_id = ?", filter.ListID)
+	}
+	if filter.LabelID != 0 {
+		db = db.Where("EXISTS (SELECT 1 FROM card_labels WHERE card_labels.card_id = cards.id AND card_labels.label_id = ?)", filter.LabelID)
+	}
+	if filter.MemberID != 0 {
+		db = db.Where("EXISTS (SELECT 1 FROM card_members WHERE card_members.card_id = cards.id AND card_members.user_id = ?)", filter.MemberID)
+	}
+	if filter.HasDueDate != nil {
+		if *filter.HasDueDate {
+			db = db.Where("cards.due_date IS NOT NULL")
+		} else {
+			db = db.Where("cards.due_date IS NULL")
+		}
+	}
+
+	return db
+}
+
+// highlight fills in the matching snippets for a page of hits
+func (ss *SearchService) highlight(hits []SearchHit, query string) error {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.CardID
+	}
+
+	var rows []struct {
+		ID          uint
+		Title       string
+		Description string
+		Comment     *string
+		Attachment  *string
+	}
+	if err := database.DB.Table("cards").
+		Select("cards.id, "+
+			"ts_headline('english', cards.title, query, ?) AS title, "+
+			"ts_headline('english', cards.description, query, ?) AS description, "+
+			"(SELECT ts_headline('english', comments.content, query, ?) FROM comments "+
+			"WHERE comments.card_id = cards.id AND comments.deleted_at IS NULL AND comments.search_vector @@ query "+
+			"ORDER BY ts_rank(comments.search_vector, query) DESC LIMIT 1) AS comment, "+
+			"(SELECT attachments.filename FROM attachments "+
+			"WHERE attachments.card_id = cards.id AND attachments.deleted_at IS NULL AND attachments.search_vector @@ query "+
+			"ORDER BY ts_rank(attachments.search_vector, query) DESC LIMIT 1) AS attachment",
+			titleHeadline, excerptHeadline, excerptHeadline).
+		Joins("CROSS JOIN plainto_tsquery('english', ?) AS query", query).
+		Where("cards.id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	byID := make(map[uint]map[string]string, len(rows))
+	for _, row := range rows {
+		highlights := map[string]string{}
+		if strings.Contains(row.Title, highlightStart) {
+			highlights["title"] = markHighlights(row.Title)
+		}
+		if strings.Contains(row.Description, highlightStart) {
+			highlights["description"] = markHighlights(row.Description)
+		}
+		if row.Comment != nil {
+			highlights["comment"] = markHighlights(*row.Comment)
+		}
+		if row.Attachment != nil {
+			// File names are matched on their split words, so show the whole name
+			highlights["attachment"] = html.EscapeString(*row.Attachment)
+		}
+		byID[row.ID] = highlights
+	}
+
+	for i := range hits {
+		hits[i].Highlights = byID[hits[i].CardID]
+	}
+	return nil
+}
+
+// markHighlights escapes a ts_headline snippet and turns its markers into <mark> tags
+func markHighlights(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
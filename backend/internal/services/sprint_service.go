@@ -0,0 +1,139 @@
+package services
+
+import (
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Where a sprint's story points come from
+const (
+	PointsField    = "field"    // The sprint's number custom field
+	PointsEstimate = "estimate" // Card estimates, in hours
+)
+
+// SprintDay is a sprint's state at the end of one day: the cards in scope and
+// those of them in a done-list, counted and in points
+type SprintDay struct {
+	Day         time.Time
+	Scope       int
+	Done        int
+	ScopePoints float64
+	DonePoints  float64
+}
+
+// SprintService computes sprint progress from card history
+type SprintService struct{}
+
+// PointsSource decides how a sprint's cards are sized: its points field if it
+// has one, otherwise card estimates if any card in scope has one. Returns ""
+// when cards can only be counted.
+func (ss *SprintService) PointsSource(db *gorm.DB, sprint *models.Sprint) (string, error) {
+	if sprint.PointsFieldID != nil {
+		return PointsField, nil
+	}
+
+	var estimated int64
+	err := db.Raw(`SELECT COUNT(*) FROM (`+sprintScopeSQL+`) scope
+		JOIN cards c ON c.id = scope.card_id
+		WHERE c.estimate_minutes IS NOT NULL AND c.deleted_at IS NULL`, sprintScopeArgs(sprint)...).Scan(&estimated).Error
+	if err != nil || estimated == 0 {
+		return "", err
+	}
+	return PointsEstimate, nil
+}
+
+// ScopeCards returns the cards currently in a sprint, in board order. Cards
+// in the trash are left out.
+func (ss *SprintService) ScopeCards(db *gorm.DB, sprint *models.Sprint) ([]models.Card, error) {
+	var cards []models.Card
+	err := db.Joins("JOIN lists ON lists.id = cards.list_id").
+		Where("cards.id IN (SELECT card_id FROM ("+sprintScopeSQL+") scope)", sprintScopeArgs(sprint)...).
+		Order("lists.rank ASC, cards.rank ASC, cards.id ASC").
+		Find(&cards).Error
+	return cards, err
+}
+
+// sprintScopeSQL selects the cards currently in a sprint. Bind with
+// sprintScopeArgs.
+const sprintScopeSQL = `
+	SELECT card_id FROM sprint_cards WHERE sprint_id = ?
+	UNION
+	SELECT card_id FROM card_labels WHERE label_id = ?`
+
+// sprintSpansSQL selects every period a card spent in a sprint, explicitly
+// or through its label. Bind with sprintScopeArgs.
+const sprintSpansSQL = `
+	SELECT card_id, added_at, removed_at FROM sprint_card_spans WHERE sprint_id = ?
+	UNION ALL
+	SELECT card_id, added_at, removed_at FROM card_label_spans WHERE label_id = ?`
+
+func sprintScopeArgs(sprint *models.Sprint) []interface{} {
+	var labelID uint
+	if sprint.LabelID != nil {
+		labelID = *sprint.LabelID
+	}
+	return []interface{}{sprint.ID, labelID}
+}
+
+// Progress replays a sprint day by day up to the earlier of its end and
+// today. A card is in scope at the end of a day if it was in the sprint then,
+// explicitly or through its label, and was not in the trash. It is done if
+// the last list it entered by then is a done-list. Points are the cards'
+// current values.
+func (ss *SprintService) Progress(db *gorm.DB, sprint *models.Sprint, pointsSource string, now time.Time) ([]SprintDay, error) {
+	last := sprint.EndDate
+	if today := now.UTC().Truncate(24 * time.Hour); today.Before(last) {
+		last = today
+	}
+	if last.Before(sprint.StartDate) {
+		return nil, nil
+	}
+
+	points := "NULL::float8"
+	var pointsArgs []interface{}
+	switch pointsSource {
+	case PointsField:
+		points = "(SELECT v.number_value FROM card_field_values v WHERE v.card_id = c.id AND v.field_id = ?)"
+		pointsArgs = append(pointsArgs, *sprint.PointsFieldID)
+	case PointsEstimate:
+		points = "c.estimate_minutes / 60.0"
+	}
+
+	args := []interface{}{dateParam(sprint.StartDate), dateParam(last)}
+	args = append(args, pointsArgs...)
+	args = append(args, sprintScopeArgs(sprint)...)
+
+	// day_end is the end of each day as a timestamptz
+	var days []SprintDay
+	err := db.Raw(`SELECT days.day,
+			COUNT(s.card_id) AS scope,
+			COUNT(s.card_id) FILTER (WHERE latest.is_done) AS done,
+			COALESCE(SUM(s.points), 0) AS scope_points,
+			COALESCE(SUM(s.points) FILTER (WHERE latest.is_done), 0) AS done_points
+		FROM generate_series(CAST(? AS date)::timestamp, CAST(? AS date)::timestamp, interval '1 day') AS days(day)
+		CROSS JOIN LATERAL (SELECT (days.day + interval '1 day') AT TIME ZONE 'UTC' AS day_end) bounds
+		LEFT JOIN LATERAL (
+			SELECT c.id AS card_id, `+points+` AS points
+			FROM cards c
+			WHERE c.created_at < bounds.day_end
+				AND (c.deleted_at IS NULL OR c.deleted_at >= bounds.day_end)
+				AND c.id IN (
+					SELECT spans.card_id FROM (`+sprintSpansSQL+`) spans
+					WHERE spans.added_at < bounds.day_end
+						AND (spans.removed_at IS NULL OR spans.removed_at >= bounds.day_end)
+				)
+		) s ON true
+		LEFT JOIN LATERAL (
+			SELECT l.is_done
+			FROM card_transitions t
+			JOIN lists l ON l.id = t.to_list_id
+			WHERE t.card_id = s.card_id AND t.moved_at < bounds.day_end
+			ORDER BY t.moved_at DESC, t.id DESC
+			LIMIT 1
+		) latest ON true
+		GROUP BY days.day
+		ORDER BY days.day ASC`, args...).Scan(&days).Error
+	return days, err
+}
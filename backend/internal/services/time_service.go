@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTimerRunning is returned when a user starts a timer while another runs
+var ErrTimerRunning = errors.New("you already have a timer running")
+
+// ErrNoTimer is returned when stopping a timer the user does not have
+var ErrNoTimer = errors.New("no timer is running on this card")
+
+// ErrInvalidTimeRange is returned for a time entry that ends before it starts
+var ErrInvalidTimeRange = errors.New("time entry must end after it starts")
+
+// entrySeconds is the SQL length of a time entry in seconds. Running timers
+// count up to the time bound as the first argument.
+const entrySeconds = "COALESCE(SUM(EXTRACT(EPOCH FROM (COALESCE(time_entries.ended_at, ?) - time_entries.started_at))), 0)::bigint"
+
+// TimeTotal is the time one user tracked
+type TimeTotal struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Seconds  int64  `json:"seconds"`
+}
+
+// TimesheetRow is the time one user tracked on one card on one day
+type TimesheetRow struct {
+	Day       time.Time
+	UserID    uint
+	Username  string
+	CardID    uint
+	CardTitle string
+	Seconds   int64
+}
+
+// TimeService tracks time spent on cards
+type TimeService struct{}
+
+// StartTimer starts a timer for userID on cardID. A user runs one timer at a
+// time; a partial unique index makes concurrent starts safe. When a timer is
+// already running it is returned with ErrTimerRunning.
+func (ts *TimeService) StartTimer(db *gorm.DB, cardID, userID uint, now time.Time) (*models.TimeEntry, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		entry := models.TimeEntry{CardID: cardID, UserID: userID, StartedAt: now}
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return &entry, nil
+		}
+
+		running, err := ts.RunningTimer(db, userID)
+		if err != nil {
+			return nil, err
+		}
+		// The running timer may have stopped since the insert; try again
+		if running != nil {
+			return running, ErrTimerRunning
+		}
+	}
+	return nil, ErrTimerRunning
+}
+
+// StopTimer stops userID's timer on cardID
+func (ts *TimeService) StopTimer(db *gorm.DB, cardID, userID uint, now time.Time) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("card_id = ? AND user_id = ? AND ended_at IS NULL", cardID, userID).
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoTimer
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A timer started in the future by clock skew stops at zero length
+	if now.Before(entry.StartedAt) {
+		now = entry.StartedAt
+	}
+	entry.EndedAt = &now
+	if err := db.Model(&entry).Update("ended_at", now).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RunningTimer returns userID's running timer, or nil when there is none
+func (ts *TimeService) RunningTimer(db *gorm.DB, userID uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := db.Where("user_id = ? AND ended_at IS NULL", userID).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CardTotals returns the time tracked on a card per user, running timers
+// counted up to now
+func (ts *TimeService) CardTotals(db *gorm.DB, cardID uint, now time.Time) ([]TimeTotal, error) {
+	var totals []TimeTotal
+	err := db.Table("time_entries").
+		Select("time_entries.user_id, users.username, "+entrySeconds+" AS seconds", now).
+		Joins("JOIN users ON users.id = time_entries.user_id").
+		Where("time_entries.card_id = ?", cardID).
+		Group("time_entries.user_id, users.username").
+		Order("users.username ASC").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// Timesheet returns the time tracked on a board's cards per user, card and
+// UTC day, for entries started in [from, to). Cards in the trash still
+// count. userID narrows it to one user when non-zero.
+func (ts *TimeService) Timesheet(db *gorm.DB, boardID uint, from, to time.Time, userID uint, now time.Time) ([]TimesheetRow, error) {
+	query := db.Table("time_entries").
+		Select("(time_entries.started_at AT TIME ZONE 'UTC')::date AS day, time_entries.user_id, users.username, "+
+			"time_entries.card_id, cards.title AS card_title, "+entrySeconds+" AS seconds", now).
+		Joins("JOIN cards ON cards.id = time_entries.card_id").
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Joins("JOIN users ON users.id = time_entries.user_id").
+		Where("lists.board_id = ? AND time_entries.started_at >= ? AND time_entries.started_at < ?", boardID, from, to)
+	if userID != 0 {
+		query = query.Where("time_entries.user_id = ?", userID)
+	}
+
+	var rows []TimesheetRow
+	err := query.
+		Group("day, time_entries.user_id, users.username, time_entries.card_id, cards.title").
+		Order("users.username ASC, day ASC, cards.title ASC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TrashService moves records to the trash and permanently removes expired ones
+type TrashService struct{}
+
+// MoveToTrash soft-deletes record inside tx, remembering who deleted it
+func (ts *TrashService) MoveToTrash(tx *gorm.DB, record interface{}, userID uint) error {
+	if err := tx.Model(record).UpdateColumn("deleted_by", userID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(record).Error
+}
+
+// PurgeExpired hard-deletes every trashed record deleted before the retention window
+func (ts *TrashService) PurgeExpired(retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+	var purged int64
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Lists first: their cards go with them
+		var listIDs []uint
+		if err := tx.Unscoped().Model(&models.List{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Pluck("id", &listIDs).Error; err != nil {
+			return err
+		}
+		if err := ts.PurgeLists(tx, listIDs); err != nil {
+			return err
+		}
+
+		var cardIDs []uint
+		if err := tx.Unscoped().Model(&models.Card{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Pluck("id", &cardIDs).Error; err != nil {
+			return err
+		}
+		if err := ts.PurgeCards(tx, cardIDs); err != nil {
+			return err
+		}
+
+		comments := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Delete(&models.Comment{})
+		if comments.Error != nil {
+			return comments.Error
+		}
+
+		attachments := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Delete(&models.Attachment{})
+		if attachments.Error != nil {
+			return attachments.Error
+		}
+
+		purged = int64(len(listIDs)+len(cardIDs)) + comments.RowsAffected + attachments.RowsAffected
+		return nil
+	})
+
+	return purged, err
+}
+
+// PurgeLists hard-deletes lists together with all of their cards
+func (ts *TrashService) PurgeLists(tx *gorm.DB, listIDs []uint) error {
+	if len(listIDs) == 0 {
+		return nil
+	}
+
+	var cardIDs []uint
+	if err := tx.Unscoped().Model(&models.Card{}).
+		Where("list_id IN ?", listIDs).
+		Pluck("id", &cardIDs).Error; err != nil {
+		return err
+	}
+	if err := ts.PurgeCards(tx, cardIDs); err != nil {
+		return err
+	}
+
+	// Recurring cards elsewhere can no longer be copied into these lists
+	if err := tx.Where("target_list_id IN ?", listIDs).Delete(&models.CardRecurrence{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Where("id IN ?", listIDs).Delete(&models.List{}).Error
+}
+
+// PurgeCards hard-deletes cards and every row that references them.
+// Attachment files are left for RemoveOrphanedUploads to clean up.
+func (ts *TrashService) PurgeCards(tx *gorm.DB, cardIDs []uint) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+
+	dependents := []interface{}{
+		&models.CardMember{},
+		&models.CardLabel{},
+		&models.Comment{},
+		&models.Attachment{},
+		&models.CardFieldValue{},
+		&models.CardRecurrence{},
+		&models.AutomationRun{},
+		&models.TimeEntry{},
+		&models.CardTransition{},
+		&models.SprintCard{},
+		&models.SprintCardSpan{},
+		&models.CardLabelSpan{},
+		&models.CardRevision{},
+	}
+	for _, model := range dependents {
+		if err := tx.Unscoped().Where("card_id IN ?", cardIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("source_card_id IN ? OR target_card_id IN ?", cardIDs, cardIDs).Delete(&models.CardRelation{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Where("id IN ?", cardIDs).Delete(&models.Card{}).Error
+}
+
+// RemoveOrphanedUploads deletes files in dir that no attachment row references.
+// Files younger than minAge are skipped so in-flight uploads are not removed.
+func (ts *TrashService) RemoveOrphanedUploads(dir string, minAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	// Trashed attachments still own their files until they are purged
+	var fileURLs []string
+	if err := database.DB.Unscoped().Model(&models.Attachment{}).
+		Pluck("file_url", &fileURLs).Error; err != nil {
+		return 0, err
+	}
+
+	referenced := make(map[string]bool, len(fileURLs))
+	for _, fileURL := range fileURLs {
+		referenced[filepath.Base(fileURL)] = true
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == ".gitkeep" || referenced[entry.Name()] {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < minAge {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			log.Printf("Warning: Could not delete orphaned file %s: %v", entry.Name(), err)
+			continue
+		}
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Due buckets of the "my work" view, in display order
+const (
+	DueOverdue  = "overdue"
+	DueToday    = "today"
+	DueThisWeek = "this_week"
+	DueLater    = "later"
+	DueNoDate   = "no_date"
+)
+
+// DueBuckets lists the due buckets in display order
+var DueBuckets = []string{DueOverdue, DueToday, DueThisWeek, DueLater, DueNoDate}
+
+// mentionBatches bounds how many pages of candidate comments Mentions reads
+const mentionBatches = 5
+
+// WorkService gathers a user's work across every board they are an active
+// member of
+type WorkService struct{}
+
+// MemberBoards scopes a query on a table joined to boards as boards.id to the
+// boards userID is an active member of
+func MemberBoards(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Joins("JOIN board_members ON board_members.board_id = boards.id AND board_members.user_id = ? AND board_members.status = ?", userID, "active")
+}
+
+// AssignedCards returns the open cards assigned to userID, soonest due first.
+// Cards that are archived, trashed, in a done-list or have their due date
+// marked complete are left out.
+func (ws *WorkService) AssignedCards(db *gorm.DB, userID uint) ([]models.Card, error) {
+	var cards []models.Card
+	err := MemberBoards(db.
+		Joins("JOIN card_members ON card_members.card_id = cards.id AND card_members.user_id = ?", userID).
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL AND NOT lists.is_done").
+		Joins("JOIN boards ON boards.id = lists.board_id AND boards.deleted_at IS NULL"), userID).
+		Where("cards.archived_at IS NULL AND NOT cards.due_complete").
+		Preload("List.Board").
+		Preload("Labels").
+		Order("cards.due_date ASC NULLS LAST, cards.id ASC").
+		Find(&cards).Error
+	return cards, err
+}
+
+// DueBucket sorts a due date into a bucket. Days and weeks (Monday to
+// Sunday) are taken in loc.
+func (ws *WorkService) DueBucket(due *time.Time, now time.Time, loc *time.Location) string {
+	if due == nil {
+		return DueNoDate
+	}
+	if due.Before(now) {
+		return DueOverdue
+	}
+
+	local := now.In(loc)
+	tomorrow := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+	if due.Before(tomorrow) {
+		return DueToday
+	}
+	daysToMonday := (8 - int(local.Weekday())) % 7
+	if daysToMonday == 0 {
+		daysToMonday = 7
+	}
+	nextWeek := time.Date(local.Year(), local.Month(), local.Day()+daysToMonday, 0, 0, 0, 0, loc)
+	if due.Before(nextWeek) {
+		return DueThisWeek
+	}
+	return DueLater
+}
+
+// Mentions returns the most recent comments by others that mention user as
+// @username, on cards of boards the user belongs to
+func (ws *WorkService) Mentions(db *gorm.DB, user *models.User, limit int) ([]models.Comment, error) {
+	pattern := "%@" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(user.Username) + "%"
+	mention := regexp.MustCompile(`(?i)(^|[^\w])@` + regexp.QuoteMeta(user.Username) + `(\W|$)`)
+
+	var mentions []models.Comment
+	for batches, offset := 0, 0; len(mentions) < limit && batches < mentionBatches; batches, offset = batches+1, offset+limit {
+		// ILIKE finds candidates; the word boundary check rules out longer names
+		var batch []models.Comment
+		err := MemberBoards(db.
+			Joins("JOIN cards ON cards.id = comments.card_id AND cards.deleted_at IS NULL").
+			Joins("JOIN lists ON lists.id = cards.list_id").
+			Joins("JOIN boards ON boards.id = lists.board_id AND boards.deleted_at IS NULL"), user.ID).
+			Where("comments.user_id <> ? AND comments.content ILIKE ?", user.ID, pattern).
+			Preload("User").
+			Preload("Card.List").
+			Order("comments.created_at DESC, comments.id DESC").
+			Offset(offset).
+			Limit(limit).
+			Find(&batch).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, comment := range batch {
+			if mention.MatchString(comment.Content) && len(mentions) < limit {
+				mentions = append(mentions, comment)
+			}
+		}
+		if len(batch) < limit {
+			break
+		}
+	}
+	return mentions, nil
+}
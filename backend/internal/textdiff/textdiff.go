@@ -0,0 +1,124 @@
+// Package textdiff compares two versions of a text line by line
+package textdiff
+
+import "strings"
+
+// Line operations
+const (
+	Equal  = "equal"
+	Insert = "insert"
+	Delete = "delete"
+)
+
+// maxCells bounds the comparison table. Texts with more line pairs than this
+// are shown as the old text deleted and the new one inserted.
+const maxCells = 4_000_000
+
+// Line is one line of a diff: kept from both texts, only in the new text
+// (Insert) or only in the old one (Delete)
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the shortest edit turning old into new, as the lines of both
+// texts in order. Deleted lines come before the lines inserted in their place.
+func Lines(old, new string) []Line {
+	a, b := split(old), split(new)
+
+	// Lines shared at the ends need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var diff []Line
+	for _, text := range a[:prefix] {
+		diff = append(diff, Line{Op: Equal, Text: text})
+	}
+	diff = append(diff, middle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		diff = append(diff, Line{Op: Equal, Text: text})
+	}
+	return diff
+}
+
+// Stats counts the inserted and deleted lines of a diff
+func Stats(diff []Line) (inserted, deleted int) {
+	for _, line := range diff {
+		switch line.Op {
+		case Insert:
+			inserted++
+		case Delete:
+			deleted++
+		}
+	}
+	return inserted, deleted
+}
+
+// middle diffs the differing part of two texts through their longest common
+// subsequence of lines
+func middle(a, b []string) []Line {
+	if len(a)*len(b) > maxCells {
+		return replace(a, b)
+	}
+
+	// lcs[i][j] is the common subsequence length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []Line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	return append(diff, replace(a[i:], b[j:])...)
+}
+
+// replace deletes every line of a and inserts every line of b
+func replace(a, b []string) []Line {
+	diff := make([]Line, 0, len(a)+len(b))
+	for _, text := range a {
+		diff = append(diff, Line{Op: Delete, Text: text})
+	}
+	for _, text := range b {
+		diff = append(diff, Line{Op: Insert, Text: text})
+	}
+	return diff
+}
+
+// split breaks text into lines. An empty text has no lines, and a trailing
+// newline does not start another one.
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -1,23 +1,73 @@
 package utils
 
 import (
-	"encoding/json"
+	"reflect"
+	"time"
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/events"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
 )
 
-// LogActivity creates an activity log entry
-func LogActivity(action, entityType string, entityID, boardID, userID uint, entityTitle string, metadata map[string]interface{}) error {
-	// Convert metadata to JSON
-	metadataJSON := ""
-	if metadata != nil {
-		bytes, err := json.Marshal(metadata)
-		if err == nil {
-			metadataJSON = string(bytes)
+// FieldChanges collects the fields an edit changed, for LogChanges
+type FieldChanges []models.FieldChange
+
+// Add records a field if its value changed. Pointers are compared by what
+// they point to, so nil and a pointer to a value differ but two pointers to
+// equal values do not; times are compared as instants.
+func (fc *FieldChanges) Add(field string, from, to interface{}) {
+	from, to = derefValue(from), derefValue(to)
+	if fromTime, ok := from.(time.Time); ok {
+		if toTime, ok := to.(time.Time); ok && fromTime.Equal(toTime) {
+			return
+		}
+	} else if reflect.DeepEqual(from, to) {
+		return
+	}
+	*fc = append(*fc, models.FieldChange{Field: field, From: from, To: to})
+}
+
+// derefValue follows pointers, returning nil for a nil one
+func derefValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
 		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// LogActivity creates an activity log entry. details is context for the
+// action, such as the card a label was added to; it may be nil.
+func LogActivity(action, entityType string, entityID, boardID, userID uint, entityTitle string, details map[string]interface{}) error {
+	return logActivity(nil, action, entityType, entityID, boardID, userID, entityTitle, models.ActivityMetadata{Details: details})
+}
+
+// LogChainedActivity is LogActivity for an activity caused by automation
+// rules. chain is the rules' chain, so rules the activity triggers count
+// towards the loop limit.
+func LogChainedActivity(chain []uint, action, entityType string, entityID, boardID, userID uint, entityTitle string, details map[string]interface{}) error {
+	return logActivity(chain, action, entityType, entityID, boardID, userID, entityTitle, models.ActivityMetadata{Details: details})
+}
+
+// LogChanges creates an activity log entry for an edit, with the before and
+// after value of each changed field. Nothing is logged when nothing changed.
+func LogChanges(action, entityType string, entityID, boardID, userID uint, entityTitle string, changes FieldChanges, details map[string]interface{}) error {
+	if len(changes) == 0 {
+		return nil
 	}
+	return logActivity(nil, action, entityType, entityID, boardID, userID, entityTitle, models.ActivityMetadata{
+		Changes: changes,
+		Details: details,
+	})
+}
 
+func logActivity(chain []uint, action, entityType string, entityID, boardID, userID uint, entityTitle string, metadata models.ActivityMetadata) error {
 	activity := models.Activity{
 		Action:      action,
 		EntityType:  entityType,
@@ -25,8 +75,19 @@ func LogActivity(action, entityType string, entityID, boardID, userID uint, enti
 		EntityTitle: entityTitle,
 		BoardID:     boardID,
 		UserID:      userID,
-		Metadata:    metadataJSON,
+		Metadata:    metadata,
+	}
+
+	if err := database.DB.Create(&activity).Error; err != nil {
+		return err
 	}
 
-	return database.DB.Create(&activity).Error
+	events.Publish(events.Event{
+		BoardID: boardID,
+		Source:  events.SourceActivity,
+		Type:    action,
+		Data:    activity,
+		Chain:   chain,
+	})
+	return nil
 }
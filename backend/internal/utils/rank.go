@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// rankDigits are the base-36 digits rank keys are made of. Keys compare
+// byte-wise, so rank columns must use the "C" collation.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const rankBase = len(rankDigits)
+
+// ErrRankOrder is returned when the lower bound is not below the upper bound
+var ErrRankOrder = errors.New("rank: lower bound must sort before upper bound")
+
+// RankBetween returns a key that sorts strictly between prev and next.
+// An empty prev means "before everything", an empty next "after everything".
+// Keys never end in '0', so there is always room to insert before any key.
+func RankBetween(prev, next string) (string, error) {
+	if next != "" && prev >= next {
+		return "", ErrRankOrder
+	}
+	return rankMidpoint(prev, next), nil
+}
+
+// rankMidpoint finds the shortest key between a and b (b == "" is +infinity)
+func rankMidpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, treating a as padded with zeros
+		n := 0
+		for n < len(b) && rankDigitAt(a, n) == rankDigit(b[n]) {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + rankMidpoint(rest, b[n:])
+		}
+	}
+
+	digitA := rankDigitAt(a, 0)
+	digitB := rankBase
+	if b != "" {
+		digitB = rankDigit(b[0])
+	}
+
+	// Room for a digit strictly in between
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB)/2])
+	}
+
+	// Digits are consecutive: b's first digit alone already sorts after a
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	// Otherwise keep a's first digit and go one level deeper
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(rankDigits[digitA]) + rankMidpoint(rest, "")
+}
+
+// RankSequence returns n evenly spaced, increasing keys. Used when
+// (re)numbering a whole list so later inserts start with short keys.
+func RankSequence(n int) []string {
+	if n <= 0 {
+		return []string{}
+	}
+
+	// Pick a width with plenty of headroom between neighbours
+	width := 2
+	capacity := rankBase * rankBase
+	for capacity/(n+1) < rankBase {
+		width++
+		capacity *= rankBase
+	}
+	step := capacity / (n + 1)
+
+	keys := make([]string, n)
+	for i := 0; i < n; i++ {
+		keys[i] = rankEncode((i+1)*step, width)
+	}
+	return keys
+}
+
+// rankEncode writes value in base 36 using exactly width digits, minus trailing zeros
+func rankEncode(value, width int) string {
+	digits := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		digits[i] = rankDigits[value%rankBase]
+		value /= rankBase
+	}
+	return strings.TrimRight(string(digits), "0")
+}
+
+// rankDigit returns the numeric value of a key character
+func rankDigit(c byte) int {
+	return strings.IndexByte(rankDigits, c)
+}
+
+// rankDigitAt returns the digit at position i, or 0 past the end of the key
+func rankDigitAt(key string, i int) int {
+	if i >= len(key) {
+		return 0
+	}
+	return rankDigit(key[i])
+}
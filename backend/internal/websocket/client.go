@@ -1,7 +1,9 @@
 package websocket
 
 import (
+	"encoding/json"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -17,8 +19,9 @@ const (
 	// Send pings to peer with this period (must be less than pongWait)
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	// Maximum message size allowed from peer, enough for a command with a
+	// full-length comment
+	maxMessageSize = 32 * 1024
 )
 
 // Client represents a WebSocket client
@@ -26,28 +29,155 @@ type Client struct {
 	Hub     *Hub
 	Conn    *websocket.Conn
 	Send    chan []byte
-	BoardID uint
 	UserID  uint
+
+	// Shown to other viewers of the client's boards
+	Username string
+
+	// Protects boards, focus, lastActive and closed
+	mu sync.Mutex
+
+	// Subscribed boards (boardID -> sequence number of the board's last event
+	// when the client joined, which it was sent then)
+	boards map[uint]uint64
+
+	// Card open on each board (boardID -> cardID)
+	focus map[uint]uint
+
+	// When the client last sent a message
+	lastActive time.Time
+
+	// Whether Send is closed
+	closed bool
 }
 
-// NewClient creates a new WebSocket client
-func NewClient(hub *Hub, conn *websocket.Conn, boardID, userID uint) *Client {
+// NewClient creates a new WebSocket client, subscribed to no boards
+func NewClient(hub *Hub, conn *websocket.Conn, userID uint, username string) *Client {
 	return &Client{
-		Hub:     hub,
-		Conn:    conn,
-		Send:    make(chan []byte, 256),
-		BoardID: boardID,
-		UserID:  userID,
+		Hub:        hub,
+		Conn:       conn,
+		Send:       make(chan []byte, 256),
+		UserID:     userID,
+		Username:   username,
+		boards:     make(map[uint]uint64),
+		focus:      make(map[uint]uint),
+		lastActive: time.Now(),
+	}
+}
+
+// deliver reports whether a board message should be sent to the client: it
+// is subscribed to the board and was not already sent the event when it
+// joined. Events published by other instances may arrive out of sequence
+// order, so later events do not hide earlier ones.
+func (c *Client) deliver(boardID uint, seq uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	joined, subscribed := c.boards[boardID]
+	return subscribed && (seq == 0 || seq > joined)
+}
+
+// queue adds a message to the send buffer without blocking. It returns false
+// when the buffer is full or closed.
+func (c *Client) queue(message []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	select {
+	case c.Send <- message:
+		return true
+	default:
+		return false
 	}
 }
 
+// send queues a message, returning false when it cannot be sent
+func (c *Client) send(message interface{}) bool {
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return false
+	}
+	return c.queue(messageJSON)
+}
 
+// reply queues a reply to one of the client's messages, closing the
+// connection if the client is too slow to take it
+func (c *Client) reply(reply Reply) {
+	if !c.send(reply) {
+		c.close()
+	}
+}
+
+// touch records that the client sent a message and returns the boards it is
+// subscribed to if it had been idle longer than idleAfter
+func (c *Client) touch(idleAfter time.Duration) []uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wasIdle := time.Since(c.lastActive) > idleAfter
+	c.lastActive = time.Now()
+	if !wasIdle {
+		return nil
+	}
+	boardIDs := make([]uint, 0, len(c.boards))
+	for boardID := range c.boards {
+		boardIDs = append(boardIDs, boardID)
+	}
+	return boardIDs
+}
+
+// setFocus records the card the client has open on a board, nil for none
+func (c *Client) setFocus(boardID uint, cardID *uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cardID == nil {
+		delete(c.focus, boardID)
+	} else {
+		c.focus[boardID] = *cardID
+	}
+}
+
+// subscribed reports whether the client is subscribed to a board
+func (c *Client) subscribed(boardID uint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.boards[boardID]
+	return ok
+}
+
+// subscriptions counts the boards the client is subscribed to
+func (c *Client) subscriptions() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.boards)
+}
+
+// close closes the send channel once, which ends WritePump
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
+	}
+}
+
+
+// ReadPump reads commands from the WebSocket connection until it closes
 func (c *Client) ReadPump() {
 	defer func() {
 		c.Hub.Unregister(c)
 		c.Conn.Close()
 	}()
 
+	// Larger frames fail the read, which closes the connection
+	c.Conn.SetReadLimit(maxMessageSize)
 	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.Conn.SetPongHandler(func(string) error {
 		c.Conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -55,13 +185,14 @@ func (c *Client) ReadPump() {
 	})
 
 	for {
-		_, _, err := c.Conn.ReadMessage()
+		_, message, err := c.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
+		c.Hub.handle(c, message)
 	}
 }
 
@@ -82,20 +213,9 @@ func (c *Client) WritePump() {
 				return
 			}
 
-			w, err := c.Conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
-			}
-			w.Write(message)
-
-			// Add queued messages to the current WebSocket message
-			n := len(c.Send)
-			for i := 0; i < n; i++ {
-				w.Write([]byte{'\n'})
-				w.Write(<-c.Send)
-			}
-
-			if err := w.Close(); err != nil {
+			// One message per frame, so each frame parses as JSON even when
+			// events were replayed in a burst
+			if err := c.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
 				return
 			}
 
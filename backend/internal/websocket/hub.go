@@ -1,74 +1,200 @@
 package websocket
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"log"
 	"sync"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/events"
 )
 
 // Hub maintains active WebSocket connections and broadcasts messages
 type Hub struct {
-	// Registered clients (boardID -> list of clients)
+	// Subscribed clients (boardID -> list of clients)
 	boards map[uint]map[*Client]bool
 
-	// Register requests from clients
-	register chan *Client
+	// Subscribe requests from clients
+	subscribe chan subscription
+
+	// Unsubscribe requests from clients
+	unsubscribe chan subscription
 
 	// Unregister requests from clients
 	unregister chan *Client
 
+	// Boards and users whose presence may have changed
+	presenceChanges chan subscription
+
+	// Presence last broadcast to each board (boardID -> userID -> viewer),
+	// owned by Run
+	presence map[uint]map[uint]Viewer
+
+	// Users this hub last told the other instances are viewing each board,
+	// owned by Run
+	published map[uint]map[uint]bool
+
+	// Viewers through other instances (boardID -> userID -> instance),
+	// written by Run under mu
+	remotePresence map[uint]map[uint]map[string]remoteViewer
+
+	// Presence updates waiting to be published to the other instances
+	presenceOut chan *Message
+
+	// Tells this hub's presence updates apart from other instances'
+	instance string
+
+	// How long a viewer may send nothing before they are shown as idle
+	IdleAfter time.Duration
+
 	// Broadcast messages to all clients in a board
 	broadcast chan *Message
 
 	// Mutex to protect concurrent access
 	mu sync.RWMutex
+
+	// Stores broadcasts for replay; nil disables sequence numbers
+	Events EventLog
+
+	// Checks subscriptions and runs client commands; nil rejects both
+	Commands CommandHandler
+
+	// Carries broadcasts to this hub and any others; set before Run
+	PubSub PubSub
+
+	// One lock per board, held while a broadcast is numbered and queued, so
+	// a board's messages are queued in sequence order and a joining client
+	// sees none twice. Boards do not wait on each other.
+	sequencing   map[uint]*sync.Mutex
+	sequencingMu sync.Mutex
+
+	// How far each board's numbered messages have been queued, for boards
+	// this hub's clients joined
+	order   map[uint]*boardOrder
+	orderMu sync.Mutex
+
+	// Closed by Stop
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// boardOrder is the last sequence number queued for a board's clients
+type boardOrder struct {
+	mu        sync.Mutex
+	delivered uint64
+}
+
+// subscription is a request to add a client to a board or remove it
+type subscription struct {
+	client  *Client
+	boardID uint
 }
 
 // Message represents a WebSocket message
 type Message struct {
 	Type    string      `json:"type"` // "card_moved", "card_created", "comment_added", etc.
 	BoardID uint        `json:"board_id"`
+	Seq     uint64      `json:"seq,omitempty"` // Board event sequence number, see EventLog
 	Data    interface{} `json:"data"`
 }
 
+// EventLog keeps each board's recent broadcasts, numbered in order, so that
+// reconnecting clients can catch up on what they missed
+type EventLog interface {
+	// Append stores a broadcast and returns its sequence number
+	Append(boardID uint, messageType string, data interface{}) (uint64, error)
+	// Latest returns the sequence number of the board's last broadcast
+	Latest(boardID uint) (uint64, error)
+	// Since returns up to limit of the board's broadcasts after seq and the
+	// latest sequence number. complete is false when some are not available.
+	Since(boardID uint, seq uint64, limit int) (messages []Message, latest uint64, complete bool, err error)
+}
+
+// Messages the hub sends to a joining client
+const (
+	MessageSynced = "synced"          // Caught up; data.seq is the board's latest event
+	MessageResync = "resync_required" // Missed events are not available; reload the board
+)
+
+// replayLimit is the most missed events replayed to a joining client. It
+// must leave room in the client's send buffer.
+const replayLimit = 200
+
 // NewHub creates a new Hub
 func NewHub() *Hub {
 	return &Hub{
-		boards:     make(map[uint]map[*Client]bool),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		broadcast:  make(chan *Message, 256),
+		boards:      make(map[uint]map[*Client]bool),
+		subscribe:   make(chan subscription),
+		unsubscribe: make(chan subscription),
+		unregister:  make(chan *Client),
+		broadcast:   make(chan *Message, 256),
+
+		presenceChanges: make(chan subscription, 256),
+		presence:        make(map[uint]map[uint]Viewer),
+		published:       make(map[uint]map[uint]bool),
+		remotePresence:  make(map[uint]map[uint]map[string]remoteViewer),
+		presenceOut:     make(chan *Message, 256),
+		instance:        newInstanceID(),
+		IdleAfter:       DefaultIdleAfter,
+		sequencing:      make(map[uint]*sync.Mutex),
+		order:           make(map[uint]*boardOrder),
+		stop:            make(chan struct{}),
+
+		PubSub: NewMemoryPubSub(),
 	}
 }
 
-// Run starts the hub
+// Run starts the hub. It returns once Stop is called.
 func (h *Hub) Run() {
+	h.PubSub.Subscribe(h.deliver)
+	go h.presencePublisher()
+
+	sweep := time.NewTicker(presenceSweep)
+	defer sweep.Stop()
+
 	for {
 		select {
-		case client := <-h.register:
+		case <-h.stop:
+			return
+
+		case sub := <-h.subscribe:
 			h.mu.Lock()
-			if h.boards[client.BoardID] == nil {
-				h.boards[client.BoardID] = make(map[*Client]bool)
+			if h.boards[sub.boardID] == nil {
+				h.boards[sub.boardID] = make(map[*Client]bool)
 			}
-			h.boards[client.BoardID][client] = true
+			h.boards[sub.boardID][sub.client] = true
 			h.mu.Unlock()
-			log.Printf(" Client registered for board %d. Total clients: %d", client.BoardID, len(h.boards[client.BoardID]))
+			log.Printf(" Client subscribed to board %d. Total clients: %d", sub.boardID, len(h.boards[sub.boardID]))
+			h.refreshPresence(sub.boardID, sub.client.UserID)
 
-		case client := <-h.unregister:
+		case sub := <-h.unsubscribe:
 			h.mu.Lock()
-			if clients, ok := h.boards[client.BoardID]; ok {
-				if _, ok := clients[client]; ok {
-					delete(clients, client)
-					close(client.Send)
-					if len(clients) == 0 {
-						delete(h.boards, client.BoardID)
-					}
-				}
-			}
+			h.leave(sub.client, sub.boardID)
 			h.mu.Unlock()
-			log.Printf("❌ Client unregistered from board %d", client.BoardID)
+			h.refreshPresence(sub.boardID, sub.client.UserID)
+
+		case sub := <-h.presenceChanges:
+			h.refreshPresence(sub.boardID, sub.client.UserID)
+
+		case <-sweep.C:
+			h.sweepPresence()
+
+		case client := <-h.unregister:
+			h.remove(client)
+			log.Printf("❌ Client of user %d unregistered", client.UserID)
 
 		case message := <-h.broadcast:
+			if message.Type == messagePresenceState {
+				h.receivePresence(message)
+				continue
+			}
+			if message.Type == messageRevoke {
+				h.revoke(message)
+				continue
+			}
+
 			h.mu.RLock()
 			clients := h.boards[message.BoardID]
 			h.mu.RUnlock()
@@ -80,22 +206,270 @@ func (h *Hub) Run() {
 			}
 
 			for client := range clients {
-				select {
-				case client.Send <- messageJSON:
-				default:
-					close(client.Send)
-					h.mu.Lock()
-					delete(h.boards[message.BoardID], client)
-					h.mu.Unlock()
+				if !client.deliver(message.BoardID, message.Seq) {
+					continue
 				}
+				if !client.queue(messageJSON) {
+					h.remove(client)
+				}
+			}
+		}
+	}
+}
+
+// newInstanceID picks a random ID for a hub's presence updates
+func newInstanceID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return time.Now().String()
+	}
+	return hex.EncodeToString(id)
+}
+
+// Stop ends Run and stops the hub publishing presence. Messages published
+// afterwards are dropped. The PubSub is left running.
+func (h *Hub) Stop() {
+	h.stopOnce.Do(func() { close(h.stop) })
+}
+
+// deliver queues a published message for this hub's clients. A board's
+// numbered messages are queued in sequence order, although notifications
+// from several instances can arrive out of order: one that skips ahead of
+// the last queued has the missing messages read from the event log and
+// queued first, and those already queued are dropped. Boards no client of
+// this hub has joined need no order.
+func (h *Hub) deliver(message *Message) {
+	order := h.boardOrder(message.BoardID)
+	if message.Seq == 0 || h.Events == nil || order == nil {
+		h.enqueue(message)
+		return
+	}
+
+	order.mu.Lock()
+	defer order.mu.Unlock()
+
+	switch {
+	case message.Seq <= order.delivered:
+		return
+
+	case message.Seq == order.delivered+1:
+		order.delivered = message.Seq
+		h.enqueue(message)
+		return
+	}
+
+	missed, latest, complete, err := h.Events.Since(message.BoardID, order.delivered, replayLimit)
+	if err != nil || !complete {
+		if err != nil {
+			log.Printf("Error loading events for board %d: %v", message.BoardID, err)
+		}
+		// The clients cannot be caught up; they must reload the board
+		h.enqueue(&Message{
+			Type:    MessageResync,
+			BoardID: message.BoardID,
+			Data:    map[string]interface{}{"seq": latest},
+		})
+		order.delivered = message.Seq
+		h.enqueue(message)
+		return
+	}
+	for i := range missed {
+		order.delivered = missed[i].Seq
+		h.enqueue(&missed[i])
+	}
+}
+
+// enqueue hands a message to Run, or drops it once the hub is stopped
+func (h *Hub) enqueue(message *Message) {
+	select {
+	case h.broadcast <- message:
+	case <-h.stop:
+	}
+}
+
+// boardOrder returns where delivery of a board's numbered messages has
+// reached, nil if no client of this hub has joined the board
+func (h *Hub) boardOrder(boardID uint) *boardOrder {
+	h.orderMu.Lock()
+	defer h.orderMu.Unlock()
+	return h.order[boardID]
+}
+
+// startOrder begins ordering a board's numbered messages after latest, the
+// last event a joining client was told of, unless they are already ordered
+func (h *Hub) startOrder(boardID uint, latest uint64) {
+	h.orderMu.Lock()
+	defer h.orderMu.Unlock()
+	if _, ok := h.order[boardID]; !ok {
+		h.order[boardID] = &boardOrder{delivered: latest}
+	}
+}
+
+// remove drops a client from all its boards and closes its send channel
+func (h *Hub) remove(client *Client) {
+	client.mu.Lock()
+	boardIDs := make([]uint, 0, len(client.boards))
+	for boardID := range client.boards {
+		boardIDs = append(boardIDs, boardID)
+	}
+	client.mu.Unlock()
+
+	h.mu.Lock()
+	for _, boardID := range boardIDs {
+		h.leave(client, boardID)
+	}
+	h.mu.Unlock()
+	client.close()
+
+	for _, boardID := range boardIDs {
+		h.refreshPresence(boardID, client.UserID)
+	}
+}
+
+// leave drops a client from one board. h.mu must be held.
+func (h *Hub) leave(client *Client, boardID uint) {
+	if clients, ok := h.boards[boardID]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.boards, boardID)
+		}
+	}
+}
+
+// Join subscribes a client to a board and tells it the board's latest event
+// sequence number in a synced message. With since set, the board's events
+// after it are queued for the client first, or a resync message is sent
+// instead when they are too many or no longer kept. It returns false when
+// the client is already subscribed to the board.
+func (h *Hub) Join(client *Client, boardID uint, since *uint64) bool {
+	sequencing := h.boardSequencing(boardID)
+	sequencing.Lock()
+	defer sequencing.Unlock()
+
+	client.mu.Lock()
+	_, subscribed := client.boards[boardID]
+	client.mu.Unlock()
+	if subscribed {
+		return false
+	}
+
+	var latest uint64
+	if h.Events != nil {
+		replayed, seq, complete, err := h.catchUp(boardID, since)
+		if err != nil {
+			log.Printf("Error loading events for board %d: %v", boardID, err)
+		}
+		status := MessageSynced
+		if !complete {
+			status, replayed = MessageResync, nil
+		}
+
+		queued := true
+		for _, message := range replayed {
+			if messageJSON, err := json.Marshal(message); err == nil {
+				queued = queued && client.queue(messageJSON)
 			}
 		}
+		statusJSON, _ := json.Marshal(Message{
+			Type:    status,
+			BoardID: boardID,
+			Data:    map[string]interface{}{"seq": seq, "replayed": len(replayed)},
+		})
+		if !queued || !client.queue(statusJSON) {
+			// Too slow to take the replay; the connection closes
+			client.close()
+			return true
+		}
+		latest = seq
+		h.startOrder(boardID, latest)
 	}
+
+	client.mu.Lock()
+	client.boards[boardID] = latest
+	client.mu.Unlock()
+	h.subscribe <- subscription{client: client, boardID: boardID}
+	return true
 }
 
-// Register registers a client to the hub
-func (h *Hub) Register(client *Client) {
-	h.register <- client
+// presenceChanged asks Run to broadcast any change in the client's user's
+// presence on a board. It never blocks; the periodic sweep catches up on a
+// dropped request.
+func (h *Hub) presenceChanged(client *Client, boardID uint) {
+	select {
+	case h.presenceChanges <- subscription{client: client, boardID: boardID}:
+	default:
+	}
+}
+
+// Leave unsubscribes a client from a board. The client stops receiving the
+// board's messages at once, before the hub drops it from the board.
+func (h *Hub) Leave(client *Client, boardID uint) {
+	client.mu.Lock()
+	delete(client.boards, boardID)
+	client.mu.Unlock()
+	h.unsubscribe <- subscription{client: client, boardID: boardID}
+}
+
+// RecheckAccess unsubscribes a user's connections to every instance from a
+// board if the user can no longer view it, as after they are removed from
+// it. Each connection is sent an access_revoked message.
+func (h *Hub) RecheckAccess(userID, boardID uint) {
+	if h.Commands != nil && h.Commands.CanView(userID, boardID) {
+		return
+	}
+	message := &Message{Type: messageRevoke, BoardID: boardID, Data: userID}
+	if err := h.PubSub.Publish(message); err != nil {
+		log.Printf("Error publishing revoked access to board %d: %v", boardID, err)
+	}
+}
+
+// revoke unsubscribes the connections of the user a revoke_access message
+// names from its board. It runs on the Run goroutine.
+func (h *Hub) revoke(message *Message) {
+	// The user ID is a number, or a float64 once it has been through a
+	// notification
+	var userID uint
+	raw, err := json.Marshal(message.Data)
+	if err == nil {
+		err = json.Unmarshal(raw, &userID)
+	}
+	if err != nil {
+		log.Printf("Invalid revoked access to board %d: %v", message.BoardID, err)
+		return
+	}
+
+	h.mu.Lock()
+	var clients []*Client
+	for client := range h.boards[message.BoardID] {
+		if client.UserID == userID {
+			clients = append(clients, client)
+			h.leave(client, message.BoardID)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		client.mu.Lock()
+		delete(client.boards, message.BoardID)
+		delete(client.focus, message.BoardID)
+		client.mu.Unlock()
+		if !client.send(Message{Type: MessageAccessRevoked, BoardID: message.BoardID}) {
+			client.close()
+		}
+	}
+	if len(clients) > 0 {
+		h.refreshPresence(message.BoardID, userID)
+	}
+}
+
+// catchUp loads the events a joining client missed. Without since there is
+// nothing to replay.
+func (h *Hub) catchUp(boardID uint, since *uint64) ([]Message, uint64, bool, error) {
+	if since == nil {
+		latest, err := h.Events.Latest(boardID)
+		return nil, latest, err == nil, err
+	}
+	return h.Events.Since(boardID, *since, replayLimit)
 }
 
 // Unregister unregisters a client from the hub
@@ -105,10 +479,46 @@ func (h *Hub) Unregister(client *Client) {
 
 // BroadcastToBoard sends a message to all clients in a board
 func (h *Hub) BroadcastToBoard(boardID uint, messageType string, data interface{}) {
+	h.BroadcastEvent(events.Event{BoardID: boardID, Type: messageType, Data: data})
+}
+
+// BroadcastEvent sends an event to all clients in its board and publishes it
+// to in-process subscribers. Automation uses it to keep the rule chain that
+// caused the event.
+func (h *Hub) BroadcastEvent(event events.Event) {
+	event.Source = events.SourceBroadcast
 	message := &Message{
-		Type:    messageType,
-		BoardID: boardID,
-		Data:    data,
+		Type:    event.Type,
+		BoardID: event.BoardID,
+		Data:    event.Data,
+	}
+
+	sequencing := h.boardSequencing(event.BoardID)
+	sequencing.Lock()
+	if h.Events != nil {
+		seq, err := h.Events.Append(event.BoardID, event.Type, event.Data)
+		if err != nil {
+			log.Printf("Error logging event for board %d: %v", event.BoardID, err)
+		}
+		message.Seq = seq
+	}
+	if err := h.PubSub.Publish(message); err != nil {
+		log.Printf("Error publishing event for board %d: %v", event.BoardID, err)
+	}
+	sequencing.Unlock()
+
+	events.Publish(event)
+}
+
+// boardSequencing returns the lock that orders a board's broadcasts
+func (h *Hub) boardSequencing(boardID uint) *sync.Mutex {
+	h.sequencingMu.Lock()
+	defer h.sequencingMu.Unlock()
+
+	sequencing, ok := h.sequencing[boardID]
+	if !ok {
+		sequencing = &sync.Mutex{}
+		h.sequencing[boardID] = sequencing
 	}
-	h.broadcast <- message
+	return sequencing
 }
@@ -0,0 +1,344 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"time"
+)
+
+// CommandFocus sets the card a client has open on a board; a null or
+// missing data.card_id clears it
+const CommandFocus = "focus"
+
+// Presence messages the hub sends to a board's clients. They are not
+// numbered or kept in the event log.
+const (
+	MessagePresenceJoined = "presence_joined" // A user started viewing the board; data is their Viewer
+	MessagePresenceLeft   = "presence_left"   // A user's last connection left the board
+	MessagePresenceFocus  = "presence_focus"  // A user opened another card, or closed it
+	MessagePresenceIdle   = "presence_idle"   // A user went idle or came back
+)
+
+// DefaultIdleAfter is how long a viewer may send nothing before they are
+// shown as idle
+const DefaultIdleAfter = 5 * time.Minute
+
+// presenceSweep is how often the hub looks for viewers who went idle and
+// repeats its own viewers to the other instances
+const presenceSweep = 15 * time.Second
+
+// presenceExpiry is how long another instance's viewer is shown without
+// being repeated, so the viewers of an instance that stopped disappear
+const presenceExpiry = 3 * presenceSweep
+
+// messagePresenceState carries a user's presence on a board through one
+// instance's hub to the others over PubSub. Clients never see it.
+const messagePresenceState = "presence_state"
+
+// presenceState is the data of a presence_state message. Viewer is nil once
+// the user has no connection to the instance subscribed to the board.
+type presenceState struct {
+	Instance string  `json:"instance"`
+	UserID   uint    `json:"user_id"`
+	Viewer   *Viewer `json:"viewer"`
+}
+
+// remoteViewer is a user's presence on a board through another instance
+type remoteViewer struct {
+	Viewer
+	expires time.Time
+}
+
+// Viewer is a user viewing a board, over one or more connections
+type Viewer struct {
+	UserID      uint      `json:"user_id"`
+	Username    string    `json:"username"`
+	CardID      *uint     `json:"card_id"` // Card open in the most recently active connection
+	Idle        bool      `json:"idle"`    // No connection sent anything within the idle timeout
+	Connections int       `json:"connections"`
+	LastActive  time.Time `json:"last_active"`
+}
+
+// Presence returns the users viewing a board through this instance or any
+// other sharing its PubSub, ordered by user ID
+func (h *Hub) Presence(boardID uint) []Viewer {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	userIDs := make(map[uint]bool)
+	for client := range h.boards[boardID] {
+		userIDs[client.UserID] = true
+	}
+	for userID := range h.remotePresence[boardID] {
+		userIDs[userID] = true
+	}
+
+	viewers := []Viewer{}
+	for userID := range userIDs {
+		if viewer, ok := h.viewer(boardID, userID); ok {
+			viewers = append(viewers, viewer)
+		}
+	}
+	sort.Slice(viewers, func(i, j int) bool { return viewers[i].UserID < viewers[j].UserID })
+	return viewers
+}
+
+// viewer returns a user's presence on a board across all instances, false
+// if they have no connection subscribed to it. h.mu must be held.
+func (h *Hub) viewer(boardID, userID uint) (Viewer, bool) {
+	viewer, viewing := h.localViewer(boardID, userID)
+	for _, remote := range h.remotePresence[boardID][userID] {
+		if time.Now().After(remote.expires) {
+			continue
+		}
+		if !viewing {
+			viewer, viewing = remote.Viewer, true
+			continue
+		}
+		viewer.Connections += remote.Connections
+		if remote.LastActive.After(viewer.LastActive) {
+			viewer.LastActive = remote.LastActive
+			viewer.CardID = remote.CardID
+		}
+	}
+	if !viewing {
+		return Viewer{}, false
+	}
+	viewer.Idle = time.Since(viewer.LastActive) > h.IdleAfter
+	return viewer, true
+}
+
+// localViewer returns a user's presence on a board through this hub's
+// connections, false if they have none. h.mu must be held.
+func (h *Hub) localViewer(boardID, userID uint) (Viewer, bool) {
+	var viewer *Viewer
+	for client := range h.boards[boardID] {
+		if client.UserID != userID {
+			continue
+		}
+		if viewer == nil {
+			viewer = &Viewer{UserID: userID, Username: client.Username}
+		}
+		h.addConnection(viewer, client, boardID)
+	}
+	if viewer == nil {
+		return Viewer{}, false
+	}
+	viewer.Idle = time.Since(viewer.LastActive) > h.IdleAfter
+	return *viewer, true
+}
+
+// addConnection counts one of a user's connections into their presence
+func (h *Hub) addConnection(viewer *Viewer, client *Client, boardID uint) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	viewer.Connections++
+	if viewer.Connections == 1 || client.lastActive.After(viewer.LastActive) {
+		viewer.LastActive = client.lastActive
+		viewer.CardID = nil
+		if cardID, ok := client.focus[boardID]; ok {
+			viewer.CardID = &cardID
+		}
+	}
+}
+
+// refreshPresence publishes a user's presence on a board through this hub
+// to the other instances, then shows any change to the board's clients. It
+// runs on the Run goroutine, which owns h.presence and h.published.
+func (h *Hub) refreshPresence(boardID, userID uint) {
+	h.mu.RLock()
+	local, viewing := h.localViewer(boardID, userID)
+	h.mu.RUnlock()
+
+	switch {
+	case viewing:
+		if h.published[boardID] == nil {
+			h.published[boardID] = make(map[uint]bool)
+		}
+		h.published[boardID][userID] = true
+		h.publishPresence(boardID, userID, &local)
+
+	case h.published[boardID][userID]:
+		delete(h.published[boardID], userID)
+		if len(h.published[boardID]) == 0 {
+			delete(h.published, boardID)
+		}
+		h.publishPresence(boardID, userID, nil)
+	}
+
+	h.showPresence(boardID, userID)
+}
+
+// showPresence compares a user's presence on a board with what its clients
+// were last told and broadcasts the difference. It runs on the Run
+// goroutine.
+func (h *Hub) showPresence(boardID, userID uint) {
+	h.mu.RLock()
+	viewer, viewing := h.viewer(boardID, userID)
+	h.mu.RUnlock()
+	last, known := h.presence[boardID][userID]
+
+	switch {
+	case !viewing && !known:
+		return
+
+	case !viewing:
+		delete(h.presence[boardID], userID)
+		if len(h.presence[boardID]) == 0 {
+			delete(h.presence, boardID)
+		}
+		h.sendPresence(boardID, MessagePresenceLeft, map[string]interface{}{"user_id": userID})
+		return
+
+	case !known:
+		if h.presence[boardID] == nil {
+			h.presence[boardID] = make(map[uint]Viewer)
+		}
+		h.sendPresence(boardID, MessagePresenceJoined, viewer)
+
+	default:
+		if !sameCard(last.CardID, viewer.CardID) {
+			h.sendPresence(boardID, MessagePresenceFocus, map[string]interface{}{"user_id": userID, "card_id": viewer.CardID})
+		}
+		if last.Idle != viewer.Idle {
+			h.sendPresence(boardID, MessagePresenceIdle, map[string]interface{}{"user_id": userID, "idle": viewer.Idle})
+		}
+	}
+	h.presence[boardID][userID] = viewer
+}
+
+// sweepPresence refreshes every known viewer, so those who went quiet are
+// shown as idle, this hub's viewers are repeated to the other instances, and
+// other instances' viewers that were not repeated are dropped
+func (h *Hub) sweepPresence() {
+	h.mu.Lock()
+	for boardID, users := range h.remotePresence {
+		for userID, instances := range users {
+			for instance, remote := range instances {
+				if time.Now().After(remote.expires) {
+					delete(instances, instance)
+				}
+			}
+			if len(instances) == 0 {
+				delete(users, userID)
+			}
+		}
+		if len(users) == 0 {
+			delete(h.remotePresence, boardID)
+		}
+	}
+	h.mu.Unlock()
+
+	for boardID, viewers := range h.presence {
+		for userID := range viewers {
+			h.refreshPresence(boardID, userID)
+		}
+	}
+}
+
+// publishPresence queues a user's presence on a board through this hub for
+// the other instances. It never blocks Run; a dropped update is repeated by
+// the next sweep, or for a user who left, expires.
+func (h *Hub) publishPresence(boardID, userID uint, viewer *Viewer) {
+	message := &Message{
+		Type:    messagePresenceState,
+		BoardID: boardID,
+		Data:    presenceState{Instance: h.instance, UserID: userID, Viewer: viewer},
+	}
+	select {
+	case h.presenceOut <- message:
+	default:
+	}
+}
+
+// presencePublisher publishes the presence updates publishPresence queues.
+// It runs apart from Run, as publishing can wait on the network and on Run
+// itself.
+func (h *Hub) presencePublisher() {
+	for {
+		select {
+		case <-h.stop:
+			return
+		case message := <-h.presenceOut:
+			if err := h.PubSub.Publish(message); err != nil {
+				log.Printf("Error publishing presence for board %d: %v", message.BoardID, err)
+			}
+		}
+	}
+}
+
+// receivePresence records another instance's presence update and shows any
+// change to the board's clients. It runs on the Run goroutine.
+func (h *Hub) receivePresence(message *Message) {
+	// The data is a presenceState in process and a map once it has been
+	// through a notification
+	var state presenceState
+	raw, err := json.Marshal(message.Data)
+	if err == nil {
+		err = json.Unmarshal(raw, &state)
+	}
+	if err != nil {
+		log.Printf("Invalid presence for board %d: %v", message.BoardID, err)
+		return
+	}
+	if state.Instance == h.instance {
+		return
+	}
+
+	h.mu.Lock()
+	users := h.remotePresence[message.BoardID]
+	switch {
+	case state.Viewer != nil:
+		if users == nil {
+			users = make(map[uint]map[string]remoteViewer)
+			h.remotePresence[message.BoardID] = users
+		}
+		if users[state.UserID] == nil {
+			users[state.UserID] = make(map[string]remoteViewer)
+		}
+		users[state.UserID][state.Instance] = remoteViewer{Viewer: *state.Viewer, expires: time.Now().Add(presenceExpiry)}
+
+	case users != nil:
+		delete(users[state.UserID], state.Instance)
+		if len(users[state.UserID]) == 0 {
+			delete(users, state.UserID)
+		}
+		if len(users) == 0 {
+			delete(h.remotePresence, message.BoardID)
+		}
+	}
+	h.mu.Unlock()
+
+	h.showPresence(message.BoardID, state.UserID)
+}
+
+// sendPresence queues a presence message for a board's local clients. A
+// client too slow to take it is closed and unregisters itself.
+func (h *Hub) sendPresence(boardID uint, messageType string, data interface{}) {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.boards[boardID]))
+	for client := range h.boards[boardID] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	message := Message{Type: messageType, BoardID: boardID, Data: data}
+	for _, client := range clients {
+		if !client.deliver(boardID, 0) {
+			continue
+		}
+		if !client.send(message) {
+			client.close()
+		}
+	}
+}
+
+// sameCard reports whether two optional card IDs are equal
+func sameCard(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
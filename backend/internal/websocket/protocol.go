@@ -0,0 +1,148 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Message types clients send. Any other type is a command for the hub's
+// CommandHandler, such as "move_card" or "add_comment".
+const (
+	CommandPing        = "ping"        // Answered with a pong
+	CommandSubscribe   = "subscribe"   // Receive board_id's broadcasts, replaying those after since
+	CommandUnsubscribe = "unsubscribe" // Stop receiving board_id's broadcasts
+)
+
+// Message types the hub sends in reply to a client message
+const (
+	MessagePong   = "pong"
+	MessageAck    = "ack"    // The message was accepted; data holds the result
+	MessageReject = "reject" // The message was refused; error says why
+)
+
+// MessageAccessRevoked tells a client it was unsubscribed from a board
+// because its user can no longer view it
+const MessageAccessRevoked = "access_revoked"
+
+// messageRevoke carries a user's lost access to a board to every hub over
+// PubSub, with the user's ID as data. Clients never see it.
+const messageRevoke = "revoke_access"
+
+// maxSubscriptions is the most boards one connection can subscribe to
+const maxSubscriptions = 50
+
+// ErrUnknownCommand is returned by a CommandHandler for a type it does not run
+var ErrUnknownCommand = errors.New("unknown message type")
+
+// Command is a message from a client. ID is chosen by the client and echoed
+// in the reply.
+type Command struct {
+	Type    string          `json:"type"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	BoardID uint            `json:"board_id,omitempty"`
+	Since   *uint64         `json:"since,omitempty"` // Last event seen, for subscribe
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Reply answers a client message
+type Reply struct {
+	Type    string          `json:"type"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	BoardID uint            `json:"board_id,omitempty"`
+	Status  int             `json:"status,omitempty"` // HTTP status of a command's result
+	Error   string          `json:"error,omitempty"`
+	Data    interface{}     `json:"data,omitempty"`
+}
+
+// CommandHandler authorizes subscriptions and runs client commands. It is
+// provided by the handlers package, which owns permissions and mutations.
+type CommandHandler interface {
+	// CanView reports whether a user may receive a board's broadcasts
+	CanView(userID, boardID uint) bool
+	// Execute runs a command for a user and returns its HTTP status and JSON
+	// result, or ErrUnknownCommand
+	Execute(userID uint, commandType string, data json.RawMessage) (status int, result json.RawMessage, err error)
+}
+
+// handle answers one message from a client
+func (h *Hub) handle(client *Client, raw []byte) {
+	var command Command
+	if err := json.Unmarshal(raw, &command); err != nil || command.Type == "" {
+		client.reply(Reply{Type: MessageReject, Status: http.StatusBadRequest, Error: "Invalid message"})
+		return
+	}
+	reply := Reply{Type: MessageAck, ID: command.ID, BoardID: command.BoardID}
+
+	// Any message shows the user is active again
+	for _, boardID := range client.touch(h.IdleAfter) {
+		h.presenceChanged(client, boardID)
+	}
+
+	switch command.Type {
+	case CommandPing:
+		reply.Type = MessagePong
+
+	case CommandSubscribe:
+		switch {
+		case command.BoardID == 0:
+			reply = reject(command, http.StatusBadRequest, "board_id required")
+		case client.subscriptions() >= maxSubscriptions:
+			reply = reject(command, http.StatusBadRequest, "Too many subscriptions")
+		case h.Commands == nil || !h.Commands.CanView(client.UserID, command.BoardID):
+			reply = reject(command, http.StatusForbidden, "Access denied to this board")
+		case !h.Join(client, command.BoardID, command.Since):
+			reply = reject(command, http.StatusConflict, "Already subscribed to this board")
+		}
+
+	case CommandUnsubscribe:
+		h.Leave(client, command.BoardID)
+
+	case CommandFocus:
+		// The card is not checked; it is only shown to the board's viewers
+		var focus struct {
+			CardID *uint `json:"card_id"`
+		}
+		if len(command.Data) > 0 && json.Unmarshal(command.Data, &focus) != nil {
+			reply = reject(command, http.StatusBadRequest, "Invalid card_id")
+			break
+		}
+		if !client.subscribed(command.BoardID) {
+			reply = reject(command, http.StatusBadRequest, "Not subscribed to this board")
+			break
+		}
+		client.setFocus(command.BoardID, focus.CardID)
+		h.presenceChanged(client, command.BoardID)
+
+	default:
+		if h.Commands == nil {
+			reply = reject(command, http.StatusBadRequest, ErrUnknownCommand.Error())
+			break
+		}
+		status, result, err := h.Commands.Execute(client.UserID, command.Type, command.Data)
+		if errors.Is(err, ErrUnknownCommand) {
+			reply = reject(command, http.StatusBadRequest, err.Error())
+			break
+		}
+		if err != nil {
+			reply = reject(command, http.StatusInternalServerError, "Failed to run command")
+			break
+		}
+
+		reply.Status, reply.Data = status, result
+		if status >= http.StatusBadRequest {
+			var body struct {
+				Error string `json:"error"`
+			}
+			json.Unmarshal(result, &body)
+			reply.Type, reply.Error = MessageReject, body.Error
+		}
+	}
+
+	client.reply(reply)
+}
+
+// reject builds the reply refusing a command
+func reject(command Command, status int, message string) Reply {
+	return Reply{Type: MessageReject, ID: command.ID, BoardID: command.BoardID, Status: status, Error: message}
+}
@@ -0,0 +1,45 @@
+package websocket
+
+import "sync"
+
+// PubSub carries board messages to every hub subscribed to it, whether in
+// this process or, depending on the backend, in other instances of the API.
+// Hubs also share their viewers through it, so each reports the presence of
+// every instance's connections.
+type PubSub interface {
+	// Publish sends a message to every subscribed hub, including the
+	// publisher's own
+	Publish(message *Message) error
+	// Subscribe calls deliver with every published message. deliver must not
+	// block for long.
+	Subscribe(deliver func(*Message))
+}
+
+// MemoryPubSub is the in-process PubSub. It is the default, and lets several
+// hubs in one process, such as in tests, share their broadcasts.
+type MemoryPubSub struct {
+	mu          sync.RWMutex
+	subscribers []func(*Message)
+}
+
+// NewMemoryPubSub creates an in-process PubSub with no subscribers
+func NewMemoryPubSub() *MemoryPubSub {
+	return &MemoryPubSub{}
+}
+
+// Publish hands the message to every subscriber
+func (ps *MemoryPubSub) Publish(message *Message) error {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	for _, deliver := range ps.subscribers {
+		deliver(message)
+	}
+	return nil
+}
+
+// Subscribe adds a subscriber
+func (ps *MemoryPubSub) Subscribe(deliver func(*Message)) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.subscribers = append(ps.subscribers, deliver)
+}
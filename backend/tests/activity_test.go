@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type ActivityTestSuite struct {
+	suite.Suite
+}
+
+// Test a card edit is logged with the before and after of each changed field
+func (suite *ActivityTestSuite) TestUpdateCard_LogsChanges() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := PUT(fmt.Sprintf("/cards/%d", card.ID), map[string]interface{}{
+		"title":       "Renamed",
+		"description": card.Description,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/activities/board/%d?action=updated_card", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	activities := response.Body["activities"].([]interface{})
+	suite.Len(activities, 1)
+
+	activity := activities[0].(map[string]interface{})
+	suite.Equal(float64(card.ID), activity["entity_id"])
+	changes := activity["metadata"].(map[string]interface{})["changes"].([]interface{})
+	suite.Len(changes, 1)
+	change := changes[0].(map[string]interface{})
+	suite.Equal("title", change["field"])
+	suite.Equal(card.Title, change["from"])
+	suite.Equal("Renamed", change["to"])
+
+	// Saving the same values again changes nothing and logs nothing
+	response = PUT(fmt.Sprintf("/cards/%d", card.ID), map[string]interface{}{"title": "Renamed"}, token)
+	suite.Equal(200, response.StatusCode)
+	response = GET(fmt.Sprintf("/activities/board/%d?action=updated_card", board.ID), token)
+	suite.Len(response.Body["activities"], 1)
+}
+
+// Test list, label and member changes are logged
+func (suite *ActivityTestSuite) TestMutations_AreLogged() {
+	owner := Factory.CreateUser()
+	member := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	suite.Equal(200, PUT(fmt.Sprintf("/lists/%d", list.ID), map[string]interface{}{"title": "Doing"}, token).StatusCode)
+	suite.Equal(201, POST(fmt.Sprintf("/boards/%d/members", board.ID), map[string]interface{}{
+		"user_id": member.ID,
+		"role":    "member",
+	}, token).StatusCode)
+	suite.Equal(200, DELETE(fmt.Sprintf("/cards/%d", card.ID), token).StatusCode)
+
+	var actions []string
+	database.DB.Model(&models.Activity{}).Where("board_id = ?", board.ID).Order("id").Pluck("action", &actions)
+	suite.Equal([]string{"updated_list", "added_member", "deleted_card"}, actions)
+}
+
+// Test the board feed filters by entity, user, action and date
+func (suite *ActivityTestSuite) TestGetBoardActivities_Filters() {
+	owner := Factory.CreateUser()
+	member := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	Factory.CreateBoardMember(board.ID, member.ID, "member")
+	token := GenerateTestJWT(member.ID, member.Username, member.Email)
+
+	old := models.Activity{Action: "created_card", EntityType: "card", EntityID: 1, BoardID: board.ID, UserID: owner.ID}
+	database.DB.Create(&old)
+	database.DB.Model(&old).Update("created_at", time.Date(2030, 1, 10, 12, 0, 0, 0, time.UTC))
+	for _, activity := range []models.Activity{
+		{Action: "updated_card", EntityType: "card", EntityID: 1, BoardID: board.ID, UserID: member.ID},
+		{Action: "created_list", EntityType: "list", EntityID: 2, BoardID: board.ID, UserID: owner.ID},
+	} {
+		database.DB.Create(&activity)
+	}
+
+	endpoint := fmt.Sprintf("/activities/board/%d", board.ID)
+	count := func(query string) int {
+		response := GET(endpoint+query, token)
+		suite.Equal(200, response.StatusCode, query)
+		return len(response.Body["activities"].([]interface{}))
+	}
+	suite.Equal(3, count(""))
+	suite.Equal(2, count("?entity_type=card&entity_id=1"))
+	suite.Equal(1, count(fmt.Sprintf("?user_id=%d", member.ID)))
+	suite.Equal(2, count("?action=created_card,created_list"))
+	suite.Equal(1, count("?from=2030-01-10&to=2030-01-10"))
+	suite.Equal(0, count("?from=2030-01-11&to=2030-01-31"))
+
+	// Pages of one
+	response := GET(endpoint+"?limit=1", token)
+	suite.Len(response.Body["activities"], 1)
+	suite.Equal(true, response.Body["has_more"])
+
+	suite.Equal(400, GET(endpoint+"?from=january", token).StatusCode)
+	suite.Equal(400, GET(endpoint+"?user_id=me", token).StatusCode)
+
+	outsider := Factory.CreateUser()
+	suite.Equal(404, GET(endpoint, GenerateTestJWT(outsider.ID, outsider.Username, outsider.Email)).StatusCode)
+}
+
+func TestActivityTestSuite(t *testing.T) {
+	suite.Run(t, new(ActivityTestSuite))
+}
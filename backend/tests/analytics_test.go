@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnalyticsTestSuite struct {
+	suite.Suite
+}
+
+// Test moves are recorded and a finished card shows up in every report
+func (suite *AnalyticsTestSuite) TestFinishedCard_Reports() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	doing := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	database.DB.Model(done).Update("is_done", true)
+	label := models.Label{Name: "Bug", Color: "#FF5733", BoardID: board.ID}
+	database.DB.Create(&label)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST("/cards", map[string]interface{}{"title": "Ship it", "list_id": todo.ID}, token)
+	suite.Equal(201, response.StatusCode)
+	cardID := uint(response.Body["id"].(float64))
+	database.DB.Create(&models.CardLabel{CardID: cardID, LabelID: label.ID})
+	open := POST("/cards", map[string]interface{}{"title": "Still open", "list_id": todo.ID}, token)
+	suite.Equal(201, open.StatusCode)
+
+	for _, listID := range []uint{doing.ID, done.ID} {
+		response = POST(fmt.Sprintf("/cards/%d/move", cardID), map[string]interface{}{"list_id": listID, "position": 0}, token)
+		suite.Equal(200, response.StatusCode)
+	}
+
+	response = GET(fmt.Sprintf("/cards/%d/transitions", cardID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(3), response.Body["count"])
+
+	response = GET(fmt.Sprintf("/boards/%d/analytics/cycle-time", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	cards := response.Body["cards"].([]interface{})
+	suite.Len(cards, 1)
+	suite.Equal(float64(cardID), cards[0].(map[string]interface{})["card_id"])
+	suite.NotNil(cards[0].(map[string]interface{})["started_at"])
+	labels := response.Body["labels"].([]interface{})
+	suite.Len(labels, 1)
+	suite.Equal(float64(1), labels[0].(map[string]interface{})["cards"])
+
+	response = GET(fmt.Sprintf("/boards/%d/analytics/throughput", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	weeks := response.Body["weeks"].([]interface{})
+	suite.Equal(float64(1), weeks[len(weeks)-1].(map[string]interface{})["cards"])
+
+	today := time.Now().UTC().Format("2006-01-02")
+	response = GET(fmt.Sprintf("/boards/%d/analytics/cfd?from=%s&to=%s", board.ID, today, today), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal([]interface{}{today}, response.Body["dates"])
+	counts := map[uint]float64{}
+	for _, item := range response.Body["lists"].([]interface{}) {
+		series := item.(map[string]interface{})
+		counts[uint(series["list_id"].(float64))] = series["counts"].([]interface{})[0].(float64)
+	}
+	suite.Equal(map[uint]float64{todo.ID: 1, doing.ID: 0, done.ID: 1}, counts)
+}
+
+// Test date ranges are validated
+func (suite *AnalyticsTestSuite) TestDateRange_Invalid() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := GET(fmt.Sprintf("/boards/%d/analytics/cfd?from=2024-03-01&to=2024-02-01", board.ID), token)
+	suite.Equal(400, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/boards/%d/analytics/throughput?from=2022-01-01&to=2024-01-01", board.ID), token)
+	suite.Equal(400, response.StatusCode)
+}
+
+func TestAnalyticsTestSuite(t *testing.T) {
+	suite.Run(t, new(AnalyticsTestSuite))
+}
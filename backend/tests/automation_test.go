@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/stretchr/testify/suite"
+)
+
+type AutomationTestSuite struct {
+	suite.Suite
+}
+
+// automationRuns counts a rule's logged runs with the given status
+func automationRuns(ruleID uint, status string) int64 {
+	var count int64
+	database.DB.Model(&models.AutomationRun{}).Where("rule_id = ? AND status = ?", ruleID, status).Count(&count)
+	return count
+}
+
+// Test moving a card into Done completes its due date and removes its members
+func (suite *AutomationTestSuite) TestCardMoved_RunsActions() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(todo.ID)
+	database.DB.Create(&models.CardMember{CardID: card.ID, UserID: owner.ID})
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/automations", board.ID), map[string]interface{}{
+		"name":    "Finish done cards",
+		"trigger": "card_moved",
+		"filter":  map[string]interface{}{"list_id": done.ID},
+		"actions": []map[string]interface{}{
+			{"type": "complete_due"},
+			{"type": "unassign"},
+		},
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	ruleID := uint(response.Body["id"].(float64))
+
+	response = POST(fmt.Sprintf("/cards/%d/move", card.ID), map[string]interface{}{
+		"list_id":  done.ID,
+		"position": 0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	suite.Eventually(func() bool {
+		return automationRuns(ruleID, services.RunSucceeded) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	var updated models.Card
+	database.DB.Preload("Members").First(&updated, card.ID)
+	suite.True(updated.DueComplete)
+	suite.Empty(updated.Members)
+
+	response = GET(fmt.Sprintf("/boards/%d/automations/runs?rule_id=%d", board.ID, ruleID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["count"])
+
+	// Actions must refer to the rule's board
+	other := Factory.CreateList(Factory.CreateBoard(owner.ID).ID)
+	response = POST(fmt.Sprintf("/boards/%d/automations", board.ID), map[string]interface{}{
+		"name":    "Move elsewhere",
+		"trigger": "card_created",
+		"actions": []map[string]interface{}{{"type": "move", "list_id": other.ID}},
+	}, token)
+	suite.Equal(400, response.StatusCode)
+}
+
+// Test two rules that move a card back and forth stop after each ran once
+func (suite *AutomationTestSuite) TestLoopProtection() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	first := Factory.CreateList(board.ID)
+	second := Factory.CreateList(board.ID)
+	start := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(start.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	ruleIDs := make([]uint, 2)
+	for i, lists := range [][2]uint{{first.ID, second.ID}, {second.ID, first.ID}} {
+		response := POST(fmt.Sprintf("/boards/%d/automations", board.ID), map[string]interface{}{
+			"name":    fmt.Sprintf("Bounce %d", i),
+			"trigger": "card_moved",
+			"filter":  map[string]interface{}{"list_id": lists[0]},
+			"actions": []map[string]interface{}{{"type": "move", "list_id": lists[1]}},
+		}, token)
+		suite.Equal(201, response.StatusCode)
+		ruleIDs[i] = uint(response.Body["id"].(float64))
+	}
+
+	response := POST(fmt.Sprintf("/cards/%d/move", card.ID), map[string]interface{}{
+		"list_id":  first.ID,
+		"position": 0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	suite.Eventually(func() bool {
+		return automationRuns(ruleIDs[0], services.RunSkipped) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+	suite.Equal(int64(1), automationRuns(ruleIDs[0], services.RunSucceeded))
+	suite.Equal(int64(1), automationRuns(ruleIDs[1], services.RunSucceeded))
+
+	// Long chains are cut off whichever rules they contain
+	rule := models.AutomationRule{ID: ruleIDs[0]}
+	automationService := &services.AutomationService{}
+	suite.NotEmpty(automationService.SkipReason(&rule, make([]uint, services.MaxAutomationDepth)))
+	suite.Empty(automationService.SkipReason(&rule, []uint{ruleIDs[1]}))
+}
+
+// Test a trigger the runner could not queue shows as a failed run
+func (suite *AutomationTestSuite) TestDroppedTrigger_LoggedAsFailedRun() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/automations", board.ID), map[string]interface{}{
+		"name":    "Finish new cards",
+		"trigger": "card_created",
+		"actions": []map[string]interface{}{{"type": "complete_due"}},
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	ruleID := uint(response.Body["id"].(float64))
+
+	automationService := &services.AutomationService{}
+	trigger := services.AutomationTrigger{Event: services.TriggerCardCreated, BoardID: board.ID, CardID: card.ID, ListID: list.ID}
+	suite.NoError(automationService.RecordDropped(database.DB, &trigger, "automation queue full; trigger dropped"))
+
+	response = GET(fmt.Sprintf("/boards/%d/automations/runs?rule_id=%d&status=failed", board.ID, ruleID), token)
+	suite.Equal(200, response.StatusCode)
+	runs := response.Body["runs"].([]interface{})
+	suite.Require().NotEmpty(runs)
+	suite.Equal("automation queue full; trigger dropped", runs[0].(map[string]interface{})["error"])
+}
+
+// Test a schedule rule archives the cards of its list once per occurrence
+func (suite *AutomationTestSuite) TestSchedule_ArchivesCards() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	done := Factory.CreateList(board.ID)
+	cards := []*models.Card{Factory.CreateCard(done.ID), Factory.CreateCard(done.ID)}
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/automations", board.ID), map[string]interface{}{
+		"name":     "Friday clean-up",
+		"trigger":  "schedule",
+		"cron":     "0 17 * * 5",
+		"timezone": "Europe/London",
+		"filter":   map[string]interface{}{"list_id": done.ID},
+		"actions":  []map[string]interface{}{{"type": "archive"}},
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	suite.NotNil(response.Body["next_run_at"])
+	ruleID := uint(response.Body["id"].(float64))
+
+	// Pretend the last occurrence was missed
+	database.DB.Model(&models.AutomationRule{}).Where("id = ?", ruleID).Update("next_run_at", time.Now().Add(-time.Hour))
+
+	automationService := &services.AutomationService{}
+	_, err := automationService.RunScheduled(database.DB, time.Now(), 10)
+	suite.NoError(err)
+
+	suite.Eventually(func() bool {
+		return automationRuns(ruleID, services.RunSucceeded) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+	for _, card := range cards {
+		var updated models.Card
+		database.DB.First(&updated, card.ID)
+		suite.NotNil(updated.ArchivedAt)
+	}
+
+	var rule models.AutomationRule
+	database.DB.First(&rule, ruleID)
+	suite.True(rule.NextRunAt.After(time.Now()))
+
+	// A schedule rule needs a list to act on
+	response = POST(fmt.Sprintf("/boards/%d/automations", board.ID), map[string]interface{}{
+		"name":    "No list",
+		"trigger": "schedule",
+		"cron":    "0 17 * * 5",
+		"actions": []map[string]interface{}{{"type": "archive"}},
+	}, token)
+	suite.Equal(400, response.StatusCode)
+}
+
+func TestAutomationTestSuite(t *testing.T) {
+	suite.Run(t, new(AutomationTestSuite))
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type CalendarFeedTestSuite struct {
+	suite.Suite
+}
+
+// feedPath strips the API base from a feed URL
+func feedPath(url string) string {
+	return url[strings.Index(url, "/feeds/"):]
+}
+
+// Test a board feed lists due cards as events and rotating it revokes the old URL
+func (suite *CalendarFeedTestSuite) TestBoardFeed_EventsAndRotation() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	undated := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	due := time.Date(2030, 3, 4, 15, 30, 0, 0, time.UTC)
+	database.DB.Model(card).Update("due_date", due)
+	label := models.Label{Name: "Release, v2", Color: "#ff0000", BoardID: board.ID}
+	database.DB.Create(&label)
+	database.DB.Model(card).Association("Labels").Append(&label)
+
+	response := POST(fmt.Sprintf("/boards/%d/calendar-feed", board.ID), nil, token)
+	suite.Equal(200, response.StatusCode)
+	firstPath := feedPath(response.Body["url"].(string))
+
+	response = GET(firstPath)
+	suite.Equal(200, response.StatusCode)
+	suite.Contains(response.RawBody, "BEGIN:VCALENDAR\r\n")
+	suite.Contains(response.RawBody, fmt.Sprintf("UID:flowboard-card-%d@flowboard\r\n", card.ID))
+	suite.Contains(response.RawBody, "DTSTART:20300304T153000Z\r\n")
+	suite.Contains(response.RawBody, "CATEGORIES:Release\\, v2\r\n")
+	suite.NotContains(response.RawBody, fmt.Sprintf("flowboard-card-%d@", undated.ID))
+
+	// Enabling again issues a new URL and the old one stops working
+	response = POST(fmt.Sprintf("/boards/%d/calendar-feed", board.ID), nil, token)
+	suite.Equal(200, response.StatusCode)
+	secondPath := feedPath(response.Body["url"].(string))
+	suite.NotEqual(firstPath, secondPath)
+	suite.Equal(404, GET(firstPath).StatusCode)
+	suite.Equal(200, GET(secondPath).StatusCode)
+
+	suite.Equal(200, DELETE(fmt.Sprintf("/boards/%d/calendar-feed", board.ID), token).StatusCode)
+	suite.Equal(404, GET(secondPath).StatusCode)
+}
+
+// Test feeds stop showing cards once their owner loses access to the board
+func (suite *CalendarFeedTestSuite) TestFeeds_FollowBoardAccess() {
+	owner := Factory.CreateUser()
+	member := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	Factory.CreateBoardMember(board.ID, member.ID, "member")
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	database.DB.Model(card).Update("due_date", time.Now().Add(48*time.Hour))
+	database.DB.Create(&models.CardMember{CardID: card.ID, UserID: member.ID})
+	token := GenerateTestJWT(member.ID, member.Username, member.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/calendar-feed", board.ID), nil, token)
+	suite.Equal(200, response.StatusCode)
+	boardPath := feedPath(response.Body["url"].(string))
+
+	response = POST("/me/calendar-feed", nil, token)
+	suite.Equal(200, response.StatusCode)
+	personalPath := feedPath(response.Body["url"].(string))
+
+	uid := fmt.Sprintf("UID:flowboard-card-%d@flowboard", card.ID)
+	suite.Contains(GET(boardPath).RawBody, uid)
+	suite.Contains(GET(personalPath).RawBody, uid)
+
+	response = GET("/me/calendar-feeds", token)
+	suite.Equal(200, response.StatusCode)
+	suite.Len(response.Body["feeds"], 2)
+
+	database.DB.Where("board_id = ? AND user_id = ?", board.ID, member.ID).Delete(&models.BoardMember{})
+
+	response = GET(boardPath)
+	suite.Equal(200, response.StatusCode)
+	suite.NotContains(response.RawBody, uid)
+	suite.NotContains(GET(personalPath).RawBody, uid)
+}
+
+func TestCalendarFeedTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarFeedTestSuite))
+}
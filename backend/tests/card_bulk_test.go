@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type CardBulkTestSuite struct {
+	suite.Suite
+}
+
+// Test bulk move keeps the requested order at the target position
+func (suite *CardBulkTestSuite) TestBulkMove() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	source := Factory.CreateList(board.ID)
+	dest := Factory.CreateList(board.ID)
+	a := Factory.CreateCard(source.ID)
+	b := Factory.CreateCard(source.ID)
+	existing := Factory.CreateCard(dest.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST("/cards/bulk", map[string]interface{}{
+		"card_ids":  []uint{b.ID, a.ID},
+		"operation": "move",
+		"list_id":   dest.ID,
+		"position":  0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(2), response.Body["updated"])
+
+	suite.Empty(cardIDsInOrder(source.ID))
+	suite.Equal([]uint{b.ID, a.ID, existing.ID}, cardIDsInOrder(dest.ID))
+
+	var activity models.Activity
+	database.DB.Where("board_id = ? AND action = ?", board.ID, "bulk_updated_cards").First(&activity)
+	suite.NotZero(activity.ID)
+}
+
+// Test bulk archive hides cards from the list
+func (suite *CardBulkTestSuite) TestBulkArchive() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	a := Factory.CreateCard(list.ID)
+	b := Factory.CreateCard(list.ID)
+	kept := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST("/cards/bulk", map[string]interface{}{
+		"card_ids":  []uint{a.ID, b.ID},
+		"operation": "archive",
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/cards/list/%d", list.ID), token)
+	suite.Equal(float64(1), response.Body["count"])
+	card := response.Body["cards"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(float64(kept.ID), card["id"])
+}
+
+// Test one card from another board rejects the whole request
+func (suite *CardBulkTestSuite) TestBulk_CardOnOtherBoard() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	other := Factory.CreateCard(Factory.CreateList(Factory.CreateBoard(owner.ID).ID).ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST("/cards/bulk", map[string]interface{}{
+		"card_ids":  []uint{card.ID, other.ID},
+		"operation": "delete",
+	}, token)
+	suite.Equal(400, response.StatusCode)
+
+	var count int64
+	database.DB.Model(&models.Card{}).Where("id IN ?", []uint{card.ID, other.ID}).Count(&count)
+	suite.Equal(int64(2), count)
+}
+
+func TestCardBulkTestSuite(t *testing.T) {
+	suite.Run(t, new(CardBulkTestSuite))
+}
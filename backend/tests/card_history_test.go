@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/textdiff"
+	"github.com/stretchr/testify/suite"
+)
+
+type CardHistoryTestSuite struct {
+	suite.Suite
+}
+
+// Test concurrent edits record the values each one replaced, so every
+// revision starts where the one before it ended
+func (suite *CardHistoryTestSuite) TestConcurrentEdits_ChainRevisions() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+	endpoint := fmt.Sprintf("/cards/%d", card.ID)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			PUT(endpoint, map[string]interface{}{"title": fmt.Sprintf("Title %d", i)}, token)
+		}(i)
+	}
+	wg.Wait()
+
+	var revisions []models.CardRevision
+	database.DB.Where("card_id = ? AND field = ?", card.ID, "title").Order("id ASC").Find(&revisions)
+	suite.Require().NotEmpty(revisions)
+	for i := 1; i < len(revisions); i++ {
+		suite.JSONEq(string(revisions[i-1].NewValue), string(revisions[i].OldValue))
+	}
+}
+
+// Test description edits are listed newest first and can be diffed and reverted
+func (suite *CardHistoryTestSuite) TestDescription_HistoryDiffRevert() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+	endpoint := fmt.Sprintf("/cards/%d", card.ID)
+
+	suite.Equal(200, PUT(endpoint, map[string]interface{}{"description": "Buy milk\nBuy eggs"}, token).StatusCode)
+	suite.Equal(200, PUT(endpoint, map[string]interface{}{"description": "Buy milk\nBuy bread"}, token).StatusCode)
+
+	response := GET(endpoint+"/history?field=description", token)
+	suite.Equal(200, response.StatusCode)
+	revisions := response.Body["revisions"].([]interface{})
+	suite.Len(revisions, 2)
+	latest := revisions[0].(map[string]interface{})
+	first := revisions[1].(map[string]interface{})
+	suite.Equal("Buy milk\nBuy bread", latest["new_value"])
+	suite.Equal("Buy milk\nBuy eggs", latest["old_value"])
+	suite.Equal(owner.Username, latest["user"].(map[string]interface{})["username"])
+
+	response = GET(fmt.Sprintf("%s/history/%v/diff", endpoint, latest["id"]), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["inserted"])
+	suite.Equal(float64(1), response.Body["deleted"])
+	suite.Len(response.Body["lines"], 3)
+
+	// Compared with a later revision instead of its own old value
+	response = GET(fmt.Sprintf("%s/history/%v/diff?against=%v", endpoint, first["id"], latest["id"]), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["inserted"])
+
+	response = POST(fmt.Sprintf("%s/history/%v/revert", endpoint, first["id"]), nil, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(true, response.Body["reverted"])
+	suite.Equal("Buy milk\nBuy eggs", response.Body["card"].(map[string]interface{})["description"])
+
+	response = GET(endpoint+"/history?field=description", token)
+	suite.Len(response.Body["revisions"], 3)
+
+	// Reverting to the value the card already has changes nothing
+	response = POST(fmt.Sprintf("%s/history/%v/revert", endpoint, first["id"]), nil, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(false, response.Body["reverted"])
+}
+
+// Test moves and label changes are recorded and can be reverted
+func (suite *CardHistoryTestSuite) TestListAndLabels_Revert() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(todo.ID)
+	label := models.Label{Name: "Bug", Color: "#ff0000", BoardID: board.ID}
+	database.DB.Create(&label)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+	endpoint := fmt.Sprintf("/cards/%d", card.ID)
+
+	suite.Equal(200, POST(fmt.Sprintf("/labels/card/%d", card.ID), map[string]interface{}{"label_id": label.ID}, token).StatusCode)
+	suite.Equal(200, POST(endpoint+"/move", map[string]interface{}{"list_id": done.ID, "position": 0}, token).StatusCode)
+
+	response := GET(endpoint+"/history", token)
+	suite.Equal(200, response.StatusCode)
+	revisions := response.Body["revisions"].([]interface{})
+	suite.Len(revisions, 2)
+	moved := revisions[0].(map[string]interface{})
+	labelled := revisions[1].(map[string]interface{})
+	suite.Equal("list_id", moved["field"])
+	suite.Equal(float64(todo.ID), moved["old_value"])
+	suite.Equal("labels", labelled["field"])
+	suite.Equal([]interface{}{}, labelled["old_value"])
+	suite.Equal([]interface{}{float64(label.ID)}, labelled["new_value"])
+
+	// Back to the list from before the move, and to the label set after labelling
+	response = POST(fmt.Sprintf("%s/history/%v/revert?before=true", endpoint, moved["id"]), nil, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(200, DELETE(fmt.Sprintf("/labels/card/%d/%d", card.ID, label.ID), token).StatusCode)
+	response = POST(fmt.Sprintf("%s/history/%v/revert", endpoint, labelled["id"]), nil, token)
+	suite.Equal(200, response.StatusCode)
+
+	var reloaded models.Card
+	database.DB.Preload("Labels").First(&reloaded, card.ID)
+	suite.Equal(todo.ID, reloaded.ListID)
+	suite.Len(reloaded.Labels, 1)
+
+	// Diffs are only for text fields
+	suite.Equal(400, GET(fmt.Sprintf("%s/history/%v/diff", endpoint, moved["id"]), token).StatusCode)
+
+	outsider := Factory.CreateUser()
+	suite.Equal(403, GET(endpoint+"/history", GenerateTestJWT(outsider.ID, outsider.Username, outsider.Email)).StatusCode)
+}
+
+// Test the line diff keeps shared lines and pairs deletions with insertions
+func (suite *CardHistoryTestSuite) TestTextDiff_Lines() {
+	diff := textdiff.Lines("a\nb\nc\n", "a\nx\nc\nd")
+	suite.Equal([]textdiff.Line{
+		{Op: textdiff.Equal, Text: "a"},
+		{Op: textdiff.Delete, Text: "b"},
+		{Op: textdiff.Insert, Text: "x"},
+		{Op: textdiff.Equal, Text: "c"},
+		{Op: textdiff.Insert, Text: "d"},
+	}, diff)
+
+	inserted, deleted := textdiff.Stats(diff)
+	suite.Equal(2, inserted)
+	suite.Equal(1, deleted)
+	suite.Empty(textdiff.Lines("", ""))
+}
+
+func TestCardHistoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CardHistoryTestSuite))
+}
@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type CardRelationTestSuite struct {
+	suite.Suite
+}
+
+// Test blocking relations, cycle detection and the blocked flag
+func (suite *CardRelationTestSuite) TestCreateCardRelation_Blocks() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	blocker := Factory.CreateCard(list.ID)
+	blocked := Factory.CreateCard(list.ID)
+	third := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/cards/%d/relations", blocker.ID), map[string]interface{}{
+		"card_id": blocked.ID,
+		"type":    "blocks",
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	suite.Equal("blocks", response.Body["type"])
+
+	response = POST(fmt.Sprintf("/cards/%d/relations", blocked.ID), map[string]interface{}{
+		"card_id": third.ID,
+		"type":    "blocks",
+	}, token)
+	suite.Equal(201, response.StatusCode)
+
+	// third -> blocker would close the loop blocker -> blocked -> third
+	response = POST(fmt.Sprintf("/cards/%d/relations", blocker.ID), map[string]interface{}{
+		"card_id": third.ID,
+		"type":    "blocked_by",
+	}, token)
+	suite.Equal(409, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/cards/%d", blocked.ID), token)
+	suite.Equal(true, response.Body["blocked"])
+	suite.Equal([]interface{}{float64(blocker.ID)}, response.Body["blocked_by"])
+
+	response = GET(fmt.Sprintf("/cards/%d/relations", blocked.ID), token)
+	suite.Equal(float64(2), response.Body["count"])
+	relation := response.Body["relations"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("blocked_by", relation["type"])
+}
+
+// Test moving a blocked card into a done-list warns or is refused
+func (suite *CardRelationTestSuite) TestMoveCard_BlockedIntoDoneList() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	database.DB.Model(done).Update("is_done", true)
+	blocker := Factory.CreateCard(todo.ID)
+	blocked := Factory.CreateCard(todo.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	POST(fmt.Sprintf("/cards/%d/relations", blocked.ID), map[string]interface{}{
+		"card_id": blocker.ID,
+		"type":    "blocked_by",
+	}, token)
+
+	response := POST(fmt.Sprintf("/cards/%d/move", blocked.ID), map[string]interface{}{
+		"list_id":    done.ID,
+		"position":   0,
+		"if_blocked": "refuse",
+	}, token)
+	suite.Equal(409, response.StatusCode)
+
+	response = POST(fmt.Sprintf("/cards/%d/move", blocked.ID), map[string]interface{}{
+		"list_id":  done.ID,
+		"position": 0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.NotEmpty(response.Body["warning"])
+
+	// Finishing the blocker unblocks the card
+	POST(fmt.Sprintf("/cards/%d/move", blocker.ID), map[string]interface{}{
+		"list_id":  done.ID,
+		"position": 0,
+	}, token)
+	response = GET(fmt.Sprintf("/cards/%d", blocked.ID), token)
+	suite.Equal(false, response.Body["blocked"])
+}
+
+// Test blockers on boards the user cannot see are counted but not identified
+func (suite *CardRelationTestSuite) TestGetCard_HidesBlockersOnOtherBoards() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	database.DB.Model(done).Update("is_done", true)
+	blocked := Factory.CreateCard(todo.ID)
+	seen := Factory.CreateCard(todo.ID)
+
+	// Linked by someone who could see both boards
+	outsider := Factory.CreateUser()
+	hidden := Factory.CreateCard(Factory.CreateList(Factory.CreateBoard(outsider.ID).ID).ID)
+	database.DB.Create(&models.CardRelation{SourceCardID: hidden.ID, TargetCardID: blocked.ID, Type: "blocks", CreatedBy: outsider.ID})
+	database.DB.Create(&models.CardRelation{SourceCardID: seen.ID, TargetCardID: blocked.ID, Type: "blocks", CreatedBy: owner.ID})
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := GET(fmt.Sprintf("/cards/%d", blocked.ID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(true, response.Body["blocked"])
+	suite.Equal([]interface{}{float64(seen.ID)}, response.Body["blocked_by"])
+	suite.Equal(float64(1), response.Body["hidden_blockers"])
+
+	// Only the hidden blocker is left once the visible one is done
+	POST(fmt.Sprintf("/cards/%d/move", seen.ID), map[string]interface{}{"list_id": done.ID, "position": 0}, token)
+	response = POST(fmt.Sprintf("/cards/%d/move", blocked.ID), map[string]interface{}{
+		"list_id":    done.ID,
+		"position":   0,
+		"if_blocked": "refuse",
+	}, token)
+	suite.Equal(409, response.StatusCode)
+	suite.Nil(response.Body["blocked_by"])
+	suite.Equal(float64(1), response.Body["hidden_blockers"])
+}
+
+func TestCardRelationTestSuite(t *testing.T) {
+	suite.Run(t, new(CardRelationTestSuite))
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CustomFieldTestSuite struct {
+	suite.Suite
+}
+
+// Test defining fields, setting values and reading them on the card
+func (suite *CustomFieldTestSuite) TestSetCardFieldValue() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/fields", board.ID), map[string]interface{}{
+		"name": "Story points",
+		"type": "number",
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	points := response.Body["id"].(float64)
+
+	response = POST(fmt.Sprintf("/boards/%d/fields", board.ID), map[string]interface{}{
+		"name":    "Environment",
+		"type":    "select",
+		"options": []string{"staging", "production"},
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	environment := response.Body["id"].(float64)
+
+	response = PUT(fmt.Sprintf("/cards/%d/fields/%d", card.ID, int(points)), map[string]interface{}{"value": 5}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(5), response.Body["value"])
+
+	// Values must match the field type and options
+	response = PUT(fmt.Sprintf("/cards/%d/fields/%d", card.ID, int(points)), map[string]interface{}{"value": "five"}, token)
+	suite.Equal(400, response.StatusCode)
+	response = PUT(fmt.Sprintf("/cards/%d/fields/%d", card.ID, int(environment)), map[string]interface{}{"value": "dev"}, token)
+	suite.Equal(400, response.StatusCode)
+
+	response = PUT(fmt.Sprintf("/cards/%d/fields/%d", card.ID, int(environment)), map[string]interface{}{"value": "staging"}, token)
+	suite.Equal(200, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/cards/%d", card.ID), token)
+	suite.Equal(200, response.StatusCode)
+	fields := response.Body["custom_fields"].([]interface{})
+	suite.Len(fields, 2)
+	suite.Equal("Story points", fields[0].(map[string]interface{})["name"])
+	suite.Equal("staging", fields[1].(map[string]interface{})["value"])
+
+	// Removing an option clears the values using it
+	response = PUT(fmt.Sprintf("/fields/%d", int(environment)), map[string]interface{}{"options": []string{"production"}}, token)
+	suite.Equal(200, response.StatusCode)
+	response = GET(fmt.Sprintf("/cards/%d", card.ID), token)
+	suite.Len(response.Body["custom_fields"].([]interface{}), 1)
+}
+
+// Test custom field terms in the search grammar
+func (suite *CustomFieldTestSuite) TestSearchCards_CustomField() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	small := Factory.CreateCard(list.ID)
+	large := Factory.CreateCard(list.ID)
+	Factory.CreateCard(list.ID) // No estimate
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/fields", board.ID), map[string]interface{}{
+		"name": "Story points",
+		"type": "number",
+	}, token)
+	points := int(response.Body["id"].(float64))
+	PUT(fmt.Sprintf("/cards/%d/fields/%d", small.ID, points), map[string]interface{}{"value": 2}, token)
+	PUT(fmt.Sprintf("/cards/%d/fields/%d", large.ID, points), map[string]interface{}{"value": 8}, token)
+
+	response = GET("/search/cards?q="+url.QueryEscape(`field."story points">=5`), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["count"])
+	card := response.Body["cards"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(float64(large.ID), card["id"])
+
+	response = GET("/search/cards?q="+url.QueryEscape(`field."story points":none`), token)
+	suite.Equal(float64(1), response.Body["count"])
+}
+
+func TestCustomFieldTestSuite(t *testing.T) {
+	suite.Run(t, new(CustomFieldTestSuite))
+}
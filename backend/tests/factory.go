@@ -6,6 +6,7 @@ import (
 
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
 	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
 	"github.com/go-faker/faker/v4"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -99,10 +100,13 @@ func (f *FactoryHelper) CreateBoardMember(boardID, userID uint, roleName string)
 
 // CreateList creates a list in a board
 func (f *FactoryHelper) CreateList(boardID uint) *models.List {
+	ordering := &services.OrderingService{}
+	rank, _ := ordering.NextListRank(database.DB, boardID)
+
 	list := &models.List{
-		Title:    faker.Word() + " List",
-		BoardID:  boardID,
-		Position: 0,
+		Title:   faker.Word() + " List",
+		BoardID: boardID,
+		Rank:    rank,
 	}
 
 	database.DB.Create(list)
@@ -111,11 +115,14 @@ func (f *FactoryHelper) CreateList(boardID uint) *models.List {
 
 // CreateCard creates a card in a list
 func (f *FactoryHelper) CreateCard(listID uint) *models.Card {
+	ordering := &services.OrderingService{}
+	rank, _ := ordering.NextCardRank(database.DB, listID)
+
 	card := &models.Card{
 		Title:       faker.Sentence(),
 		Description: faker.Paragraph(),
 		ListID:      listID,
-		Position:    0,
+		Rank:        rank,
 	}
 
 	database.DB.Create(card)
@@ -3,13 +3,16 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
 )
 
 const BaseURL = "http://localhost:8083/api/v1"
@@ -35,6 +38,11 @@ func PUT(endpoint string, body interface{}, token ...string) *HTTPResponse {
 	return makeRequest("PUT", endpoint, body, token...)
 }
 
+// PATCH makes a PATCH request to the API
+func PATCH(endpoint string, body interface{}, token ...string) *HTTPResponse {
+	return makeRequest("PATCH", endpoint, body, token...)
+}
+
 // DELETE makes a DELETE request to the API
 func DELETE(endpoint string, token ...string) *HTTPResponse {
 	return makeRequest("DELETE", endpoint, nil, token...)
@@ -77,6 +85,32 @@ func makeRequest(method, endpoint string, body interface{}, token ...string) *HT
 	}
 }
 
+// DialWebSocket connects to a board's WebSocket, or to none with boardID 0,
+// with any extra query parameters, such as since
+func DialWebSocket(boardID uint, token string, query ...string) (*websocket.Conn, error) {
+	params := url.Values{}
+	if boardID != 0 {
+		params.Set("board_id", fmt.Sprint(boardID))
+	}
+	params.Set("token", token)
+	for i := 0; i+1 < len(query); i += 2 {
+		params.Set(query[i], query[i+1])
+	}
+
+	wsURL := strings.Replace(BaseURL, "http", "ws", 1) + "/ws?" + params.Encode()
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	return conn, err
+}
+
+// ReadWebSocket reads the next JSON message from a WebSocket, failing after
+// a few seconds
+func ReadWebSocket(conn *websocket.Conn) (map[string]interface{}, error) {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var message map[string]interface{}
+	err := conn.ReadJSON(&message)
+	return message, err
+}
+
 // GenerateTestJWT creates a valid JWT token for testing
 func GenerateTestJWT(userID uint, username, email string, expiryHours ...int) string {
 
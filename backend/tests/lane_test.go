@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type LaneTestSuite struct {
+	suite.Suite
+}
+
+// groupCards returns the IDs of the cards in a board group's list
+func groupCards(group map[string]interface{}, listID uint) []uint {
+	var ids []uint
+	for _, item := range group["lists"].([]interface{}) {
+		cell := item.(map[string]interface{})
+		if uint(cell["list_id"].(float64)) != listID {
+			continue
+		}
+		for _, card := range cell["cards"].([]interface{}) {
+			ids = append(ids, uint(card.(map[string]interface{})["id"].(float64)))
+		}
+	}
+	return ids
+}
+
+// Test cards are created in and moved between lanes, and grouped by lane
+func (suite *LaneTestSuite) TestMoveCard_BetweenLanes() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	laneIDs := make([]uint, 2)
+	for i, title := range []string{"Frontend", "Backend"} {
+		response := POST(fmt.Sprintf("/boards/%d/lanes", board.ID), map[string]interface{}{"title": title}, token)
+		suite.Equal(201, response.StatusCode)
+		laneIDs[i] = uint(response.Body["id"].(float64))
+	}
+
+	response := POST("/cards", map[string]interface{}{"title": "Login page", "list_id": list.ID, "lane_id": laneIDs[0]}, token)
+	suite.Equal(201, response.StatusCode)
+	cardID := uint(response.Body["id"].(float64))
+	loose := Factory.CreateCard(list.ID)
+
+	response = POST(fmt.Sprintf("/cards/%d/move", cardID), map[string]interface{}{
+		"list_id":  list.ID,
+		"position": 0,
+		"lane_id":  laneIDs[1],
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(laneIDs[1]), response.Body["lane_id"])
+
+	response = GET(fmt.Sprintf("/boards/%d?group_by=lane", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	groups := response.Body["groups"].([]interface{})
+	suite.Len(groups, 3)
+	suite.Empty(groupCards(groups[0].(map[string]interface{}), list.ID))
+	suite.Equal([]uint{cardID}, groupCards(groups[1].(map[string]interface{}), list.ID))
+	suite.Equal([]uint{loose.ID}, groupCards(groups[2].(map[string]interface{}), list.ID))
+
+	// Lanes of other boards are refused
+	otherLane := models.Lane{BoardID: Factory.CreateBoard(owner.ID).ID, Title: "Elsewhere"}
+	database.DB.Create(&otherLane)
+	response = POST(fmt.Sprintf("/cards/%d/move", cardID), map[string]interface{}{
+		"list_id":  list.ID,
+		"position": 0,
+		"lane_id":  otherLane.ID,
+	}, token)
+	suite.Equal(400, response.StatusCode)
+
+	// Deleting a lane moves its cards to the default lane
+	suite.Equal(200, DELETE(fmt.Sprintf("/lanes/%d", laneIDs[1]), token).StatusCode)
+	var card models.Card
+	database.DB.First(&card, cardID)
+	suite.Nil(card.LaneID)
+}
+
+// Test a board can be grouped by label, with cards in every label's row
+func (suite *LaneTestSuite) TestGetBoard_GroupByLabel() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	labeled := createLabeledCard(board.ID, list.ID, "bug")
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := GET(fmt.Sprintf("/boards/%d?group_by=label", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal("label", response.Body["group_by"])
+	groups := response.Body["groups"].([]interface{})
+	suite.Len(groups, 2)
+	suite.Equal("bug", groups[0].(map[string]interface{})["title"])
+	suite.Equal([]uint{labeled.ID}, groupCards(groups[0].(map[string]interface{}), list.ID))
+
+	suite.Equal(400, GET(fmt.Sprintf("/boards/%d?group_by=colour", board.ID), token).StatusCode)
+}
+
+// Test lane positions stay numbered 0 to n-1 through creates, moves and deletes
+func (suite *LaneTestSuite) TestLanePositions_StayContiguous() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	ids := make(map[string]uint)
+	for _, title := range []string{"A", "B", "C"} {
+		response := POST(fmt.Sprintf("/boards/%d/lanes", board.ID), map[string]interface{}{"title": title}, token)
+		suite.Equal(201, response.StatusCode)
+		ids[title] = uint(response.Body["id"].(float64))
+	}
+	suite.Equal(200, DELETE(fmt.Sprintf("/lanes/%d", ids["B"]), token).StatusCode)
+
+	response := POST(fmt.Sprintf("/boards/%d/lanes", board.ID), map[string]interface{}{"title": "D"}, token)
+	suite.Equal(201, response.StatusCode)
+	suite.Equal(float64(2), response.Body["position"])
+	ids["D"] = uint(response.Body["id"].(float64))
+
+	response = PUT(fmt.Sprintf("/lanes/%d", ids["D"]), map[string]interface{}{"position": 0}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(0), response.Body["position"])
+
+	response = GET(fmt.Sprintf("/boards/%d/lanes", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	var titles []string
+	for i, item := range response.Body["lanes"].([]interface{}) {
+		lane := item.(map[string]interface{})
+		suite.Equal(float64(i), lane["position"])
+		titles = append(titles, lane["title"].(string))
+	}
+	suite.Equal([]string{"D", "A", "C"}, titles)
+}
+
+func TestLaneTestSuite(t *testing.T) {
+	suite.Run(t, new(LaneTestSuite))
+}
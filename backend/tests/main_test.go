@@ -40,6 +40,23 @@ func TestMain(m *testing.M) {
 
 	
 	log.Println("Cleaning up old test data...")
+	database.DB.Exec("TRUNCATE TABLE board_events CASCADE")
+	database.DB.Exec("TRUNCATE TABLE card_revisions CASCADE")
+	database.DB.Exec("TRUNCATE TABLE calendar_feeds CASCADE")
+	database.DB.Exec("TRUNCATE TABLE sprint_card_spans CASCADE")
+	database.DB.Exec("TRUNCATE TABLE card_label_spans CASCADE")
+	database.DB.Exec("TRUNCATE TABLE sprint_cards CASCADE")
+	database.DB.Exec("TRUNCATE TABLE sprints CASCADE")
+	database.DB.Exec("TRUNCATE TABLE card_transitions CASCADE")
+	database.DB.Exec("TRUNCATE TABLE time_entries CASCADE")
+	database.DB.Exec("TRUNCATE TABLE lanes CASCADE")
+	database.DB.Exec("TRUNCATE TABLE automation_runs CASCADE")
+	database.DB.Exec("TRUNCATE TABLE automation_rules CASCADE")
+	database.DB.Exec("TRUNCATE TABLE card_recurrences CASCADE")
+	database.DB.Exec("TRUNCATE TABLE card_relations CASCADE")
+	database.DB.Exec("TRUNCATE TABLE card_field_values CASCADE")
+	database.DB.Exec("TRUNCATE TABLE custom_fields CASCADE")
+	database.DB.Exec("TRUNCATE TABLE saved_filters CASCADE")
 	database.DB.Exec("TRUNCATE TABLE activities CASCADE")
 	database.DB.Exec("TRUNCATE TABLE attachments CASCADE")
 	database.DB.Exec("TRUNCATE TABLE card_members CASCADE")
@@ -66,6 +83,7 @@ func TestMain(m *testing.M) {
 		&models.Comment{},
 		&models.Label{},
 		&models.CardLabel{},
+		&models.CardLabelSpan{},
 		&models.CardMember{},
 		&models.Attachment{},
 		&models.Activity{},
@@ -73,6 +91,22 @@ func TestMain(m *testing.M) {
 		&models.Permission{},
 		&models.RolePermission{},
 		&models.BoardMember{},
+		&models.SavedFilter{},
+		&models.CustomField{},
+		&models.CardFieldValue{},
+		&models.CardRelation{},
+		&models.CardRecurrence{},
+		&models.AutomationRule{},
+		&models.AutomationRun{},
+		&models.Lane{},
+		&models.TimeEntry{},
+		&models.CardTransition{},
+		&models.Sprint{},
+		&models.SprintCard{},
+		&models.SprintCardSpan{},
+		&models.CalendarFeed{},
+		&models.CardRevision{},
+		&models.BoardEvent{},
 	)
 
 	// Seed roles and permissions
@@ -131,6 +165,23 @@ func TestMain(m *testing.M) {
 	// Drop all tables in reverse order
 	log.Println("Rolling back migrations...")
 	database.DB.Migrator().DropTable(
+		&models.SprintCardSpan{},
+		&models.CardLabelSpan{},
+		&models.BoardEvent{},
+		&models.CardRevision{},
+		&models.CalendarFeed{},
+		&models.SprintCard{},
+		&models.Sprint{},
+		&models.CardTransition{},
+		&models.TimeEntry{},
+		&models.Lane{},
+		&models.AutomationRun{},
+		&models.AutomationRule{},
+		&models.CardRecurrence{},
+		&models.CardRelation{},
+		&models.CardFieldValue{},
+		&models.CustomField{},
+		&models.SavedFilter{},
 		&models.BoardMember{},
 		&models.RolePermission{},
 		&models.Permission{},
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type MyWorkTestSuite struct {
+	suite.Suite
+}
+
+// Test a member who owns no boards sees their cards, mentions and timer
+func (suite *MyWorkTestSuite) TestMyWork_MemberBoards() {
+	owner := Factory.CreateUser()
+	member := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	Factory.CreateBoardMember(board.ID, member.ID, "member")
+	list := Factory.CreateList(board.ID)
+	token := GenerateTestJWT(member.ID, member.Username, member.Email)
+
+	overdue := Factory.CreateCard(list.ID)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	database.DB.Model(overdue).Update("due_date", yesterday)
+	undated := Factory.CreateCard(list.ID)
+	unassigned := Factory.CreateCard(list.ID)
+	for _, card := range []*models.Card{overdue, undated} {
+		database.DB.Create(&models.CardMember{CardID: card.ID, UserID: member.ID})
+	}
+	database.DB.Model(unassigned).Update("due_date", yesterday)
+
+	database.DB.Create(&models.Comment{CardID: undated.ID, UserID: owner.ID, Content: fmt.Sprintf("@%s can you take this?", member.Username)})
+	database.DB.Create(&models.Comment{CardID: undated.ID, UserID: owner.ID, Content: fmt.Sprintf("@%sx is someone else", member.Username)})
+
+	response := POST(fmt.Sprintf("/cards/%d/time/start", undated.ID), nil, token)
+	suite.Equal(201, response.StatusCode)
+
+	response = GET("/me/work?tz=Europe/London", token)
+	suite.Equal(200, response.StatusCode)
+	cards := response.Body["cards"].(map[string]interface{})
+	suite.Len(cards["overdue"], 1)
+	suite.Equal(float64(overdue.ID), cards["overdue"].([]interface{})[0].(map[string]interface{})["id"])
+	suite.Len(cards["no_date"], 1)
+	suite.Len(cards["today"], 0)
+	suite.Len(response.Body["mentions"], 1)
+	suite.Len(response.Body["running_timers"], 1)
+
+	// The older endpoints cover member boards too
+	response = GET("/search/overdue", token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(2), response.Body["count"])
+
+	response = GET("/me/work?tz=Mars/Olympus", token)
+	suite.Equal(400, response.StatusCode)
+}
+
+func TestMyWorkTestSuite(t *testing.T) {
+	suite.Run(t, new(MyWorkTestSuite))
+}
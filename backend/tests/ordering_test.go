@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type OrderingTestSuite struct {
+	suite.Suite
+}
+
+// cardIDsInOrder returns the IDs of a list's cards in display order
+func cardIDsInOrder(listID uint) []uint {
+	var ids []uint
+	database.DB.Model(&models.Card{}).
+		Where("list_id = ?", listID).
+		Order("rank ASC, id ASC").
+		Pluck("id", &ids)
+	return ids
+}
+
+// Test moving a card only rewrites the moved card's rank
+func (suite *OrderingTestSuite) TestMoveCard_OnlyWritesMovedRow() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	a := Factory.CreateCard(list.ID)
+	b := Factory.CreateCard(list.ID)
+	c := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/cards/%d/move", c.ID), map[string]interface{}{
+		"list_id":  list.ID,
+		"position": 0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	suite.Equal([]uint{c.ID, a.ID, b.ID}, cardIDsInOrder(list.ID))
+
+	var unchanged models.Card
+	database.DB.First(&unchanged, a.ID)
+	suite.Equal(a.Rank, unchanged.Rank)
+	database.DB.First(&unchanged, b.ID)
+	suite.Equal(b.Rank, unchanged.Rank)
+}
+
+// Test moving a card into the middle of another list
+func (suite *OrderingTestSuite) TestMoveCard_BetweenLists() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	source := Factory.CreateList(board.ID)
+	dest := Factory.CreateList(board.ID)
+	moved := Factory.CreateCard(source.ID)
+	first := Factory.CreateCard(dest.ID)
+	second := Factory.CreateCard(dest.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/cards/%d/move", moved.ID), map[string]interface{}{
+		"list_id":  dest.ID,
+		"position": 1,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	suite.Empty(cardIDsInOrder(source.ID))
+	suite.Equal([]uint{first.ID, moved.ID, second.ID}, cardIDsInOrder(dest.ID))
+}
+
+// Test positions count only the active cards clients are shown
+func (suite *OrderingTestSuite) TestMoveCard_IgnoresArchivedCards() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	a := Factory.CreateCard(list.ID)
+	archived := Factory.CreateCard(list.ID)
+	b := Factory.CreateCard(list.ID)
+	c := Factory.CreateCard(list.ID)
+	database.DB.Model(archived).Update("archived_at", time.Now())
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/cards/%d/move", c.ID), map[string]interface{}{
+		"list_id":  list.ID,
+		"position": 0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal([]uint{c.ID, a.ID, archived.ID, b.ID}, cardIDsInOrder(list.ID))
+
+	// Last of the two other active cards
+	response = POST(fmt.Sprintf("/cards/%d/move", c.ID), map[string]interface{}{
+		"list_id":       list.ID,
+		"position":      2,
+		"from_position": 0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal([]uint{a.ID, archived.ID, b.ID, c.ID}, cardIDsInOrder(list.ID))
+
+	// b is shown second, not third
+	response = POST(fmt.Sprintf("/cards/%d/move", b.ID), map[string]interface{}{
+		"list_id":       list.ID,
+		"position":      0,
+		"from_position": 2,
+	}, token)
+	suite.Equal(409, response.StatusCode)
+	suite.Equal(float64(1), response.Body["position"])
+}
+
+// Test a move based on an outdated version is rejected with the current state
+func (suite *OrderingTestSuite) TestMoveCard_StaleVersion() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	a := Factory.CreateCard(list.ID)
+	b := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/cards/%d/move", b.ID), map[string]interface{}{
+		"list_id":  list.ID,
+		"position": 0,
+		"version":  b.Version,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	// Second client still holds the old version
+	response = POST(fmt.Sprintf("/cards/%d/move", b.ID), map[string]interface{}{
+		"list_id":  list.ID,
+		"position": 1,
+		"version":  b.Version,
+	}, token)
+	suite.Equal(409, response.StatusCode)
+	suite.Equal(float64(0), response.Body["position"])
+
+	current := response.Body["card"].(map[string]interface{})
+	suite.Equal(float64(b.Version+1), current["version"])
+	suite.Equal([]uint{b.ID, a.ID}, cardIDsInOrder(list.ID))
+}
+
+// Test edits that re-rank a card and moves of it can run at the same time
+// without deadlocking
+func (suite *OrderingTestSuite) TestUpdateAndMoveCard_Concurrent() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(todo.ID)
+	Factory.CreateCard(todo.ID)
+	Factory.CreateCard(done.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			response := PUT(fmt.Sprintf("/cards/%d", card.ID), map[string]interface{}{"position": i % 2}, token)
+			statuses <- response.StatusCode
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			listID := todo.ID
+			if i%2 == 0 {
+				listID = done.ID
+			}
+			response := POST(fmt.Sprintf("/cards/%d/move", card.ID), map[string]interface{}{"list_id": listID, "position": 0}, token)
+			statuses <- response.StatusCode
+		}(i)
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		suite.Equal(200, status)
+	}
+}
+
+// Test repairing a list replaces duplicate ranks and keeps the order
+func (suite *OrderingTestSuite) TestRepairListOrder() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	a := Factory.CreateCard(list.ID)
+	b := Factory.CreateCard(list.ID)
+	c := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	// Corrupt the order: a and b share a rank, c sorts first
+	database.DB.Model(&models.Card{}).Where("id IN ?", []uint{a.ID, b.ID}).UpdateColumn("rank", "m")
+	database.DB.Model(&models.Card{}).Where("id = ?", c.ID).UpdateColumn("rank", "a")
+
+	response := POST(fmt.Sprintf("/lists/%d/repair", list.ID), nil, token)
+	suite.Equal(200, response.StatusCode)
+
+	suite.Equal([]uint{c.ID, a.ID, b.ID}, cardIDsInOrder(list.ID))
+	var ranks []string
+	database.DB.Model(&models.Card{}).Where("list_id = ?", list.ID).Order("rank ASC").Pluck("rank", &ranks)
+	suite.Len(ranks, 3)
+	suite.True(ranks[0] < ranks[1] && ranks[1] < ranks[2])
+
+	outsider := Factory.CreateUser()
+	response = POST(fmt.Sprintf("/lists/%d/repair", list.ID), nil, GenerateTestJWT(outsider.ID, outsider.Username, outsider.Email))
+	suite.Equal(403, response.StatusCode)
+	suite.Equal(404, POST("/lists/999999/repair", nil, token).StatusCode)
+}
+
+// Test rank keys always sort between their neighbours
+func (suite *OrderingTestSuite) TestRankBetween() {
+	keys := utils.RankSequence(3)
+	suite.Len(keys, 3)
+
+	mid, err := utils.RankBetween(keys[0], keys[1])
+	suite.NoError(err)
+	suite.True(keys[0] < mid && mid < keys[1])
+
+	first, err := utils.RankBetween("", keys[0])
+	suite.NoError(err)
+	suite.True(first < keys[0])
+
+	last, err := utils.RankBetween(keys[2], "")
+	suite.NoError(err)
+	suite.True(last > keys[2])
+
+	_, err = utils.RankBetween(keys[1], keys[0])
+	suite.ErrorIs(err, utils.ErrRankOrder)
+}
+
+func TestOrderingTestSuite(t *testing.T) {
+	suite.Run(t, new(OrderingTestSuite))
+}
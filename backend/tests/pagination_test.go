@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaginationTestSuite struct {
+	suite.Suite
+}
+
+// Test paging through comments with the cursor returns each one once, in order
+func (suite *PaginationTestSuite) TestGetComments_Cursor() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	var ids []float64
+	for i := 0; i < 5; i++ {
+		comment := models.Comment{Content: fmt.Sprintf("Comment %d", i), CardID: card.ID, UserID: owner.ID}
+		database.DB.Create(&comment)
+		ids = append(ids, float64(comment.ID))
+	}
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	var seen []float64
+	endpoint := fmt.Sprintf("/comments/card/%d?limit=2", card.ID)
+	for page := 0; page < 5; page++ {
+		response := GET(endpoint, token)
+		suite.Equal(200, response.StatusCode)
+		for _, comment := range response.Body["comments"].([]interface{}) {
+			seen = append(seen, comment.(map[string]interface{})["id"].(float64))
+		}
+
+		cursor, ok := response.Body["next_cursor"].(string)
+		if !ok {
+			suite.Equal(false, response.Body["has_more"])
+			break
+		}
+		endpoint = fmt.Sprintf("/comments/card/%d?limit=2&after=%s", card.ID, cursor)
+	}
+	suite.Equal(ids, seen)
+}
+
+// Test sort and field selection on the board list
+func (suite *PaginationTestSuite) TestGetBoards_SortAndFields() {
+	owner := Factory.CreateUser()
+	first := Factory.CreateBoard(owner.ID)
+	second := Factory.CreateBoard(owner.ID)
+	database.DB.Model(first).Update("title", "Alpha")
+	database.DB.Model(second).Update("title", "Beta")
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := GET("/boards?sort=title&fields=id,title", token)
+	suite.Equal(200, response.StatusCode)
+
+	boards := response.Body["boards"].([]interface{})
+	suite.Len(boards, 2)
+	board := boards[0].(map[string]interface{})
+	suite.Equal("Alpha", board["title"])
+	suite.Len(board, 2)
+
+	suite.Equal(400, GET("/boards?sort=owner", token).StatusCode)
+}
+
+// Test a request without limit or cursor still returns every comment
+func (suite *PaginationTestSuite) TestGetComments_UnboundedByDefault() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	for i := 0; i < 150; i++ {
+		database.DB.Create(&models.Comment{Content: fmt.Sprintf("Comment %d", i), CardID: card.ID, UserID: owner.ID})
+	}
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := GET(fmt.Sprintf("/comments/card/%d", card.ID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Len(response.Body["comments"].([]interface{}), 150)
+	suite.Nil(response.Body["limit"])
+	suite.Equal(false, response.Body["has_more"])
+
+	response = GET(fmt.Sprintf("/comments/card/%d?limit=100", card.ID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Len(response.Body["comments"].([]interface{}), 100)
+	suite.Equal(float64(100), response.Body["limit"])
+	suite.Equal(true, response.Body["has_more"])
+}
+
+func TestPaginationTestSuite(t *testing.T) {
+	suite.Run(t, new(PaginationTestSuite))
+}
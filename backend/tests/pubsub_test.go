@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	ws "github.com/ChukwukaRosemary23/flowboard-backend/internal/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type PubSubTestSuite struct {
+	suite.Suite
+	stop []func()
+}
+
+// TearDownTest stops the hubs and listeners the test started
+func (suite *PubSubTestSuite) TearDownTest() {
+	for _, stop := range suite.stop {
+		stop()
+	}
+	suite.stop = nil
+}
+
+// startHub runs a hub as a separate API instance would, sharing broadcasts
+// through the test database, and waits for it to listen
+func (suite *PubSubTestSuite) startHub() *ws.Hub {
+	eventLog := &services.EventLogService{DB: database.DB}
+	pubSub := &services.PubSubService{DB: database.DB, Events: eventLog}
+	hub := ws.NewHub()
+	hub.Events = eventLog
+	hub.PubSub = pubSub
+	go hub.Run()
+	suite.stop = append(suite.stop, hub.Stop, pubSub.Close)
+
+	select {
+	case <-pubSub.Ready():
+	case <-time.After(5 * time.Second):
+		suite.FailNow("Listener did not start")
+	}
+	return hub
+}
+
+// readSent reads the next message queued for a client
+func (suite *PubSubTestSuite) readSent(client *ws.Client) map[string]interface{} {
+	select {
+	case raw := <-client.Send:
+		var message map[string]interface{}
+		suite.Require().NoError(json.Unmarshal(raw, &message))
+		return message
+	case <-time.After(5 * time.Second):
+		suite.FailNow("No message received")
+		return nil
+	}
+}
+
+// readBroadcast reads the next message queued for a client that is not a
+// presence update, which may come from either hub
+func (suite *PubSubTestSuite) readBroadcast(client *ws.Client) map[string]interface{} {
+	for {
+		message := suite.readSent(client)
+		if !strings.HasPrefix(message["type"].(string), "presence_") {
+			return message
+		}
+	}
+}
+
+// Test a broadcast on one hub reaches the clients of another
+func (suite *PubSubTestSuite) TestTwoHubs_ShareBroadcasts() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+
+	first, second := suite.startHub(), suite.startHub()
+
+	local := ws.NewClient(first, nil, owner.ID, owner.Username)
+	remote := ws.NewClient(second, nil, owner.ID, owner.Username)
+	suite.True(first.Join(local, board.ID, nil))
+	suite.True(second.Join(remote, board.ID, nil))
+	suite.Equal("synced", suite.readBroadcast(local)["type"])
+	suite.Equal("synced", suite.readBroadcast(remote)["type"])
+
+	first.BroadcastToBoard(board.ID, "card_updated", map[string]interface{}{"title": "Shared"})
+
+	for _, client := range []*ws.Client{local, remote} {
+		message := suite.readBroadcast(client)
+		suite.Equal("card_updated", message["type"])
+		suite.Equal(float64(1), message["seq"])
+		suite.Equal("Shared", message["data"].(map[string]interface{})["title"])
+	}
+
+	// Too large to notify, so read back from the event log
+	description := strings.Repeat("x", 10000)
+	second.BroadcastToBoard(board.ID, "card_updated", map[string]interface{}{"description": description})
+	message := suite.readBroadcast(local)
+	suite.Equal(float64(2), message["seq"])
+	suite.Equal(description, message["data"].(map[string]interface{})["description"])
+	suite.Equal(float64(2), suite.readBroadcast(remote)["seq"])
+}
+
+// Test each hub reports the viewers connected to the other
+func (suite *PubSubTestSuite) TestTwoHubs_SharePresence() {
+	owner := Factory.CreateUser()
+	member := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+
+	first, second := suite.startHub(), suite.startHub()
+
+	local := ws.NewClient(first, nil, owner.ID, owner.Username)
+	remote := ws.NewClient(second, nil, member.ID, member.Username)
+	suite.True(first.Join(local, board.ID, nil))
+	suite.True(second.Join(remote, board.ID, nil))
+
+	viewing := func(hub *ws.Hub, userIDs ...uint) func() bool {
+		return func() bool {
+			viewers := hub.Presence(board.ID)
+			if len(viewers) != len(userIDs) {
+				return false
+			}
+			for i, viewer := range viewers {
+				if viewer.UserID != userIDs[i] {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	suite.Eventually(viewing(first, owner.ID, member.ID), 5*time.Second, 20*time.Millisecond)
+	suite.Eventually(viewing(second, owner.ID, member.ID), 5*time.Second, 20*time.Millisecond)
+
+	// The local client is told the remote user joined
+	for {
+		message := suite.readSent(local)
+		if message["type"] == "presence_joined" && message["data"].(map[string]interface{})["user_id"] == float64(member.ID) {
+			break
+		}
+	}
+
+	second.Leave(remote, board.ID)
+	suite.Eventually(viewing(first, owner.ID), 5*time.Second, 20*time.Millisecond)
+}
+
+// Test a client still sees every event in order when a notification from
+// another instance arrives ahead of an earlier one
+func (suite *PubSubTestSuite) TestDeliver_FillsGapsInOrder() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	eventLog := &services.EventLogService{DB: database.DB}
+
+	pubSub := ws.NewMemoryPubSub()
+	hub := ws.NewHub()
+	hub.Events = eventLog
+	hub.PubSub = pubSub
+	go hub.Run()
+	suite.stop = append(suite.stop, hub.Stop)
+
+	client := ws.NewClient(hub, nil, owner.ID, owner.Username)
+	suite.True(hub.Join(client, board.ID, nil))
+	suite.Equal("synced", suite.readBroadcast(client)["type"])
+
+	// Two events logged by other instances, notified out of order
+	for _, title := range []string{"First", "Second"} {
+		_, err := eventLog.Append(board.ID, "card_updated", map[string]interface{}{"title": title})
+		suite.Require().NoError(err)
+	}
+	second, err := eventLog.Event(board.ID, 2)
+	suite.Require().NoError(err)
+	first, err := eventLog.Event(board.ID, 1)
+	suite.Require().NoError(err)
+	suite.Require().NoError(pubSub.Publish(second))
+	suite.Require().NoError(pubSub.Publish(first))
+	hub.BroadcastToBoard(board.ID, "card_updated", map[string]interface{}{"title": "Third"})
+
+	for seq, title := range []string{"First", "Second", "Third"} {
+		message := suite.readBroadcast(client)
+		suite.Equal(float64(seq+1), message["seq"])
+		suite.Equal(title, message["data"].(map[string]interface{})["title"])
+	}
+}
+
+func TestPubSubTestSuite(t *testing.T) {
+	suite.Run(t, new(PubSubTestSuite))
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecurrenceTestSuite struct {
+	suite.Suite
+}
+
+// Test a weekly recurrence lists its next occurrences on the chosen weekdays
+func (suite *RecurrenceTestSuite) TestSetCardRecurrence_Weekly() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := PUT(fmt.Sprintf("/cards/%d/recurrence", card.ID), map[string]interface{}{
+		"frequency":   "weekly",
+		"weekdays":    []int{1},
+		"time_of_day": "08:30",
+		"timezone":    "Europe/Berlin",
+	}, token)
+	suite.Equal(201, response.StatusCode)
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	for _, value := range response.Body["upcoming"].([]interface{}) {
+		at, err := time.Parse(time.RFC3339, value.(string))
+		suite.NoError(err)
+		suite.Equal(time.Monday, at.In(berlin).Weekday())
+		suite.Equal(8, at.In(berlin).Hour())
+	}
+
+	response = PUT(fmt.Sprintf("/cards/%d/recurrence", card.ID), map[string]interface{}{
+		"frequency": "cron",
+		"cron":      "61 * * * *",
+	}, token)
+	suite.Equal(400, response.StatusCode)
+}
+
+// Test the scheduler copies a due card once, with labels and a shifted due date
+func (suite *RecurrenceTestSuite) TestRunDue_CopiesOnce() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	template := createLabeledCard(board.ID, list.ID, "chore")
+	due := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	database.DB.Model(template).Update("due_date", due)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	startsAt := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	response := PUT(fmt.Sprintf("/cards/%d/recurrence", template.ID), map[string]interface{}{
+		"frequency": "daily",
+		"starts_at": startsAt,
+	}, token)
+	suite.Equal(201, response.StatusCode)
+
+	// Pretend the scheduler was down for the last few occurrences
+	database.DB.Model(&models.CardRecurrence{}).Where("card_id = ?", template.ID).Update("next_run_at", startsAt)
+
+	recurrenceService := &services.RecurrenceService{}
+	created, err := recurrenceService.RunDue(database.DB, time.Now(), 10)
+	suite.NoError(err)
+	suite.Len(created, 1)
+	created, err = recurrenceService.RunDue(database.DB, time.Now(), 10)
+	suite.NoError(err)
+	suite.Len(created, 0)
+
+	var instance models.Card
+	database.DB.Preload("Labels").Where("recurrence_id IS NOT NULL AND list_id = ?", list.ID).First(&instance)
+	suite.Equal(template.Title, instance.Title)
+	suite.Len(instance.Labels, 1)
+	suite.True(instance.DueDate.Equal(due.Add(instance.OccurrenceAt.Sub(startsAt))))
+
+	// Paused schedules do not run
+	suite.Equal(200, POST(fmt.Sprintf("/cards/%d/recurrence/pause", template.ID), nil, token).StatusCode)
+	database.DB.Model(&models.CardRecurrence{}).Where("card_id = ?", template.ID).Update("next_run_at", startsAt)
+	created, _ = recurrenceService.RunDue(database.DB, time.Now(), 10)
+	suite.Len(created, 0)
+}
+
+func TestRecurrenceTestSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceTestSuite))
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type SavedFilterTestSuite struct {
+	suite.Suite
+}
+
+// createLabeledCard creates a card on list carrying a label with the given name
+func createLabeledCard(boardID, listID uint, labelName string) *models.Card {
+	card := Factory.CreateCard(listID)
+	label := models.Label{Name: labelName, Color: "#FF5733", BoardID: boardID}
+	database.DB.Create(&label)
+	database.DB.Create(&models.CardLabel{CardID: card.ID, LabelID: label.ID})
+	return card
+}
+
+// Test field filters, boolean operators and member:me in the query grammar
+func (suite *SavedFilterTestSuite) TestSearchCards_QueryGrammar() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	database.DB.Model(done).Update("title", "Done")
+
+	mine := createLabeledCard(board.ID, todo.ID, "bug")
+	database.DB.Create(&models.CardMember{CardID: mine.ID, UserID: owner.ID})
+	finished := createLabeledCard(board.ID, done.ID, "bug")
+	database.DB.Create(&models.CardMember{CardID: finished.ID, UserID: owner.ID})
+	createLabeledCard(board.ID, todo.ID, "bug") // Not assigned
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	query := url.QueryEscape("label:bug AND member:me NOT list:Done")
+	response := GET("/search/cards?q="+query, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["count"])
+	card := response.Body["cards"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(float64(mine.ID), card["id"])
+
+	response = GET("/search/cards?q="+url.QueryEscape("label:bug AND (due<"), token)
+	suite.Equal(400, response.StatusCode)
+}
+
+// Test a saved filter can be run, shown on the dashboard and read as RSS
+func (suite *SavedFilterTestSuite) TestSavedFilter_DashboardAndFeed() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := createLabeledCard(board.ID, list.ID, "urgent")
+	Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST("/filters", map[string]interface{}{
+		"name":              "Urgent",
+		"query":             "label:urgent",
+		"show_on_dashboard": true,
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	filterID := response.Body["id"].(float64)
+
+	response = GET(fmt.Sprintf("/filters/%d/cards", int(filterID)), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["count"])
+
+	response = GET("/dashboard", token)
+	suite.Equal(200, response.StatusCode)
+	panel := response.Body["panels"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(float64(1), panel["count"])
+
+	response = POST(fmt.Sprintf("/filters/%d/feed", int(filterID)), nil, token)
+	suite.Equal(200, response.StatusCode)
+	feedURL := response.Body["feed_url"].(string)
+	feedPath := feedURL[strings.Index(feedURL, "/feeds/"):]
+
+	response = GET(feedPath)
+	suite.Equal(200, response.StatusCode)
+	suite.Contains(response.RawBody, "<title>"+card.Title+"</title>")
+
+	// Revoking the feed invalidates the URL
+	suite.Equal(200, DELETE(fmt.Sprintf("/filters/%d/feed", int(filterID)), token).StatusCode)
+	suite.Equal(404, GET(feedPath).StatusCode)
+}
+
+// Test a filter with an invalid query is rejected
+func (suite *SavedFilterTestSuite) TestCreateSavedFilter_InvalidQuery() {
+	owner := Factory.CreateUser()
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST("/filters", map[string]interface{}{
+		"name":  "Broken",
+		"query": "due<someday",
+	}, token)
+	suite.Equal(400, response.StatusCode)
+}
+
+func TestSavedFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(SavedFilterTestSuite))
+}
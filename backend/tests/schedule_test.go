@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleTestSuite struct {
+	suite.Suite
+}
+
+// Test the calendar groups due cards into every period of the range
+func (suite *ScheduleTestSuite) TestCalendar_GroupsByWeek() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	monday := Factory.CreateCard(list.ID)
+	sunday := Factory.CreateCard(list.ID)
+	later := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	// 2030-04-01 is a Monday
+	database.DB.Model(monday).Update("due_date", time.Date(2030, 4, 1, 9, 0, 0, 0, time.UTC))
+	database.DB.Model(sunday).Update("due_date", time.Date(2030, 4, 7, 23, 0, 0, 0, time.UTC))
+	database.DB.Model(later).Update("due_date", time.Date(2030, 4, 16, 12, 0, 0, 0, time.UTC))
+
+	response := GET(fmt.Sprintf("/boards/%d/calendar?from=2030-04-01&to=2030-04-21&group_by=week", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(3), response.Body["total"])
+	periods := response.Body["periods"].([]interface{})
+	suite.Len(periods, 3)
+
+	first := periods[0].(map[string]interface{})
+	suite.Equal("2030-04-01", first["start"])
+	suite.Equal("2030-04-07", first["end"])
+	suite.Len(first["cards"], 2)
+	suite.Len(periods[1].(map[string]interface{})["cards"], 0)
+	suite.Len(periods[2].(map[string]interface{})["cards"], 1)
+
+	response = GET(fmt.Sprintf("/boards/%d/calendar?group_by=year", board.ID), token)
+	suite.Equal(400, response.StatusCode)
+}
+
+// Test the timeline spans start to due and includes blocking edges
+func (suite *ScheduleTestSuite) TestTimeline_Dependencies() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	design := Factory.CreateCard(list.ID)
+	build := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	database.DB.Model(design).Updates(map[string]interface{}{
+		"start_date": time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC),
+		"due_date":   time.Date(2030, 5, 5, 0, 0, 0, 0, time.UTC),
+	})
+	database.DB.Model(build).Update("start_date", time.Date(2030, 5, 6, 0, 0, 0, 0, time.UTC))
+
+	response := POST(fmt.Sprintf("/cards/%d/relations", design.ID), map[string]interface{}{
+		"card_id": build.ID,
+		"type":    "blocks",
+	}, token)
+	suite.Equal(201, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/boards/%d/timeline?from=2030-05-01&to=2030-05-31", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	cards := response.Body["cards"].([]interface{})
+	suite.Len(cards, 2)
+	suite.Equal(float64(design.ID), cards[0].(map[string]interface{})["id"])
+	suite.Equal(true, cards[1].(map[string]interface{})["blocked"])
+
+	dependencies := response.Body["dependencies"].([]interface{})
+	suite.Len(dependencies, 1)
+	suite.Equal(float64(design.ID), dependencies[0].(map[string]interface{})["source_card_id"])
+	suite.Equal(float64(build.ID), dependencies[0].(map[string]interface{})["target_card_id"])
+}
+
+// Test rescheduling moves dates, checks their order and honours the version
+func (suite *ScheduleTestSuite) TestRescheduleCard() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	start := time.Date(2030, 6, 3, 9, 0, 0, 0, time.UTC)
+	response := PATCH(fmt.Sprintf("/cards/%d/schedule", card.ID), map[string]interface{}{
+		"start_date": start,
+		"due_date":   start.Add(48 * time.Hour),
+		"version":    card.Version,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(card.Version+1), response.Body["version"])
+	suite.NotNil(response.Body["start_date"])
+
+	// The start cannot move past the due date
+	response = PATCH(fmt.Sprintf("/cards/%d/schedule", card.ID), map[string]interface{}{
+		"start_date": start.Add(72 * time.Hour),
+	}, token)
+	suite.Equal(400, response.StatusCode)
+
+	// A stale version is refused
+	response = PATCH(fmt.Sprintf("/cards/%d/schedule", card.ID), map[string]interface{}{
+		"clear_start_date": true,
+		"version":          card.Version,
+	}, token)
+	suite.Equal(409, response.StatusCode)
+
+	response = PATCH(fmt.Sprintf("/cards/%d/schedule", card.ID), map[string]interface{}{
+		"clear_start_date": true,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Nil(response.Body["start_date"])
+	suite.NotNil(response.Body["due_date"])
+}
+
+func TestScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleTestSuite))
+}
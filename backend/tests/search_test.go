@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+}
+
+// Test title matches rank above description matches and are highlighted
+func (suite *SearchTestSuite) TestSearchCards_RankedWithHighlights() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	inDescription := Factory.CreateCard(list.ID)
+	inTitle := Factory.CreateCard(list.ID)
+	database.DB.Model(inDescription).Updates(map[string]interface{}{"title": "Quarterly", "description": "Prepare the invoice totals"})
+	database.DB.Model(inTitle).Updates(map[string]interface{}{"title": "Send invoice", "description": "To finance"})
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := GET("/search/cards?q=invoices", token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(2), response.Body["count"])
+
+	cards := response.Body["cards"].([]interface{})
+	first := cards[0].(map[string]interface{})
+	suite.Equal(float64(inTitle.ID), first["id"])
+	highlights := first["highlights"].(map[string]interface{})
+	suite.Equal("Send <mark>invoice</mark>", highlights["title"])
+}
+
+// Test comments are searched and results cover boards the user is a member of
+func (suite *SearchTestSuite) TestSearchCards_CommentOnMemberBoard() {
+	owner := Factory.CreateUser()
+	member := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	Factory.CreateBoardMember(board.ID, member.ID, "member")
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	database.DB.Create(&models.Comment{Content: "Waiting on the vendor contract", CardID: card.ID, UserID: owner.ID})
+	token := GenerateTestJWT(member.ID, member.Username, member.Email)
+
+	response := GET("/search/cards?q=contract", token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["count"])
+
+	result := response.Body["cards"].([]interface{})[0].(map[string]interface{})
+	suite.Equal(float64(card.ID), result["id"])
+	suite.Contains(result["highlights"].(map[string]interface{})["comment"], "<mark>contract</mark>")
+}
+
+// Test paging with the cursor returns every match exactly once
+func (suite *SearchTestSuite) TestSearchCards_CursorPagination() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	for i := 0; i < 5; i++ {
+		card := Factory.CreateCard(list.ID)
+		database.DB.Model(card).Update("title", fmt.Sprintf("Release checklist %d", i))
+	}
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	seen := map[float64]bool{}
+	endpoint := fmt.Sprintf("/search/cards?q=release&board=%d&limit=2", board.ID)
+	for page := 0; page < 3; page++ {
+		response := GET(endpoint, token)
+		suite.Equal(200, response.StatusCode)
+		for _, card := range response.Body["cards"].([]interface{}) {
+			seen[card.(map[string]interface{})["id"].(float64)] = true
+		}
+
+		cursor, ok := response.Body["next_cursor"].(string)
+		if !ok {
+			break
+		}
+		endpoint = fmt.Sprintf("/search/cards?q=release&board=%d&limit=2&cursor=%s", board.ID, cursor)
+	}
+	suite.Len(seen, 5)
+}
+
+// Test a negated date filter includes cards without a date
+func (suite *SearchTestSuite) TestSearchCards_NegatedDueIncludesNoDueDate() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	early := Factory.CreateCard(list.ID)
+	late := Factory.CreateCard(list.ID)
+	undated := Factory.CreateCard(list.ID)
+	database.DB.Model(early).Update("due_date", time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC))
+	database.DB.Model(late).Update("due_date", time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC))
+	database.DB.Model(undated).Update("due_date", nil)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	searchIDs := func(query string) []float64 {
+		response := GET(fmt.Sprintf("/search/cards?board=%d&q=%s", board.ID, url.QueryEscape(query)), token)
+		suite.Require().Equal(200, response.StatusCode)
+		var ids []float64
+		for _, card := range response.Body["cards"].([]interface{}) {
+			ids = append(ids, card.(map[string]interface{})["id"].(float64))
+		}
+		return ids
+	}
+
+	suite.ElementsMatch([]float64{float64(early.ID)}, searchIDs("due<2024-02-01"))
+	suite.ElementsMatch([]float64{float64(late.ID), float64(undated.ID)}, searchIDs("-due<2024-02-01"))
+	suite.ElementsMatch([]float64{float64(late.ID), float64(undated.ID)}, searchIDs("NOT due<2024-02-01"))
+}
+
+// Test a plain field: term is free text rather than a custom field with no name
+func (suite *SearchTestSuite) TestSearchCards_FieldWithoutNameIsText() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	database.DB.Model(card).Update("title", "Field sizing workshop")
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := GET(fmt.Sprintf("/search/cards?board=%d&q=%s", board.ID, url.QueryEscape("field:sizing")), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["count"])
+	suite.Equal(float64(card.ID), response.Body["cards"].([]interface{})[0].(map[string]interface{})["id"])
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type SprintTestSuite struct {
+	suite.Suite
+}
+
+// Test burnup and burndown follow cards and points through a done-list
+func (suite *SprintTestSuite) TestBurndown_PointsAndLabel() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	database.DB.Model(done).Update("is_done", true)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/fields", board.ID), map[string]interface{}{"name": "Points", "type": "number"}, token)
+	suite.Equal(201, response.StatusCode)
+	fieldID := uint(response.Body["id"].(float64))
+
+	cardIDs := make([]uint, 2)
+	for i, points := range []int{3, 5} {
+		response = POST("/cards", map[string]interface{}{"title": fmt.Sprintf("Story %d", i), "list_id": todo.ID}, token)
+		suite.Equal(201, response.StatusCode)
+		cardIDs[i] = uint(response.Body["id"].(float64))
+		response = PUT(fmt.Sprintf("/cards/%d/fields/%d", cardIDs[i], fieldID), map[string]interface{}{"value": points}, token)
+		suite.Equal(200, response.StatusCode)
+	}
+
+	// A third card joins through the sprint label
+	label := models.Label{Name: "Sprint 1", Color: "#FF5733", BoardID: board.ID}
+	database.DB.Create(&label)
+	database.DB.Create(&models.CardLabel{CardID: Factory.CreateCard(todo.ID).ID, LabelID: label.ID})
+
+	today := time.Now().UTC()
+	response = POST(fmt.Sprintf("/boards/%d/sprints", board.ID), map[string]interface{}{
+		"name":            "Sprint 1",
+		"start_date":      today.Format("2006-01-02"),
+		"end_date":        today.AddDate(0, 0, 13).Format("2006-01-02"),
+		"label_id":        label.ID,
+		"points_field_id": fieldID,
+		"card_ids":        cardIDs,
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	sprintID := uint(response.Body["id"].(float64))
+
+	response = POST(fmt.Sprintf("/cards/%d/move", cardIDs[0]), map[string]interface{}{"list_id": done.ID, "position": 0}, token)
+	suite.Equal(200, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/sprints/%d", sprintID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Len(response.Body["cards"], 3)
+
+	response = GET(fmt.Sprintf("/sprints/%d/burnup", sprintID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal("field", response.Body["points_source"])
+	days := response.Body["days"].([]interface{})
+	suite.Len(days, 14)
+	first := days[0].(map[string]interface{})
+	suite.Equal(float64(3), first["scope"])
+	suite.Equal(float64(1), first["done"])
+	suite.Equal(float64(8), first["scope_points"])
+	suite.Equal(float64(3), first["done_points"])
+	suite.Nil(days[13].(map[string]interface{})["scope"])
+
+	response = GET(fmt.Sprintf("/sprints/%d/burndown", sprintID), token)
+	suite.Equal(200, response.StatusCode)
+	days = response.Body["days"].([]interface{})
+	first = days[0].(map[string]interface{})
+	suite.Equal(float64(2), first["remaining"])
+	suite.Equal(float64(5), first["remaining_points"])
+	suite.Equal(float64(3), first["ideal"])
+	suite.Equal(float64(0), days[13].(map[string]interface{})["ideal"])
+}
+
+// Test taking a card out of a sprint, or its label off a card, only changes
+// the scope from that day on
+func (suite *SprintTestSuite) TestBurnup_RemovalKeepsHistory() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	explicit := Factory.CreateCard(list.ID)
+	labelled := Factory.CreateCard(list.ID)
+	label := models.Label{Name: "Sprint 2", Color: "#FF5733", BoardID: board.ID}
+	database.DB.Create(&label)
+	database.DB.Create(&models.CardLabel{CardID: labelled.ID, LabelID: label.ID})
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	today := time.Now().UTC()
+	response := POST(fmt.Sprintf("/boards/%d/sprints", board.ID), map[string]interface{}{
+		"name":       "Sprint 2",
+		"start_date": today.AddDate(0, 0, -3).Format("2006-01-02"),
+		"end_date":   today.AddDate(0, 0, 10).Format("2006-01-02"),
+		"label_id":   label.ID,
+		"card_ids":   []uint{explicit.ID},
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	sprintID := uint(response.Body["id"].(float64))
+
+	// Both cards have been in the sprint since before it started
+	past := today.AddDate(0, 0, -5)
+	database.DB.Model(&models.Card{}).Where("id IN ?", []uint{explicit.ID, labelled.ID}).UpdateColumn("created_at", past)
+	database.DB.Model(&models.SprintCardSpan{}).Where("sprint_id = ?", sprintID).UpdateColumn("added_at", past)
+	database.DB.Model(&models.CardLabelSpan{}).Where("label_id = ?", label.ID).UpdateColumn("added_at", past)
+
+	suite.Equal(200, DELETE(fmt.Sprintf("/sprints/%d/cards/%d", sprintID, explicit.ID), token).StatusCode)
+	suite.Equal(200, DELETE(fmt.Sprintf("/labels/card/%d/%d", labelled.ID, label.ID), token).StatusCode)
+
+	response = GET(fmt.Sprintf("/sprints/%d/burnup", sprintID), token)
+	suite.Equal(200, response.StatusCode)
+	days := response.Body["days"].([]interface{})
+	for i := 0; i < 3; i++ {
+		suite.Equal(float64(2), days[i].(map[string]interface{})["scope"])
+	}
+	suite.Equal(float64(0), days[3].(map[string]interface{})["scope"])
+}
+
+// Test sprint cards and settings must belong to the sprint's board
+func (suite *SprintTestSuite) TestCreateSprint_Validation() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	other := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(other.ID).ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/boards/%d/sprints", board.ID), map[string]interface{}{
+		"name":       "Wrong board",
+		"start_date": "2025-01-06",
+		"end_date":   "2025-01-19",
+		"card_ids":   []uint{card.ID},
+	}, token)
+	suite.Equal(400, response.StatusCode)
+
+	response = POST(fmt.Sprintf("/boards/%d/sprints", board.ID), map[string]interface{}{
+		"name":       "Backwards",
+		"start_date": "2025-01-19",
+		"end_date":   "2025-01-06",
+	}, token)
+	suite.Equal(400, response.StatusCode)
+}
+
+func TestSprintTestSuite(t *testing.T) {
+	suite.Run(t, new(SprintTestSuite))
+}
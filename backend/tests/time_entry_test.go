@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TimeEntryTestSuite struct {
+	suite.Suite
+}
+
+// Test a user runs one timer at a time and stopping it records the entry
+func (suite *TimeEntryTestSuite) TestTimer_OnePerUser() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	first := Factory.CreateCard(list.ID)
+	second := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/cards/%d/time/start", first.ID), nil, token)
+	suite.Equal(201, response.StatusCode)
+	suite.Equal(true, response.Body["running"])
+	entryID := response.Body["id"]
+
+	// A second timer is refused and the running one is reported
+	response = POST(fmt.Sprintf("/cards/%d/time/start", second.ID), nil, token)
+	suite.Equal(409, response.StatusCode)
+	suite.Equal(entryID, response.Body["running"].(map[string]interface{})["id"])
+
+	response = GET("/time-entries/running", token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(entryID, response.Body["running"].(map[string]interface{})["id"])
+
+	response = POST(fmt.Sprintf("/cards/%d/time/stop", second.ID), nil, token)
+	suite.Equal(404, response.StatusCode)
+
+	response = POST(fmt.Sprintf("/cards/%d/time/stop", first.ID), nil, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(false, response.Body["running"])
+	suite.NotNil(response.Body["ended_at"])
+
+	// Once stopped, a new timer can start
+	response = POST(fmt.Sprintf("/cards/%d/time/start", second.ID), nil, token)
+	suite.Equal(201, response.StatusCode)
+}
+
+// Test manual entries add up against the estimate and on the timesheet
+func (suite *TimeEntryTestSuite) TestManualEntries_Timesheet() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := PUT(fmt.Sprintf("/cards/%d", card.ID), map[string]interface{}{"estimate_minutes": 120}, token)
+	suite.Equal(200, response.StatusCode)
+
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+	response = POST(fmt.Sprintf("/cards/%d/time", card.ID), map[string]interface{}{
+		"started_at": day.Add(9 * time.Hour),
+		"minutes":    45,
+		"note":       "Pairing",
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	suite.Equal(float64(45*60), response.Body["seconds"])
+
+	response = POST(fmt.Sprintf("/cards/%d/time", card.ID), map[string]interface{}{
+		"started_at": day.Add(14 * time.Hour),
+		"ended_at":   day.Add(14*time.Hour + 30*time.Minute),
+	}, token)
+	suite.Equal(201, response.StatusCode)
+
+	// An entry must end after it starts
+	response = POST(fmt.Sprintf("/cards/%d/time", card.ID), map[string]interface{}{
+		"started_at": day.Add(14 * time.Hour),
+		"ended_at":   day.Add(13 * time.Hour),
+	}, token)
+	suite.Equal(400, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/cards/%d/time", card.ID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(75*60), response.Body["total_seconds"])
+	suite.Equal(float64(45*60), response.Body["remaining_seconds"])
+	suite.Len(response.Body["entries"], 2)
+
+	date := day.Format("2006-01-02")
+	response = GET(fmt.Sprintf("/boards/%d/timesheet?from=%s&to=%s", board.ID, date, date), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(75*60), response.Body["total_seconds"])
+	rows := response.Body["rows"].([]interface{})
+	suite.Len(rows, 1)
+	suite.Equal(date, rows[0].(map[string]interface{})["date"])
+
+	response = GET(fmt.Sprintf("/boards/%d/timesheet?from=%s&to=%s&format=csv", board.ID, date, date), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Contains(response.RawBody, "date,user_id,username,card_id,card_title,hours")
+	suite.Contains(response.RawBody, fmt.Sprintf("%s,%d,%s,%d,", date, owner.ID, owner.Username, card.ID))
+	suite.Contains(response.RawBody, ",1.25\n")
+}
+
+// Test users can only change their own entries
+func (suite *TimeEntryTestSuite) TestUpdateEntry_OwnOnly() {
+	owner := Factory.CreateUser()
+	other := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := POST(fmt.Sprintf("/cards/%d/time", card.ID), map[string]interface{}{
+		"started_at": time.Now().Add(-time.Hour),
+		"minutes":    30,
+	}, token)
+	suite.Equal(201, response.StatusCode)
+	entryID := int(response.Body["id"].(float64))
+
+	otherToken := GenerateTestJWT(other.ID, other.Username, other.Email)
+	response = DELETE(fmt.Sprintf("/time-entries/%d", entryID), otherToken)
+	suite.Equal(404, response.StatusCode)
+
+	response = PUT(fmt.Sprintf("/time-entries/%d", entryID), map[string]interface{}{"note": "Review"}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal("Review", response.Body["note"])
+
+	response = DELETE(fmt.Sprintf("/time-entries/%d", entryID), token)
+	suite.Equal(200, response.StatusCode)
+}
+
+func TestTimeEntryTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeEntryTestSuite))
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/services"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashTestSuite struct {
+	suite.Suite
+}
+
+// Test deleting a card puts it in the trash and restoring puts it back in place
+func (suite *TrashTestSuite) TestDeleteAndRestoreCard_RestoresPosition() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	cards := []*models.Card{
+		Factory.CreateCard(list.ID),
+		Factory.CreateCard(list.ID),
+		Factory.CreateCard(list.ID),
+	}
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := DELETE(fmt.Sprintf("/cards/%d", cards[1].ID), token)
+	suite.Equal(200, response.StatusCode)
+
+	response = GET(fmt.Sprintf("/boards/%d/trash", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(1), response.Body["count"])
+
+	item := response.Body["items"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("card", item["type"])
+	suite.Equal(float64(cards[1].ID), item["id"])
+	deletedBy := item["deleted_by"].(map[string]interface{})
+	suite.Equal(owner.Username, deletedBy["username"])
+
+	response = POST(fmt.Sprintf("/boards/%d/trash/card/%d/restore", board.ID, cards[1].ID), nil, token)
+	suite.Equal(200, response.StatusCode)
+
+	var restored []models.Card
+	database.DB.Where("list_id = ?", list.ID).Order("rank ASC, id ASC").Find(&restored)
+	suite.Len(restored, 3)
+	for i, card := range restored {
+		suite.Equal(cards[i].ID, card.ID)
+	}
+}
+
+// Test a card in a trashed list cannot be restored on its own
+func (suite *TrashTestSuite) TestRestoreCard_ListInTrash() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	suite.Equal(200, DELETE(fmt.Sprintf("/cards/%d", card.ID), token).StatusCode)
+	suite.Equal(200, DELETE(fmt.Sprintf("/lists/%d", list.ID), token).StatusCode)
+
+	response := POST(fmt.Sprintf("/boards/%d/trash/card/%d/restore", board.ID, card.ID), nil, token)
+	suite.Equal(409, response.StatusCode)
+
+	response = POST(fmt.Sprintf("/boards/%d/trash/list/%d/restore", board.ID, list.ID), nil, token)
+	suite.Equal(200, response.StatusCode)
+}
+
+// Test viewers cannot restore items
+func (suite *TrashTestSuite) TestRestore_ViewerForbidden() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(list.ID)
+
+	viewer := Factory.CreateUser()
+	Factory.CreateBoardMember(board.ID, viewer.ID, "viewer")
+
+	ownerToken := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+	suite.Equal(200, DELETE(fmt.Sprintf("/cards/%d", card.ID), ownerToken).StatusCode)
+
+	viewerToken := GenerateTestJWT(viewer.ID, viewer.Username, viewer.Email)
+	response := GET(fmt.Sprintf("/boards/%d/trash", board.ID), viewerToken)
+	suite.Equal(200, response.StatusCode)
+
+	response = POST(fmt.Sprintf("/boards/%d/trash/card/%d/restore", board.ID, card.ID), nil, viewerToken)
+	suite.Equal(403, response.StatusCode)
+}
+
+// Test expired trash is hard-deleted and recent trash is kept
+func (suite *TrashTestSuite) TestPurgeExpired() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	expired := Factory.CreateCard(list.ID)
+	recent := Factory.CreateCard(list.ID)
+
+	database.DB.Delete(expired)
+	database.DB.Unscoped().Model(expired).UpdateColumn("deleted_at", time.Now().Add(-48*time.Hour))
+	database.DB.Delete(recent)
+
+	trashService := &services.TrashService{}
+	_, err := trashService.PurgeExpired(24 * time.Hour)
+	suite.NoError(err)
+
+	var count int64
+	database.DB.Unscoped().Model(&models.Card{}).Where("id = ?", expired.ID).Count(&count)
+	suite.Equal(int64(0), count)
+
+	database.DB.Unscoped().Model(&models.Card{}).Where("id = ?", recent.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func TestTrashTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashTestSuite))
+}
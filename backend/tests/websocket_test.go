@@ -0,0 +1,298 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebSocketTestSuite struct {
+	suite.Suite
+}
+
+// Test broadcasts are numbered and replayed to a client reconnecting with since
+func (suite *WebSocketTestSuite) TestReconnect_ReplaysMissedEvents() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	// A fresh connection only learns the latest sequence number
+	conn, err := DialWebSocket(board.ID, token)
+	suite.Require().NoError(err)
+	message, err := ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("synced", message["type"])
+	suite.Equal(float64(0), message["data"].(map[string]interface{})["seq"])
+
+	suite.Equal(201, POST("/cards", map[string]interface{}{"title": "Live", "list_id": list.ID}, token).StatusCode)
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("card_created", message["type"])
+	suite.Equal(float64(1), message["seq"])
+	conn.Close()
+
+	// Missed while disconnected
+	suite.Equal(201, POST("/cards", map[string]interface{}{"title": "Missed", "list_id": list.ID}, token).StatusCode)
+
+	conn, err = DialWebSocket(board.ID, token, "since", "1")
+	suite.Require().NoError(err)
+	defer conn.Close()
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("card_created", message["type"])
+	suite.Equal(float64(2), message["seq"])
+	suite.Equal("Missed", message["data"].(map[string]interface{})["title"])
+
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("synced", message["type"])
+	suite.Equal(float64(2), message["data"].(map[string]interface{})["seq"])
+	suite.Equal(float64(1), message["data"].(map[string]interface{})["replayed"])
+}
+
+// Test a client too far behind is told to reload the board
+func (suite *WebSocketTestSuite) TestReconnect_GapTooLarge() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	// Only the latest events are kept
+	database.DB.Create(&models.BoardEvent{BoardID: board.ID, Seq: 5000, Type: "card_updated", Data: []byte("{}")})
+
+	conn, err := DialWebSocket(board.ID, token, "since", "3")
+	suite.Require().NoError(err)
+	defer conn.Close()
+	message, err := ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("resync_required", message["type"])
+	suite.Equal(float64(5000), message["data"].(map[string]interface{})["seq"])
+
+	// since must be a sequence number
+	_, err = DialWebSocket(board.ID, token, "since", "latest")
+	suite.Error(err)
+}
+
+// Test a connection subscribes to boards, answers pings and runs commands
+func (suite *WebSocketTestSuite) TestCommands_SubscribePingMove() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	todo := Factory.CreateList(board.ID)
+	done := Factory.CreateList(board.ID)
+	card := Factory.CreateCard(todo.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	other := Factory.CreateUser()
+	otherBoard := Factory.CreateBoard(other.ID)
+	otherCard := Factory.CreateCard(Factory.CreateList(otherBoard.ID).ID)
+
+	conn, err := DialWebSocket(0, token)
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{"type": "ping", "id": "p1"}))
+	message, err := ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("pong", message["type"])
+	suite.Equal("p1", message["id"])
+
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{"type": "subscribe", "id": 1, "board_id": board.ID}))
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("synced", message["type"])
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("ack", message["type"])
+	suite.Equal(float64(1), message["id"])
+
+	// Only boards the user can view
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{"type": "subscribe", "id": 2, "board_id": otherBoard.ID}))
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("reject", message["type"])
+	suite.Equal(float64(403), message["status"])
+
+	// The ack and the broadcast to the board may arrive in either order
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{
+		"type": "move_card",
+		"id":   3,
+		"data": map[string]interface{}{"card_id": card.ID, "list_id": done.ID, "position": 0},
+	}))
+	received := map[string]map[string]interface{}{}
+	for i := 0; i < 2; i++ {
+		message, err = ReadWebSocket(conn)
+		suite.Require().NoError(err)
+		received[message["type"].(string)] = message
+	}
+	suite.Require().Contains(received, "ack")
+	suite.Require().Contains(received, "card_moved")
+	suite.Equal(float64(3), received["ack"]["id"])
+	suite.Equal(float64(done.ID), received["ack"]["data"].(map[string]interface{})["new_list_id"])
+	suite.Equal(float64(done.ID), received["card_moved"]["data"].(map[string]interface{})["new_list_id"])
+
+	var moved models.Card
+	database.DB.First(&moved, card.ID)
+	suite.Equal(done.ID, moved.ListID)
+
+	// Commands are checked like their REST requests
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{
+		"type": "add_comment",
+		"id":   4,
+		"data": map[string]interface{}{"card_id": otherCard.ID, "content": "Hello"},
+	}))
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("reject", message["type"])
+	suite.Equal(float64(4), message["id"])
+	suite.Equal(float64(403), message["status"])
+	suite.Equal("Access denied", message["error"])
+
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{"type": "archive_everything", "id": 5}))
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("reject", message["type"])
+	suite.Equal(float64(400), message["status"])
+
+	// No more board messages after unsubscribing
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{"type": "unsubscribe", "id": 6, "board_id": board.ID}))
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("ack", message["type"])
+	suite.Equal(201, POST("/cards", map[string]interface{}{"title": "Unseen", "list_id": todo.ID}, token).StatusCode)
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{"type": "ping", "id": "p2"}))
+	message, err = ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("pong", message["type"])
+}
+
+// Test presence counts users across tabs and follows the card each has open
+func (suite *WebSocketTestSuite) TestPresence_JoinFocusLeave() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	card := Factory.CreateCard(Factory.CreateList(board.ID).ID)
+	member := Factory.CreateUser()
+	Factory.CreateBoardMember(board.ID, member.ID, "member")
+	ownerToken := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+	memberToken := GenerateTestJWT(member.ID, member.Username, member.Email)
+
+	// readType skips messages until one of the given type
+	readType := func(conn *websocket.Conn, messageType string) map[string]interface{} {
+		for {
+			message, err := ReadWebSocket(conn)
+			suite.Require().NoError(err)
+			if message["type"] == messageType {
+				return message
+			}
+		}
+	}
+
+	watcher, err := DialWebSocket(board.ID, memberToken)
+	suite.Require().NoError(err)
+	defer watcher.Close()
+	readType(watcher, "presence_joined")
+
+	// Two tabs of the same user join once
+	first, err := DialWebSocket(board.ID, ownerToken)
+	suite.Require().NoError(err)
+	defer first.Close()
+	joined := readType(watcher, "presence_joined")
+	suite.Equal(float64(owner.ID), joined["data"].(map[string]interface{})["user_id"])
+	suite.Equal(owner.Username, joined["data"].(map[string]interface{})["username"])
+
+	second, err := DialWebSocket(board.ID, ownerToken)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(second.WriteJSON(map[string]interface{}{
+		"type":     "focus",
+		"board_id": board.ID,
+		"data":     map[string]interface{}{"card_id": card.ID},
+	}))
+	focus := readType(watcher, "presence_focus")
+	suite.Equal(float64(owner.ID), focus["data"].(map[string]interface{})["user_id"])
+	suite.Equal(float64(card.ID), focus["data"].(map[string]interface{})["card_id"])
+
+	response := GET(fmt.Sprintf("/boards/%d/presence", board.ID), ownerToken)
+	suite.Equal(200, response.StatusCode)
+	suite.Equal(float64(2), response.Body["count"])
+	viewers := response.Body["viewers"].([]interface{})
+	ownerViewer := viewers[0].(map[string]interface{})
+	suite.Equal(float64(owner.ID), ownerViewer["user_id"])
+	suite.Equal(float64(2), ownerViewer["connections"])
+	suite.Equal(float64(card.ID), ownerViewer["card_id"])
+	suite.Equal(false, ownerViewer["idle"])
+
+	// Closing one tab keeps the user, now shown with the other tab's card;
+	// closing the last one leaves
+	second.Close()
+	message, err := ReadWebSocket(watcher)
+	suite.Require().NoError(err)
+	suite.Equal("presence_focus", message["type"])
+	suite.Nil(message["data"].(map[string]interface{})["card_id"])
+
+	first.Close()
+	left := readType(watcher, "presence_left")
+	suite.Equal(float64(owner.ID), left["data"].(map[string]interface{})["user_id"])
+
+	response = GET(fmt.Sprintf("/boards/%d/presence", board.ID), memberToken)
+	suite.Equal(float64(1), response.Body["count"])
+
+	outsider := Factory.CreateUser()
+	suite.Equal(403, GET(fmt.Sprintf("/boards/%d/presence", board.ID), GenerateTestJWT(outsider.ID, outsider.Username, outsider.Email)).StatusCode)
+}
+
+// Test a member removed from a board stops receiving its broadcasts
+func (suite *WebSocketTestSuite) TestRemovedMember_LosesSubscription() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	list := Factory.CreateList(board.ID)
+	member := Factory.CreateUser()
+	membership := Factory.CreateBoardMember(board.ID, member.ID, "member")
+	ownerToken := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	conn, err := DialWebSocket(board.ID, GenerateTestJWT(member.ID, member.Username, member.Email))
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	suite.Equal(200, DELETE(fmt.Sprintf("/boards/%d/members/%d", board.ID, membership.ID), ownerToken).StatusCode)
+	for {
+		message, err := ReadWebSocket(conn)
+		suite.Require().NoError(err)
+		if message["type"] == "access_revoked" {
+			suite.Equal(float64(board.ID), message["board_id"])
+			break
+		}
+	}
+
+	suite.Equal(201, POST("/cards", map[string]interface{}{"title": "Unseen", "list_id": list.ID}, ownerToken).StatusCode)
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{"type": "ping", "id": "p1"}))
+	message, err := ReadWebSocket(conn)
+	suite.Require().NoError(err)
+	suite.Equal("pong", message["type"])
+}
+
+// Test a frame larger than the message size limit closes the connection
+func (suite *WebSocketTestSuite) TestOversizedFrame_ClosesConnection() {
+	owner := Factory.CreateUser()
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	conn, err := DialWebSocket(0, token)
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	suite.Require().NoError(conn.WriteJSON(map[string]interface{}{
+		"type": "add_comment",
+		"data": map[string]interface{}{"content": strings.Repeat("x", 64*1024)},
+	}))
+	_, err = ReadWebSocket(conn)
+	suite.Require().Error(err)
+	suite.True(websocket.IsCloseError(err, websocket.CloseMessageTooBig), "unexpected error: %v", err)
+}
+
+func TestWebSocketTestSuite(t *testing.T) {
+	suite.Run(t, new(WebSocketTestSuite))
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/database"
+	"github.com/ChukwukaRosemary23/flowboard-backend/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type WIPLimitTestSuite struct {
+	suite.Suite
+}
+
+// Test a hard limit refuses new and moved cards and logs the breach
+func (suite *WIPLimitTestSuite) TestHardLimit_RefusesCards() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	doing := Factory.CreateList(board.ID)
+	todo := Factory.CreateList(board.ID)
+	waiting := Factory.CreateCard(todo.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	response := PUT(fmt.Sprintf("/lists/%d", doing.ID), map[string]interface{}{
+		"wip_limit": 1,
+		"wip_mode":  "hard",
+	}, token)
+	suite.Equal(200, response.StatusCode)
+
+	response = POST("/cards", map[string]interface{}{"title": "First", "list_id": doing.ID}, token)
+	suite.Equal(201, response.StatusCode)
+	suite.Nil(response.Body["wip_warning"])
+
+	response = POST("/cards", map[string]interface{}{"title": "Second", "list_id": doing.ID}, token)
+	suite.Equal(409, response.StatusCode)
+	suite.Equal(float64(1), response.Body["card_count"])
+
+	response = POST(fmt.Sprintf("/cards/%d/move", waiting.ID), map[string]interface{}{
+		"list_id":  doing.ID,
+		"position": 0,
+	}, token)
+	suite.Equal(409, response.StatusCode)
+
+	var count int64
+	database.DB.Model(&models.Card{}).Where("list_id = ?", doing.ID).Count(&count)
+	suite.Equal(int64(1), count)
+	database.DB.Model(&models.Activity{}).Where("action = ? AND entity_id = ?", "exceeded_wip_limit", doing.ID).Count(&count)
+	suite.Equal(int64(2), count)
+}
+
+// Test a soft limit warns and the board's lists report their counts
+func (suite *WIPLimitTestSuite) TestSoftLimit_Warns() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	doing := Factory.CreateList(board.ID)
+	todo := Factory.CreateList(board.ID)
+	Factory.CreateCard(doing.ID)
+	card := Factory.CreateCard(todo.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	suite.Equal(200, PUT(fmt.Sprintf("/lists/%d", doing.ID), map[string]interface{}{"wip_limit": 1}, token).StatusCode)
+
+	response := POST(fmt.Sprintf("/cards/%d/move", card.ID), map[string]interface{}{
+		"list_id":  doing.ID,
+		"position": 0,
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	suite.NotNil(response.Body["wip_warning"])
+
+	response = GET(fmt.Sprintf("/lists/board/%d", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	for _, item := range response.Body["lists"].([]interface{}) {
+		list := item.(map[string]interface{})
+		if uint(list["id"].(float64)) == doing.ID {
+			suite.Equal(float64(2), list["card_count"])
+			suite.Equal(true, list["over_limit"])
+			suite.Equal("soft", list["wip_mode"])
+		} else {
+			suite.Equal(float64(0), list["card_count"])
+			suite.Nil(list["over_limit"])
+		}
+	}
+
+	// Removing the limit clears the flag
+	suite.Equal(200, PUT(fmt.Sprintf("/lists/%d", doing.ID), map[string]interface{}{"wip_limit": 0}, token).StatusCode)
+	response = GET(fmt.Sprintf("/boards/%d", board.ID), token)
+	suite.Equal(200, response.StatusCode)
+	for _, item := range response.Body["lists"].([]interface{}) {
+		list := item.(map[string]interface{})
+		suite.Nil(list["wip_limit"])
+		suite.Nil(list["over_limit"])
+	}
+}
+
+// Test a hard limit also refuses cards entering by bulk move, unarchive and
+// trash restore
+func (suite *WIPLimitTestSuite) TestHardLimit_OtherEntryPaths() {
+	owner := Factory.CreateUser()
+	board := Factory.CreateBoard(owner.ID)
+	doing := Factory.CreateList(board.ID)
+	todo := Factory.CreateList(board.ID)
+	archived := Factory.CreateCard(doing.ID)
+	trashed := Factory.CreateCard(doing.ID)
+	waiting := Factory.CreateCard(todo.ID)
+	token := GenerateTestJWT(owner.ID, owner.Username, owner.Email)
+
+	suite.Equal(200, DELETE(fmt.Sprintf("/cards/%d", trashed.ID), token).StatusCode)
+	response := POST("/cards/bulk", map[string]interface{}{
+		"card_ids":  []uint{archived.ID},
+		"operation": "archive",
+	}, token)
+	suite.Equal(200, response.StatusCode)
+	Factory.CreateCard(doing.ID)
+
+	suite.Equal(200, PUT(fmt.Sprintf("/lists/%d", doing.ID), map[string]interface{}{
+		"wip_limit": 1,
+		"wip_mode":  "hard",
+	}, token).StatusCode)
+
+	response = POST("/cards/bulk", map[string]interface{}{
+		"card_ids":  []uint{waiting.ID},
+		"operation": "move",
+		"list_id":   doing.ID,
+	}, token)
+	suite.Equal(409, response.StatusCode)
+
+	response = POST("/cards/bulk", map[string]interface{}{
+		"card_ids":  []uint{archived.ID},
+		"operation": "unarchive",
+	}, token)
+	suite.Equal(409, response.StatusCode)
+
+	response = POST(fmt.Sprintf("/boards/%d/trash/card/%d/restore", board.ID, trashed.ID), nil, token)
+	suite.Equal(409, response.StatusCode)
+
+	var count int64
+	database.DB.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL", doing.ID).Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func TestWIPLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(WIPLimitTestSuite))
+}